@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func runAdd(args []string) int {
+	var cf configFlags
+	var manifest, id, name string
+	var of outputFlags
+
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&manifest, "manifest", "", "manifest to put on the shelf")
+	fs.StringVar(&id, "id", "", "tape id (default: from the manifest file name)")
+	fs.StringVar(&name, "name", "", "tape name (default: the id)")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if manifest == "" {
+		fmt.Fprintln(os.Stderr, "add requires --manifest")
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	tape, err := config.TapeForManifest(cfg, manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "add tape: %v\n", err)
+		return 1
+	}
+	if id != "" {
+		tape.ID, tape.Name = id, id
+	}
+	if name != "" {
+		tape.Name = name
+	}
+	if err := config.AddTape(cfg.Path, cfg.ProjectRoot, tape); err != nil {
+		fmt.Fprintf(os.Stderr, "add tape: %v\n", err)
+		return 1
+	}
+	if of.json {
+		printJSON(tapeEntry(tape))
+		return 0
+	}
+	fmt.Printf("added %s (%s, manifest %s)\n", tape.ID, tape.Mode, tape.Manifest)
+	return 0
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/batch"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+type batchRowResult struct {
+	Row         int      `json:"row"`
+	Manifest    string   `json:"manifest"`
+	RunID       string   `json:"run_id,omitempty"`
+	ExitCode    int      `json:"exit_code"`
+	OutputPaths []string `json:"output_paths,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+type batchSummary struct {
+	BatchID   string           `json:"batch_id"`
+	TapeID    string           `json:"tape_id"`
+	Template  string           `json:"template"`
+	RowsFile  string           `json:"rows_file"`
+	DryRun    bool             `json:"dry_run"`
+	Timestamp time.Time        `json:"timestamp"`
+	Rows      []batchRowResult `json:"rows"`
+}
+
+func runBatch(args []string) int {
+	var cf configFlags
+	var tapeID, rowsPath string
+	var dryRun bool
+	var of outputFlags
+	var lf logFlags
+
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&tapeID, "tape", "", "tape id whose manifest is the template")
+	fs.StringVar(&rowsPath, "rows", "", "CSV (with header) or JSON array of rows")
+	fs.BoolVar(&dryRun, "dry-run", false, "write row manifests and records without rendering")
+	of.register(fs)
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if tapeID == "" || rowsPath == "" {
+		fmt.Fprintln(os.Stderr, "batch requires --tape and --rows")
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	tape, ok := findTape(cfg, tapeID)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown tape %q\n", tapeID)
+		return 2
+	}
+	if tape.Disabled {
+		fmt.Fprintf(os.Stderr, "tape %q is disabled\n", tape.ID)
+		return 2
+	}
+	if len(tape.PrimaryArgs) > 0 && !strings.HasPrefix(strings.TrimSpace(tape.PrimaryArgs[0]), "-") {
+		fmt.Fprintf(os.Stderr, "tape %q uses a full command in primary_args; batch needs the default render command so the row manifest is used\n", tape.ID)
+		return 2
+	}
+
+	templatePath, err := config.ResolveManifestPath(cfg.ProjectRoot, tape.Manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve manifest path: %v\n", err)
+		return 1
+	}
+	template, err := os.ReadFile(templatePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read template: %v\n", err)
+		return 1
+	}
+	if len(batch.Placeholders(string(template))) == 0 {
+		fmt.Fprintf(os.Stderr, "warning: %s has no {{column}} placeholders; every row renders the same manifest\n", templatePath)
+	}
+
+	rows, err := batch.LoadRows(rowsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rows: %v\n", err)
+		return 1
+	}
+
+	now := time.Now()
+	// The batch dir retires an id once created; the claim covers the
+	// window before that.
+	batchID, claim, err := runner.ReserveID(cfg.RunsDir, "batches", tape.ID, now, func(id string) bool {
+		_, err := os.Stat(filepath.Join(cfg.RunsDir, "batches", id))
+		return err == nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reserve batch id: %v\n", err)
+		return 1
+	}
+	defer runner.ReleaseID(claim)
+	summary := batchSummary{
+		BatchID:   batchID,
+		TapeID:    tape.ID,
+		Template:  templatePath,
+		RowsFile:  rowsPath,
+		DryRun:    dryRun,
+		Timestamp: now,
+	}
+	batchDir := filepath.Join(cfg.RunsDir, "batches", summary.BatchID)
+	if err := os.MkdirAll(batchDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "create batch dir: %v\n", err)
+		return 1
+	}
+	manifests, err := batch.WriteRowManifests(string(template), rows, templatePath, summary.BatchID, batchDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write row manifests: %v\n", err)
+		return 1
+	}
+	defer batch.RemoveRowManifests(manifests)
+
+	logger, closeLog, code := openLogger(lf, nil)
+	if logger == nil {
+		return code
+	}
+	defer closeLog.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := runner.New(nil)
+	run.SetLogger(logger)
+	failed := 0
+	for i, manifest := range manifests {
+		result := batchRowResult{Row: i + 1, Manifest: batch.ArchivePath(batchDir, templatePath, i+1), ExitCode: -1}
+		if ctx.Err() != nil {
+			result.Error = "batch canceled"
+			summary.Rows = append(summary.Rows, result)
+			failed++
+			continue
+		}
+
+		rowTape := tape
+		rowTape.Manifest = manifest
+		events, err := run.Start(ctx, runner.Request{Config: cfg, Tape: rowTape, Action: runner.ActionPrimary, DryRun: dryRun})
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			for event := range events {
+				if event.Type != runner.EventFinished {
+					continue
+				}
+				result.ExitCode = event.ExitCode
+				if event.Record != nil {
+					result.RunID = event.Record.RunID
+					result.OutputPaths = event.Record.OutputPaths
+				}
+				if event.ExitCode != 0 {
+					result.Error = event.Message
+				}
+			}
+		}
+		if result.ExitCode != 0 {
+			failed++
+		}
+		summary.Rows = append(summary.Rows, result)
+
+		status := "ok"
+		if result.ExitCode != 0 {
+			status = "failed: " + result.Error
+		}
+		fmt.Fprintf(of.progress(), "[batch %d/%d] %s %s\n", i+1, len(manifests), filepath.Base(result.Manifest), status)
+	}
+
+	buf, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(batchDir, "batch.json"), append(buf, '\n'), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write batch summary: %v\n", err)
+	}
+
+	if of.json {
+		printJSON(struct {
+			batchSummary
+			Dir string `json:"dir"`
+		}{summary, batchDir})
+	} else {
+		fmt.Printf("batch %s: %d/%d succeeded (%s)\n", summary.BatchID, len(manifests)-failed, len(manifests), batchDir)
+	}
+	return exitCode(failed == 0)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/bench"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func runBench(args []string) int {
+	var cf configFlags
+	var tapeID, quality, baselinePath string
+	var runs, warmup int
+	var of outputFlags
+	var lf logFlags
+
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&tapeID, "tape", "", "tape id from the config")
+	fs.IntVar(&runs, "runs", 3, "measured renders per preset")
+	fs.IntVar(&warmup, "warmup", 0, "unmeasured renders before each preset")
+	fs.StringVar(&quality, "quality", "", "comma-separated --quality presets to compare")
+	fs.StringVar(&baselinePath, "baseline", "", "earlier bench report to compare against")
+	of.register(fs)
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if tapeID == "" {
+		fmt.Fprintln(os.Stderr, "bench requires --tape")
+		return 2
+	}
+	if runs < 1 || warmup < 0 {
+		fmt.Fprintln(os.Stderr, "bench requires --runs >= 1 and --warmup >= 0")
+		return 2
+	}
+	var presets []string
+	for _, p := range strings.Split(quality, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			presets = append(presets, p)
+		}
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	tape, ok := findTape(cfg, tapeID)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown tape %q\n", tapeID)
+		return 2
+	}
+	if tape.Disabled {
+		fmt.Fprintf(os.Stderr, "tape %q is disabled\n", tape.ID)
+		return 2
+	}
+	var baseline *bench.Report
+	if baselinePath != "" {
+		var err error
+		if baseline, err = bench.ReadReport(baselinePath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	logger, closeLog, code := openLogger(lf, nil)
+	if logger == nil {
+		return code
+	}
+	defer closeLog.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := runner.New(nil)
+	run.SetLogger(logger)
+	report, err := bench.Run(ctx, bench.Options{Config: cfg, Runner: run, Tape: tape, Runs: runs, Warmup: warmup, Presets: presets, Out: of.progress()})
+	if report == nil {
+		printErr("bench", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bench stopped early: %v\n", err)
+	}
+
+	path := bench.ReportPath(cfg.RunsDir, report.ID)
+	if werr := bench.WriteReport(path, report); werr != nil {
+		fmt.Fprintln(os.Stderr, werr)
+	}
+	runner.ReleaseID(report.ClaimPath)
+	if of.json {
+		printJSON(report)
+	} else {
+		fmt.Println()
+		if err := report.WriteTable(os.Stdout, baseline); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Printf("\nbench %s (%s)\n", report.ID, path)
+	}
+
+	for _, s := range report.Summaries {
+		if s.Successes < s.Runs {
+			return 1
+		}
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/doctor"
+)
+
+func runDoctor(args []string) int {
+	var cf configFlags
+	var of outputFlags
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	cf.register(fs)
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+
+	report := doctor.Run(context.Background(), cfg, doctor.DefaultOptions())
+	if of.json {
+		printJSON(struct {
+			doctor.Report
+			OK      bool   `json:"ok"`
+			Summary string `json:"summary"`
+		}{report, report.OK(), report.Summary()})
+		return exitCode(report.OK())
+	}
+	width := 0
+	for _, c := range report.Checks {
+		width = max(width, len(c.Name))
+	}
+	for _, c := range report.Checks {
+		fmt.Printf("[%s] %-*s  %s\n", strings.ToUpper(string(c.Status)), width, c.Name, c.Detail)
+		if c.Status != doctor.StatusPass && c.Fix != "" {
+			fmt.Printf("       %*s  fix: %s\n", width, "", c.Fix)
+		}
+	}
+	fmt.Printf("\n%s\n", report.Summary())
+	return exitCode(report.OK())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func runDuplicate(args []string) int {
+	var cf configFlags
+	var d config.Duplicate
+	var of outputFlags
+
+	fs := flag.NewFlagSet("duplicate", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&d.SourceID, "tape", "", "tape id to copy")
+	fs.StringVar(&d.ID, "id", "", "id for the new tape (default: <tape>-copy)")
+	fs.StringVar(&d.Name, "name", "", "name for the new tape (default: \"<name> (copy)\")")
+	fs.StringVar(&d.Manifest, "manifest", "", "manifest path for the new tape (default: <id>.yaml next to the source)")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if d.SourceID == "" {
+		fmt.Fprintln(os.Stderr, "duplicate requires --tape")
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	if d.ID == "" {
+		d.ID = config.NextTapeID(cfg, d.SourceID)
+	}
+	manifest, err := config.DuplicateTape(cfg.Path, cfg.ProjectRoot, d)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "duplicate tape: %v\n", err)
+		return 1
+	}
+	if of.json {
+		printJSON(struct {
+			Source   string `json:"source"`
+			ID       string `json:"id"`
+			Manifest string `json:"manifest"`
+		}{d.SourceID, d.ID, manifest})
+		return 0
+	}
+	fmt.Printf("duplicated %s as %s (manifest %s)\n", d.SourceID, d.ID, manifest)
+	return 0
+}
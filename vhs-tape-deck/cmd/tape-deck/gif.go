@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/share"
+)
+
+func runGIF(args []string) int {
+	var cf configFlags
+	var tapeID, input, output, formatName, presetName string
+	var start, duration time.Duration
+	var of outputFlags
+
+	fs := flag.NewFlagSet("gif", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&tapeID, "tape", "", "convert the latest successful output of this tape")
+	fs.StringVar(&input, "input", "", "convert this file instead of a tape output")
+	fs.StringVar(&output, "output", "", "output path (default: next to the input)")
+	fs.StringVar(&formatName, "format", "gif", "gif or webp")
+	fs.StringVar(&presetName, "preset", share.DefaultPreset, "size preset: "+strings.Join(share.PresetNames(), ", "))
+	fs.DurationVar(&start, "start", 0, "start offset, e.g. 1.5s")
+	fs.DurationVar(&duration, "duration", 0, "length of the range, e.g. 3s (default: to the end)")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if (tapeID == "") == (input == "") {
+		fmt.Fprintln(os.Stderr, "gif requires exactly one of --tape or --input")
+		return 2
+	}
+	format, err := share.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	preset, ok := share.Presets[presetName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown preset %q (valid: %s)\n", presetName, strings.Join(share.PresetNames(), ", "))
+		return 2
+	}
+
+	recordPath := ""
+	if tapeID != "" {
+		cfg, code := loadConfig(cf)
+		if cfg == nil {
+			return code
+		}
+		if _, ok := findTape(cfg, tapeID); !ok {
+			fmt.Fprintf(os.Stderr, "unknown tape %q\n", tapeID)
+			return 2
+		}
+		record, err := latestOutput(cfg.RunsDir, tapeID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", tapeID, err)
+			return 1
+		}
+		input = record.OutputPaths[0]
+		recordPath = runner.RecordPath(cfg.RunsDir, record.RunID)
+	}
+	if output == "" {
+		output = share.DefaultOutput(input, format, preset)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var expected time.Duration
+	if info, err := probe.Run(ctx, "", input); err == nil {
+		expected = time.Duration(info.Duration * float64(time.Second))
+	}
+
+	opts := share.Options{Input: input, Output: output, Format: format, Preset: preset, Start: start, Duration: duration}
+	err = share.Convert(ctx, "", opts, expected, func(f float64) {
+		fmt.Fprintf(os.Stderr, "\r[gif] %3.0f%%", f*100)
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "convert: %v\n", err)
+		return 1
+	}
+	if recordPath != "" {
+		if err := runner.AddArtifact(recordPath, output); err != nil {
+			fmt.Fprintf(os.Stderr, "record artifact: %v\n", err)
+		}
+	}
+	if of.json {
+		printJSON(struct {
+			Input  string `json:"input"`
+			Output string `json:"output"`
+		}{input, output})
+		return 0
+	}
+	fmt.Printf("wrote %s\n", output)
+	return 0
+}
+
+func latestOutput(runsDir, tapeID string) (*runner.RunRecord, error) {
+	records, err := runner.LoadRunRecords(runsDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if r.TapeID != tapeID || r.ExitCode != 0 || r.DryRun || len(r.OutputPaths) == 0 {
+			continue
+		}
+		if _, err := os.Stat(r.OutputPaths[0]); err == nil {
+			return &r, nil
+		}
+	}
+	return nil, errors.New("no successful output found in run records")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func initConfig(args []string) int {
+	var configPath, preset string
+	var force, withManifests bool
+
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.StringVar(&configPath, "config", "", "path to config file (.yaml, .json or .toml)")
+	fs.StringVar(&preset, "preset", "", "starter tape set: "+strings.Join(config.PresetNames(), ", ")+" (default: ask, or "+config.DefaultPreset+" when not on a terminal)")
+	fs.BoolVar(&force, "force", false, "overwrite existing config")
+	fs.BoolVar(&withManifests, "with-manifests", false, "write a minimal manifest for every tape whose manifest does not exist")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if configPath == "" {
+		var err error
+		configPath, err = config.DefaultConfigPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "resolve config path: %v\n", err)
+			return 1
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve cwd: %v\n", err)
+		return 1
+	}
+
+	vcrBinary := ""
+	if preset == "" {
+		preset = config.DefaultPreset
+		if isTerminal(os.Stdin) {
+			answers := askInit(os.Stdin, os.Stdout, cwd)
+			preset, vcrBinary, cwd = answers.preset, answers.vcrBinary, answers.projectRoot
+			withManifests = withManifests || answers.manifests
+		}
+	}
+	cfg, err := config.PresetConfig(preset, cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init config: %v\n", err)
+		return 2
+	}
+	if vcrBinary != "" {
+		cfg.VCRBinary = vcrBinary
+	}
+
+	if err := config.WriteConfig(configPath, cwd, cfg, force); err != nil {
+		fmt.Fprintf(os.Stderr, "init config: %v\n", err)
+		return 1
+	}
+
+	abs, _ := filepath.Abs(configPath)
+	fmt.Printf("wrote %s starter config: %s\n", preset, abs)
+
+	if withManifests {
+		loaded, err := config.Load(configPath, cwd)
+		if err != nil {
+			printErr("load config", err)
+			return 1
+		}
+		written, err := config.WriteManifestStubs(loaded)
+		for _, path := range written {
+			fmt.Printf("wrote manifest stub: %s\n", path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "write manifest stubs: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+type initAnswers struct {
+	preset      string
+	vcrBinary   string
+	projectRoot string
+	manifests   bool
+}
+
+// askInit asks for the preset, vcr binary, project root and whether to
+// write manifest stubs. An empty
+// answer, or the end of input, keeps the default shown in brackets.
+func askInit(in io.Reader, out io.Writer, cwd string) initAnswers {
+	answers := initAnswers{preset: config.DefaultPreset, vcrBinary: "vcr", projectRoot: cwd}
+	scanner := bufio.NewScanner(in)
+	ask := func(question, def string) string {
+		fmt.Fprintf(out, "%s [%s]: ", question, def)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return def
+		}
+		if answer := strings.TrimSpace(scanner.Text()); answer != "" {
+			return answer
+		}
+		return def
+	}
+
+	fmt.Fprintln(out, "Starter presets:")
+	for i, p := range config.Presets {
+		fmt.Fprintf(out, "  %d) %-11s %s\n", i+1, p.Name, p.Description)
+	}
+	for {
+		answer := ask("Preset", "1")
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(config.Presets) {
+			answers.preset = config.Presets[n-1].Name
+			break
+		}
+		if _, err := config.PresetConfig(answer, cwd); err == nil {
+			answers.preset = answer
+			break
+		}
+		fmt.Fprintf(out, "unknown preset %q\n", answer)
+	}
+
+	if path, err := exec.LookPath("vcr"); err == nil {
+		answers.vcrBinary = path
+	} else {
+		fmt.Fprintln(out, "vcr was not found on PATH.")
+	}
+	answers.vcrBinary = ask("vcr binary", answers.vcrBinary)
+	answers.projectRoot = ask("Project root (manifest paths are relative to it)", cwd)
+	if !filepath.IsAbs(answers.projectRoot) && !strings.HasPrefix(answers.projectRoot, "~") {
+		answers.projectRoot = filepath.Join(cwd, answers.projectRoot)
+	}
+	answer := ask("Write starter manifests for tapes that have none? (y/n)", "y")
+	answers.manifests = strings.HasPrefix(strings.ToLower(answer), "y")
+	return answers
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
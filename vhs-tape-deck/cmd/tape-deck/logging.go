@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+)
+
+type logFlags struct {
+	level   string
+	json    bool
+	verbose bool
+}
+
+func (lf *logFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&lf.level, "log-level", "info", "log level: debug, info, warn, error")
+	fs.BoolVar(&lf.json, "log-json", false, "write log records as JSON")
+}
+
+func openLogger(lf logFlags, mirror func(string)) (*slog.Logger, io.Closer, int) {
+	level, err := logging.ParseLevel(lf.level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, 2
+	}
+	logger, closer, err := logging.New(logging.Options{
+		Name:   "tape-deck",
+		Level:  level,
+		JSON:   lf.json,
+		Mirror: mirror,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open log: %v\n", err)
+		return nil, nil, 1
+	}
+	return logger, closer, 0
+}
@@ -4,33 +4,88 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 
-	"vhs-tape-deck/internal/config"
-	"vhs-tape-deck/internal/ui"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/ui"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
 )
 
 func main() {
-	os.Exit(run(os.Args[1:]))
+	stopTracing, code := startTracing()
+	if stopTracing == nil {
+		os.Exit(code)
+	}
+	code = run(os.Args[1:])
+	stopTracing()
+	os.Exit(code)
 }
 
 func run(args []string) int {
 	if len(args) == 0 {
-		return runUI("")
+		return runUI(configFlags{}, logFlags{level: "info"}, false)
 	}
+	args = hoistJSON(args)
 
 	switch args[0] {
 	case "init":
 		return initConfig(args[1:])
 	case "run":
-		configPath := ""
+		var cf configFlags
+		var lf logFlags
 		fs := flag.NewFlagSet("run", flag.ContinueOnError)
-		fs.StringVar(&configPath, "config", "", "path to config yaml")
+		cf.register(fs)
+		lf.register(fs)
+		fs.BoolVar(&lf.verbose, "verbose", false, "mirror log records into the log pane")
+		plain := false
+		fs.BoolVar(&plain, "plain", false, "line-oriented output for screen readers and simple terminals")
+		if err := fs.Parse(args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		return runUI(cf, lf, plain)
+	case "batch":
+		return runBatch(args[1:])
+	case "pipeline":
+		return runPipeline(args[1:])
+	case "verify":
+		return runVerify(args[1:])
+	case "bench":
+		return runBench(args[1:])
+	case "serve":
+		return runServe(args[1:])
+	case "doctor":
+		return runDoctor(args[1:])
+	case "gif":
+		return runGIF(args[1:])
+	case "duplicate":
+		return runDuplicate(args[1:])
+	case "add":
+		return runAdd(args[1:])
+	case "report":
+		return runReport(args[1:])
+	case "status":
+		return runStatus(args[1:])
+	case "tapes":
+		return runTapes(args[1:])
+	case "validate":
+		return runValidate(args[1:])
+	case "version", "--version":
+		var of outputFlags
+		fs := flag.NewFlagSet("version", flag.ContinueOnError)
+		of.register(fs)
 		if err := fs.Parse(args[1:]); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 2
 		}
-		return runUI(configPath)
+		if of.json {
+			printJSON(struct {
+				Version string `json:"version"`
+			}{version.Deck})
+			return 0
+		}
+		fmt.Printf("tape-deck %s\n", version.Deck)
+		return 0
 	case "help", "-h", "--help":
 		printUsage()
 		return 0
@@ -40,84 +95,159 @@ func run(args []string) int {
 	}
 }
 
-func initConfig(args []string) int {
-	var configPath string
-	var force bool
+func runUI(cf configFlags, lf logFlags, plain bool) int {
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
 
-	fs := flag.NewFlagSet("init", flag.ContinueOnError)
-	fs.StringVar(&configPath, "config", "", "path to config yaml")
-	fs.BoolVar(&force, "force", false, "overwrite existing config")
-	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return 2
+	var lines chan string
+	var mirror func(string)
+	if lf.verbose {
+		lines = make(chan string, 256)
+		mirror = func(line string) {
+			select {
+			case lines <- line:
+			default:
+			}
+		}
+	}
+	logger, closeLog, code := openLogger(lf, mirror)
+	if logger == nil {
+		return code
 	}
+	defer closeLog.Close()
 
-	if configPath == "" {
-		var err error
-		configPath, err = config.DefaultConfigPath()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "resolve config path: %v\n", err)
+	opts := ui.Options{Logger: logger, LogLines: lines}
+	// A dumb terminal cannot draw the TUI, so fall back to plain lines.
+	if plain || os.Getenv("TERM") == "dumb" {
+		if err := ui.RunPlain(cfg, opts, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "run plain: %v\n", err)
 			return 1
 		}
+		return 0
 	}
-
-	cwd, err := os.Getwd()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "resolve cwd: %v\n", err)
+	if err := ui.Run(cfg, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "run UI: %v\n", err)
 		return 1
 	}
+	return 0
+}
 
-	if err := config.WriteStarterConfig(configPath, cwd, force); err != nil {
-		fmt.Fprintf(os.Stderr, "init config: %v\n", err)
-		return 1
-	}
+type configFlags struct {
+	path   string
+	strict bool
+}
 
-	abs, _ := filepath.Abs(configPath)
-	fmt.Printf("wrote starter config: %s\n", abs)
-	return 0
+func (cf *configFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.path, "config", "", "path to config file (.yaml, .json or .toml)")
+	fs.BoolVar(&cf.strict, "strict", false, "treat unknown config keys as errors instead of warnings")
+}
+
+func loadConfig(cf configFlags) (*config.Config, int) {
+	cfg, err := readConfig(cf)
+	if err != nil {
+		printErr("", err)
+		return nil, 1
+	}
+	for _, w := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return cfg, 0
 }
 
-func runUI(configPath string) int {
+// readConfig loads the config named by cf, or the default one, and returns
+// errors that say which file failed.
+func readConfig(cf configFlags) (*config.Config, error) {
+	configPath := cf.path
 	if configPath == "" {
 		var err error
 		configPath, err = config.DefaultConfigPath()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "resolve config path: %v\n", err)
-			return 1
+			return nil, fmt.Errorf("resolve config path: %w", err)
 		}
 	}
 
 	cwd, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "resolve cwd: %v\n", err)
-		return 1
+		return nil, fmt.Errorf("resolve cwd: %w", err)
 	}
 
-	cfg, err := config.Load(configPath, cwd)
+	load := config.Load
+	if cf.strict {
+		load = config.LoadStrict
+	}
+	cfg, err := load(configPath, cwd)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "load config (%s): %v\n", configPath, err)
-		fmt.Fprintln(os.Stderr, "tip: run `tape-deck init` to create a starter config")
-		return 1
+		return nil, fmt.Errorf("load config (%s): %w", configPath, err)
 	}
+	return cfg, nil
+}
 
-	if err := ui.Run(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "run UI: %v\n", err)
-		return 1
+// printErr reports err on stderr, after prefix when there is one, followed
+// by how to fix it when the error carries a hint.
+func printErr(prefix string, err error) {
+	if prefix != "" {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if h := hint.Of(err); h != "" {
+		fmt.Fprintf(os.Stderr, "hint: %s\n", h)
 	}
-	return 0
+}
+
+func findTape(cfg *config.Config, id string) (config.Tape, bool) {
+	for _, tape := range cfg.Tapes {
+		if tape.ID == id {
+			return tape, true
+		}
+	}
+	return config.Tape{}, false
 }
 
 func printUsage() {
 	fmt.Println(`tape-deck - VHS Tape Deck UI for VCR
 
 Usage:
-  tape-deck init [--config <path>] [--force]
-  tape-deck run [--config <path>]
+  tape-deck init [--preset <name>] [--with-manifests] [--config <path>] [--force]
+  tape-deck run [--config <path>] [--plain] [--verbose] [--log-level <level>] [--log-json]
+  tape-deck doctor [--config <path>] [--json]
+  tape-deck validate [--config <path>] [--json]
+  tape-deck tapes [--all] [--config <path>] [--json]
+  tape-deck batch --tape <id> --rows <rows.csv|rows.json> [--config <path>] [--dry-run] [--json]
+  tape-deck pipeline --id <id> [--config <path>] [--dry-run] [--json]
+  tape-deck verify --tape <id> [--config <path>] [--dry-run] [--json]
+  tape-deck bench --tape <id> [--runs <n>] [--warmup <n>] [--quality <a,b>] [--baseline <report.json>] [--json] [--config <path>]
+  tape-deck serve [--config <path>] [--dry-run] [--metrics-addr <host:port>]
+  tape-deck duplicate --tape <id> [--id <new-id>] [--name <name>] [--manifest <path>] [--config <path>] [--json]
+  tape-deck add --manifest <path> [--id <id>] [--name <name>] [--config <path>] [--json]
+  tape-deck gif (--tape <id> | --input <file>) [--format gif|webp] [--preset small|medium|large] [--start <dur>] [--duration <dur>] [--json]
+  tape-deck report [--since <YYYY-MM-DD>] [--until <YYYY-MM-DD>] [--tape <id>] [--html <out.html> | --json] [--config <path>]
+  tape-deck status [--format <template> | --json] [--config <path>]
+  tape-deck version [--json]
   tape-deck
 
 Commands:
-  init    Write a starter config with five tapes
-  run     Start the Tape Deck UI
-
-If no command is provided, run is implied.`)
+  init       Write a starter config, asking for a preset when run in a terminal
+  run        Start the Tape Deck UI (--plain for timestamped status lines instead)
+  doctor     Check vcr, ffmpeg, GPU backend, LLM backends, dirs and manifests
+  validate   Load the config and report whether it is valid
+  tapes      List the tapes on the shelf
+  batch      Render one output per row, substituting {{column}} placeholders in the tape manifest
+  pipeline   Run a configured chain of tapes and commands, passing outputs between steps
+  verify     Render a tape's golden frames and compare them with the reference PNGs
+  bench      Render a tape repeatedly and report duration and frames/sec per quality preset
+  serve      Stay running and fire the config's cron schedules of tapes and pipelines
+  duplicate  Copy a tape (and its manifest) under a new id
+  add        Put an existing manifest, e.g. one an agent generated, on the shelf as a new tape
+  gif        Convert a tape's latest output (or a time range of it) into a shareable GIF or WebP
+  report     List runs in a date range, or write them to a self-contained HTML review gallery
+  status     Print a one-line summary of the running or latest run, for tmux and shell prompts
+  version    Print the tape deck version
+
+If no command is provided, run is implied.
+Commands that load the config accept --strict to reject unknown keys instead of warning.
+Commands that print a result accept --json, also given before the command (tape-deck --json doctor).
+Logs are written to ~/.vcr/logs/tape-deck.log.`)
 }
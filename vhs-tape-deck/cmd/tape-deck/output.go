@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// outputFlags selects machine-readable output. With --json a command prints
+// exactly one JSON document on stdout and moves progress lines to stderr;
+// errors stay on stderr and the exit code is unchanged.
+type outputFlags struct {
+	json bool
+}
+
+func (of *outputFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&of.json, "json", false, "print the result as JSON on stdout")
+}
+
+// progress is where a command writes its running commentary.
+func (of outputFlags) progress() io.Writer {
+	if of.json {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func printJSON(v any) {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encode JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(buf))
+}
+
+func exitCode(ok bool) int {
+	if ok {
+		return 0
+	}
+	return 1
+}
+
+// hoistJSON lets --json come before the command, as in
+// `tape-deck --json doctor`, by moving it after the command name.
+func hoistJSON(args []string) []string {
+	if len(args) < 2 || (args[0] != "--json" && args[0] != "-json") {
+		return args
+	}
+	return append([]string{args[1], "--json"}, args[2:]...)
+}
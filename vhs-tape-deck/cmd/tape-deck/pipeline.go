@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/pipeline"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func runPipeline(args []string) int {
+	var cf configFlags
+	var id string
+	var dryRun bool
+	var of outputFlags
+	var lf logFlags
+
+	fs := flag.NewFlagSet("pipeline", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&id, "id", "", "pipeline id from the config")
+	fs.BoolVar(&dryRun, "dry-run", false, "write records without running any step")
+	of.register(fs)
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if id == "" {
+		fmt.Fprintln(os.Stderr, "pipeline requires --id")
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	p, ok := pipeline.Find(cfg, id)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown pipeline %q\n", id)
+		return 2
+	}
+
+	logger, closeLog, code := openLogger(lf, nil)
+	if logger == nil {
+		return code
+	}
+	defer closeLog.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := runner.New(nil)
+	run.SetLogger(logger)
+	events, err := pipeline.Start(ctx, run, pipeline.Request{Config: cfg, Pipeline: p, DryRun: dryRun})
+	if err != nil {
+		printErr("start pipeline", err)
+		return 1
+	}
+
+	out := of.progress()
+	var record *pipeline.Record
+	for event := range events {
+		switch event.Type {
+		case pipeline.EventStepStarted:
+			fmt.Fprintf(out, "[%3.0f%%] %s started\n", event.Overall*100, event.Step)
+		case pipeline.EventLog:
+			fmt.Fprintf(out, "  %s %s\n", event.Step, event.Message)
+		case pipeline.EventStepRetrying:
+			fmt.Fprintf(out, "[%3.0f%%] %s %s\n", event.Overall*100, event.Step, event.Message)
+		case pipeline.EventStepFinished:
+			status := event.Message
+			if event.Result != nil && event.Result.Ignored {
+				status += " (continue_on_error)"
+			}
+			if event.Result != nil && event.Result.Error != "" {
+				status += ": " + event.Result.Error
+			}
+			fmt.Fprintf(out, "[%3.0f%%] %s %s\n", event.Overall*100, event.Step, status)
+		case pipeline.EventFinished:
+			record = event.Record
+			if event.RecordErr != nil {
+				fmt.Fprintf(os.Stderr, "write pipeline record: %v\n", event.RecordErr)
+			}
+		}
+	}
+
+	path := pipeline.RecordPath(cfg.RunsDir, record.RunID)
+	if of.json {
+		printJSON(struct {
+			*pipeline.Record
+			RecordPath string `json:"record_path"`
+		}{record, path})
+	} else {
+		fmt.Printf("pipeline %s: %s (%s)\n", record.RunID, record.Status, path)
+	}
+	return exitCode(record.Status == runner.StatusSuccess)
+}
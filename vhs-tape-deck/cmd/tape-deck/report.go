@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/report"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func runReport(args []string) int {
+	var cf configFlags
+	var tapeID, since, until, htmlPath string
+	var of outputFlags
+
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&tapeID, "tape", "", "only runs of this tape")
+	fs.StringVar(&since, "since", "", "first day to include, YYYY-MM-DD")
+	fs.StringVar(&until, "until", "", "last day to include, YYYY-MM-DD")
+	fs.StringVar(&htmlPath, "html", "", "write a self-contained HTML gallery to this path")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if of.json && htmlPath != "" {
+		fmt.Fprintln(os.Stderr, "report takes --json or --html, not both")
+		return 2
+	}
+	var opts report.Options
+	var err error
+	if opts.Since, err = parseDay(since); err != nil {
+		fmt.Fprintf(os.Stderr, "--since: %v\n", err)
+		return 2
+	}
+	if opts.Until, err = parseDay(until); err != nil {
+		fmt.Fprintf(os.Stderr, "--until: %v\n", err)
+		return 2
+	}
+	if !opts.Until.IsZero() {
+		opts.Until = opts.Until.AddDate(0, 0, 1)
+	}
+	opts.TapeID = tapeID
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	if tapeID != "" {
+		if _, ok := findTape(cfg, tapeID); !ok {
+			fmt.Fprintf(os.Stderr, "unknown tape %q\n", tapeID)
+			return 2
+		}
+	}
+	records, err := runner.LoadRunRecords(cfg.RunsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load run records: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	selected := report.Select(records, opts)
+	if of.json {
+		for i := range selected {
+			selected[i].Status = report.StatusOf(selected[i])
+		}
+		printJSON(struct {
+			Runs []runner.RunRecord `json:"runs"`
+		}{append([]runner.RunRecord{}, selected...)})
+		return 0
+	}
+	if htmlPath == "" {
+		rep := &report.Report{Options: opts}
+		for _, r := range selected {
+			rep.Entries = append(rep.Entries, report.Entry{Record: r, Status: report.StatusOf(r)})
+		}
+		if err := rep.WriteText(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "write report: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	rep := report.Build(ctx, cfg, selected, opts)
+	f, err := os.Create(htmlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write report: %v\n", err)
+		return 1
+	}
+	if err := rep.WriteHTML(f); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "write report: %v\n", err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "write report: %v\n", err)
+		return 1
+	}
+	fmt.Printf("wrote %s (%d runs)\n", htmlPath, len(rep.Entries))
+	return 0
+}
+
+// parseDay reads a local YYYY-MM-DD date; empty means no bound.
+func parseDay(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", v, time.Local)
+}
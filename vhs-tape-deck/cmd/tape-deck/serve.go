@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/daemon"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func runServe(args []string) int {
+	var cf configFlags
+	var dryRun bool
+	var metricsAddr string
+	var lf logFlags
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	cf.register(fs)
+	fs.BoolVar(&dryRun, "dry-run", false, "turn every scheduled run into a dry run")
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address, e.g. :9464")
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	if len(cfg.Schedules) == 0 {
+		fmt.Fprintln(os.Stderr, "no schedules in config; add a schedules: section")
+		return 2
+	}
+
+	logger, closeLog, code := openLogger(lf, nil)
+	if logger == nil {
+		return code
+	}
+	defer closeLog.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	run := runner.New(nil)
+	run.SetLogger(logger)
+	d := daemon.New(daemon.Options{Config: cfg, Runner: run, Logger: logger, Out: os.Stdout, DryRun: dryRun})
+	if metricsAddr != "" {
+		ln, err := net.Listen("tcp", metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "serve: metrics: %v\n", err)
+			return 1
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", d.Metrics())
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("metrics server stopped", "err", err)
+			}
+		}()
+		defer server.Close()
+		fmt.Printf("[serve] metrics on http://%s/metrics\n", ln.Addr())
+	}
+	fmt.Printf("[serve] %d schedule(s) loaded from %s; Ctrl+C to stop\n", len(cfg.Schedules), cfg.Path)
+	if err := d.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return 1
+	}
+	fmt.Println("[serve] stopped")
+	return 0
+}
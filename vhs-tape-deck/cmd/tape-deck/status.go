@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/status"
+)
+
+func runStatus(args []string) int {
+	var cf configFlags
+	var format string
+	var of outputFlags
+
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&format, "format", status.DefaultFormat, "Go template over the run summary")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	s, err := status.Read(cfg.RunsDir, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "status: %v\n", err)
+		return 1
+	}
+	if of.json {
+		printJSON(s)
+		return 0
+	}
+	if err := status.Write(os.Stdout, s, format); err != nil {
+		fmt.Fprintf(os.Stderr, "status: %v\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+// tapeInfo is a tape as listed by `tapes --json` and `add --json`.
+type tapeInfo struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Mode     config.Mode `json:"mode"`
+	Manifest string      `json:"manifest"`
+	Notes    string      `json:"notes,omitempty"`
+	Disabled bool        `json:"disabled,omitempty"`
+}
+
+func tapeEntry(t config.Tape) tapeInfo {
+	return tapeInfo{ID: t.ID, Name: t.Name, Mode: t.Mode, Manifest: t.Manifest, Notes: t.Notes, Disabled: t.Disabled}
+}
+
+func runTapes(args []string) int {
+	var cf configFlags
+	var of outputFlags
+	var all bool
+
+	fs := flag.NewFlagSet("tapes", flag.ContinueOnError)
+	cf.register(fs)
+	fs.BoolVar(&all, "all", false, "include retired (disabled) tapes")
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	tapes := []tapeInfo{}
+	for _, t := range cfg.Tapes {
+		if all || !t.Disabled {
+			tapes = append(tapes, tapeEntry(t))
+		}
+	}
+	if of.json {
+		printJSON(struct {
+			Tapes []tapeInfo `json:"tapes"`
+		}{tapes})
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tMODE\tMANIFEST")
+	for _, t := range tapes {
+		name := t.Name
+		if t.Disabled {
+			name += " (retired)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.ID, name, t.Mode, t.Manifest)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "tapes: %v\n", err)
+		return 1
+	}
+	return 0
+}
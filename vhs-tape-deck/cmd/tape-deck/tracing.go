@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/trace"
+)
+
+// startTracing installs an OTLP span exporter when the standard
+// OTEL_EXPORTER_OTLP_* variables name an endpoint. The returned func sends
+// the spans still queued; it is nil when the variables are invalid.
+func startTracing() (func(), int) {
+	exporter, err := trace.FromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tracing: %v\n", err)
+		return nil, 2
+	}
+	if exporter == nil {
+		return func() {}, 0
+	}
+	trace.Install(exporter)
+	return func() {
+		trace.Install(nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := exporter.Shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "tracing: %v\n", err)
+		}
+	}, 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+)
+
+type validateResult struct {
+	OK        bool     `json:"ok"`
+	Path      string   `json:"path,omitempty"`
+	Tapes     int      `json:"tapes"`
+	Pipelines int      `json:"pipelines"`
+	Schedules int      `json:"schedules"`
+	Warnings  []string `json:"warnings"`
+	Error     string   `json:"error,omitempty"`
+	Hint      string   `json:"hint,omitempty"`
+}
+
+// runValidate loads the config and reports whether it is valid. Unlike
+// other commands, with --json a broken config is part of the result rather
+// than an error on stderr, so editors can show it.
+func runValidate(args []string) int {
+	var cf configFlags
+	var of outputFlags
+
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	cf.register(fs)
+	of.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	result := validateResult{Warnings: []string{}}
+	cfg, err := readConfig(cf)
+	if err != nil {
+		result.Error, result.Hint = err.Error(), hint.Of(err)
+	} else {
+		result.OK = true
+		result.Path = cfg.Path
+		result.Tapes, result.Pipelines, result.Schedules = len(cfg.Tapes), len(cfg.Pipelines), len(cfg.Schedules)
+		result.Warnings = append(result.Warnings, cfg.Warnings...)
+	}
+	if of.json {
+		printJSON(result)
+		return exitCode(result.OK)
+	}
+
+	if err != nil {
+		printErr("", err)
+		return 1
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	fmt.Printf("%s: ok (%d tapes, %d pipelines, %d schedules)\n", result.Path, result.Tapes, result.Pipelines, result.Schedules)
+	return 0
+}
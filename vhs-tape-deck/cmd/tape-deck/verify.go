@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func runVerify(args []string) int {
+	var cf configFlags
+	var tapeID string
+	var dryRun bool
+	var of outputFlags
+	var lf logFlags
+
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	cf.register(fs)
+	fs.StringVar(&tapeID, "tape", "", "tape id from the config")
+	fs.BoolVar(&dryRun, "dry-run", false, "write the record without rendering or comparing")
+	of.register(fs)
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if tapeID == "" {
+		fmt.Fprintln(os.Stderr, "verify requires --tape")
+		return 2
+	}
+
+	cfg, code := loadConfig(cf)
+	if cfg == nil {
+		return code
+	}
+	tape, ok := findTape(cfg, tapeID)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown tape %q\n", tapeID)
+		return 2
+	}
+	if tape.Disabled {
+		fmt.Fprintf(os.Stderr, "tape %q is disabled\n", tape.ID)
+		return 2
+	}
+
+	logger, closeLog, code := openLogger(lf, nil)
+	if logger == nil {
+		return code
+	}
+	defer closeLog.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := runner.New(nil)
+	run.SetLogger(logger)
+	events, err := run.Start(ctx, runner.Request{Config: cfg, Tape: tape, Action: runner.ActionVerify, DryRun: dryRun})
+	if err != nil {
+		printErr("start verify", err)
+		return 1
+	}
+
+	var finished runner.Event
+	for event := range events {
+		switch event.Type {
+		case runner.EventLog, runner.EventStalled:
+			fmt.Fprintln(of.progress(), event.Message)
+		case runner.EventFinished:
+			finished = event
+			if event.RecordErr != nil {
+				fmt.Fprintf(os.Stderr, "write run record: %v\n", event.RecordErr)
+			}
+		}
+	}
+
+	record := finished.Record
+	if of.json {
+		printJSON(runSummary{record, finished.Message, finished.Hint, runner.RecordPath(cfg.RunsDir, record.RunID)})
+		return exitCode(finished.ExitCode == 0)
+	}
+	fmt.Printf("verify %s: %s (%s)\n", record.RunID, finished.Message, runner.RecordPath(cfg.RunsDir, record.RunID))
+	if finished.Hint != "" {
+		fmt.Printf("hint: %s\n", finished.Hint)
+	}
+	return exitCode(finished.ExitCode == 0)
+}
+
+// runSummary is the --json result of a single run: its record plus the
+// closing message and where the record was written.
+type runSummary struct {
+	*runner.RunRecord
+	Message    string `json:"message"`
+	Hint       string `json:"hint,omitempty"`
+	RecordPath string `json:"record_path"`
+}
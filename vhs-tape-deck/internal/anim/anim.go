@@ -1,7 +1,6 @@
 package anim
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -15,9 +14,61 @@ const (
 	StateFailed   State = "failed"
 )
 
+// GlyphSet selects how shelf status indicators are drawn. Only GlyphsDots
+// relies on color; the other sets give every state its own shape.
+type GlyphSet string
+
+const (
+	GlyphsDots    GlyphSet = "dots"
+	GlyphsUnicode GlyphSet = "unicode"
+	GlyphsASCII   GlyphSet = "ascii"
+)
+
+var statusGlyphs = map[GlyphSet]map[State]string{
+	GlyphsUnicode: {StateIdle: "○", StateInserted: "◆", StateRunning: "▶", StateSuccess: "✓", StateFailed: "✗"},
+	GlyphsASCII:   {StateIdle: "o", StateInserted: "*", StateRunning: ">", StateSuccess: "+", StateFailed: "x"},
+}
+
+func StatusGlyph(state State, set GlyphSet) string {
+	glyphs, ok := statusGlyphs[set]
+	if !ok {
+		return "●"
+	}
+	if g, ok := glyphs[state]; ok {
+		return g
+	}
+	return glyphs[StateIdle]
+}
+
+// Intensity scales how much of the cassette moves.
+type Intensity string
+
+const (
+	IntensityFull Intensity = "full"
+	// IntensityReduced keeps inserts and ejects but drops shimmer, reel
+	// spin and the failure shake.
+	IntensityReduced Intensity = "reduced"
+	// IntensityOff draws every tape at rest.
+	IntensityOff Intensity = "off"
+)
+
 type Options struct {
 	LabelStyle    string
 	ShellColorway string
+	// Pack is the cassette art; nil draws the default pack.
+	Pack *Pack
+	// Done and Total are frames rendered so far. While Total is set the
+	// reels turn a step per frame and a bar shows the tape left to wind;
+	// otherwise the reels follow the tick count.
+	Done  int
+	Total int
+	// Color tints the shell by colorway and the reels and badge by state.
+	Color bool
+	// Slot moves the tape in and out. Without one, an inserted tape
+	// slides in over the first six ticks.
+	Slot *Slot
+	// Intensity is how much moves; empty means full.
+	Intensity Intensity
 }
 
 type CassetteAnimator struct{}
@@ -31,57 +82,90 @@ func (a CassetteAnimator) Render(label, tapeID string, tickCount int, state Stat
 		tickCount = 0
 	}
 
+	pack := opts.Pack
+	if pack == nil {
+		pack = defaultPack()
+	}
 	shellChar := shellForColorway(opts.ShellColorway)
-	reelLeft, reelRight := reelGlyphs(tickCount, state)
 	labelText := styleLabel(label, opts.LabelStyle)
 	idText := styleLabel(tapeID, "clean")
-	status := statusBadge(state, isInserted)
+	badge := statusBadge(state, isInserted)
 
-	offset := 6
+	offset := slideDistance
 	if isInserted {
 		offset = 0
-		if tickCount < 6 {
-			offset = 6 - tickCount
+	}
+	switch {
+	case opts.Intensity == IntensityOff:
+	case opts.Slot != nil:
+		if opts.Intensity != IntensityReduced || opts.Slot.motion != MotionShake {
+			offset = opts.Slot.Offset(tapeID, isInserted, tickCount)
 		}
+	case isInserted:
+		offset = max(0, slideDistance-tickCount)
 	}
 	indent := strings.Repeat(" ", offset)
 
+	pal := newPalette(opts, state, isInserted)
+	slotWidth := pack.Width() + 2
 	lines := []string{
-		"+-------------------------------+",
-		"|      VHS SLOT [====]          |",
-		"+-------------------------------+",
-		indent + "+---------------------------+",
-		indent + "|" + strings.Repeat(shellChar, 27) + "|",
-		indent + fmt.Sprintf("|  (%s)       [%s]      (%s) |", reelLeft, centerText(labelText, 7), reelRight),
-		indent + fmt.Sprintf("|  ID:%s             |", centerText(idText, 20)),
-		indent + "|" + strings.Repeat(shellChar, 27) + "|",
-		indent + "+---------------------------+",
-		indent + "   " + status,
-	}
-
-	if state != StateRunning {
-		for i := range lines {
-			if i >= 4 && i <= 7 {
-				lines[i] = shimmer(lines[i], tickCount, i)
-			}
+		pal.art("+" + strings.Repeat("-", slotWidth) + "+"),
+		pal.art("|" + slotTitle(slotWidth) + "|"),
+		pal.art("+" + strings.Repeat("-", slotWidth) + "+"),
+	}
+	spin := tickCount
+	status := pal.paint(pal.state, badge)
+	if opts.Total > 0 {
+		spin = max(0, opts.Done)
+		if state == StateRunning {
+			status += " " + pal.paint(pal.state, tapeBar(opts.Done, opts.Total, max(4, pack.Width()-len(badge)-6)))
+		}
+	}
+	still := opts.Intensity == IntensityReduced || opts.Intensity == IntensityOff
+	reel := func(n int) string {
+		if still {
+			return reelGlyph(0, state, 0)
 		}
+		return reelGlyph(spin, state, n)
+	}
+	for _, line := range pack.draw(shellChar, reel, labelText, idText, tickCount, state != StateRunning && !still, pal) {
+		lines = append(lines, indent+line)
 	}
+	lines = append(lines, indent+"   "+status)
 
 	return strings.Join(lines, "\n")
 }
 
-func reelGlyphs(tick int, state State) (string, string) {
-	if state == StateRunning {
-		frames := []string{"|", "/", "-", "\\"}
-		return frames[tick%len(frames)], frames[(tick+2)%len(frames)]
-	}
-	if state == StateSuccess {
-		return "*", "*"
+// tapeBar shows the tape still on the supply reel, shrinking as frames
+// wind onto the take-up reel.
+func tapeBar(done, total, width int) string {
+	left := width - int(float64(min(max(done, 0), total))/float64(total)*float64(width)+0.5)
+	return "[" + strings.Repeat("=", left) + strings.Repeat(".", width-left) + "]"
+}
+
+// slotTitle labels the deck slot, inset like the original 31-column slot.
+func slotTitle(width int) string {
+	const title = "VHS SLOT [====]"
+	if width < len(title) {
+		return centerText("SLOT", width)
 	}
-	if state == StateFailed {
-		return "x", "x"
+	left := min(6, (width-len(title))/2)
+	return strings.Repeat(" ", left) + title + strings.Repeat(" ", width-left-len(title))
+}
+
+// reelGlyph draws the nth reel of a pack. Neighbouring reels spin half a
+// turn apart.
+func reelGlyph(tick int, state State, n int) string {
+	switch state {
+	case StateRunning:
+		frames := []string{"|", "/", "-", "\\"}
+		return frames[(tick+2*n)%len(frames)]
+	case StateSuccess:
+		return "*"
+	case StateFailed:
+		return "x"
 	}
-	return "o", "o"
+	return "o"
 }
 
 func shellForColorway(colorway string) string {
@@ -149,7 +233,7 @@ func shimmer(line string, tick, row int) string {
 		return line
 	}
 	b := []byte(line)
-	if b[idx] == '#' || b[idx] == '=' || b[idx] == '.' {
+	if b[idx] == '#' {
 		b[idx] = '~'
 	}
 	return string(b)
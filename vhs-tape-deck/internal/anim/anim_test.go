@@ -50,3 +50,46 @@ func TestRenderIdleEjected(t *testing.T) {
 		t.Fatalf("expected tape body with ejected offset, got:\n%s", frame)
 	}
 }
+
+func TestStatusGlyphsAreDistinct(t *testing.T) {
+	t.Parallel()
+
+	states := []State{StateIdle, StateInserted, StateRunning, StateSuccess, StateFailed}
+	for _, set := range []GlyphSet{GlyphsUnicode, GlyphsASCII} {
+		seen := map[string]State{}
+		for _, state := range states {
+			g := StatusGlyph(state, set)
+			if prev, ok := seen[g]; ok {
+				t.Fatalf("%s: %s and %s share glyph %q", set, prev, state, g)
+			}
+			seen[g] = state
+		}
+	}
+	if StatusGlyph(StateFailed, GlyphsASCII) != "x" || StatusGlyph("", GlyphsUnicode) != "○" {
+		t.Fatalf("unexpected glyphs")
+	}
+	if StatusGlyph(StateSuccess, GlyphsDots) != "●" {
+		t.Fatalf("expected dots to keep the colored dot")
+	}
+}
+
+func TestRenderFollowsFrameProgress(t *testing.T) {
+	t.Parallel()
+
+	a := NewCassetteAnimator()
+	start := a.Render("Alpha", "alpha", 9, StateRunning, true, Options{Done: 0, Total: 100})
+	if !strings.Contains(start, "(|)") || !strings.Contains(start, "[ PLAY ] [===============]") {
+		t.Fatalf("expected full supply reel at frame 0, got:\n%s", start)
+	}
+	half := a.Render("Alpha", "alpha", 9, StateRunning, true, Options{Done: 53, Total: 100})
+	if !strings.Contains(half, "(/)") || !strings.Contains(half, "[=======........]") {
+		t.Fatalf("expected half-wound tape at frame 53, got:\n%s", half)
+	}
+	if a.Render("Alpha", "alpha", 10, StateRunning, true, Options{Done: 53, Total: 100}) != half {
+		t.Fatalf("expected reels to ignore the tick count while frames are known")
+	}
+	done := a.Render("Alpha", "alpha", 9, StateSuccess, true, Options{Done: 100, Total: 100})
+	if !strings.HasSuffix(done, "[ DONE ]") {
+		t.Fatalf("expected no tape bar after the run, got:\n%s", done)
+	}
+}
@@ -0,0 +1,76 @@
+package anim
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type cell int
+
+const (
+	artCell cell = iota
+	shellCell
+	reelCell
+)
+
+// palette tints the parts of a frame. Adaptive colors keep a black shell
+// visible on dark terminals; the zero value leaves the frame plain.
+type palette struct {
+	on    bool
+	cells [3]lipgloss.Style
+	label lipgloss.Style
+	state lipgloss.Style
+}
+
+var shellColors = map[string]lipgloss.AdaptiveColor{
+	"black": {Light: "238", Dark: "242"},
+	"gray":  {Light: "246", Dark: "250"},
+	"clear": {Light: "31", Dark: "117"},
+}
+
+var stateColors = map[State]lipgloss.Color{
+	StateRunning:  "214",
+	StateSuccess:  "42",
+	StateFailed:   "196",
+	StateInserted: "81",
+}
+
+func newPalette(opts Options, state State, inserted bool) palette {
+	if !opts.Color {
+		return palette{}
+	}
+	shell, ok := shellColors[strings.ToLower(strings.TrimSpace(opts.ShellColorway))]
+	if !ok {
+		shell = shellColors["black"]
+	}
+	color, ok := stateColors[state]
+	if !ok {
+		color = "245"
+		if inserted {
+			color = stateColors[StateInserted]
+		}
+	}
+	art := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "240", Dark: "252"})
+	return palette{
+		on: true,
+		cells: [3]lipgloss.Style{
+			artCell:   art,
+			shellCell: lipgloss.NewStyle().Foreground(shell),
+			reelCell:  lipgloss.NewStyle().Foreground(color).Bold(true),
+		},
+		label: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "235", Dark: "230"}).Bold(true),
+		state: lipgloss.NewStyle().Foreground(color).Bold(true),
+	}
+}
+
+func (p palette) paint(style lipgloss.Style, text string) string {
+	if !p.on || text == "" {
+		return text
+	}
+	return style.Render(text)
+}
+
+func (p palette) art(text string) string {
+	return p.paint(p.cells[artCell], text)
+}
@@ -0,0 +1,39 @@
+package anim
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var sgr = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Not parallel: it switches the default renderer to 256 colors.
+func TestRenderColor(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	defer lipgloss.SetColorProfile(profile)
+
+	a := NewCassetteAnimator()
+	opts := Options{ShellColorway: "clear", Done: 10, Total: 40}
+	mono := a.Render("Alpha", "alpha", 7, StateRunning, true, opts)
+	if strings.Contains(mono, "\x1b") {
+		t.Fatalf("expected plain ASCII without Color, got %q", mono)
+	}
+
+	opts.Color = true
+	color := a.Render("Alpha", "alpha", 7, StateRunning, true, opts)
+	if sgr.ReplaceAllString(color, "") != mono {
+		t.Fatalf("expected the same art under the colors:\n%s\nvs\n%s", color, mono)
+	}
+	if !strings.Contains(color, "38;5;214") || !strings.Contains(color, "38;5;117") {
+		t.Fatalf("expected running and clear-shell colors, got %q", color)
+	}
+	failed := a.Render("Alpha", "alpha", 7, StateFailed, true, opts)
+	if !strings.Contains(failed, "38;5;196") {
+		t.Fatalf("expected failed tint, got %q", failed)
+	}
+}
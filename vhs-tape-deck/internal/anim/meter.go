@@ -0,0 +1,89 @@
+package anim
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How far one event pushes the meter. A chatty render pins it; a single
+// line every few seconds shows as a blip.
+const (
+	LogHit      = 0.12
+	ProgressHit = 0.25
+)
+
+const (
+	meterDecay = 0.9
+	peakHold   = 16
+	peakFall   = 0.04
+)
+
+// Meter is a VU-style activity level. Events push it up and every tick
+// lets it fall back, with a peak marker that holds for a moment, so a
+// render that keeps talking keeps the needle moving and a silent one
+// visibly flatlines.
+type Meter struct {
+	level float64
+	peak  float64
+	hold  int
+}
+
+func (m *Meter) Hit(amount float64) {
+	m.level = min(1, m.level+amount)
+	if m.level >= m.peak {
+		m.peak = m.level
+		m.hold = peakHold
+	}
+}
+
+func (m *Meter) Tick() {
+	m.level *= meterDecay
+	if m.level < 0.01 {
+		m.level = 0
+	}
+	if m.hold > 0 {
+		m.hold--
+		return
+	}
+	m.peak = max(m.level, m.peak-peakFall)
+}
+
+func (m *Meter) Level() float64 {
+	return m.level
+}
+
+// Render draws the meter as [=====--|   ] in width cells. With color the
+// bar runs green, then amber, then red near the top like a VU scale.
+func (m *Meter) Render(width int, color bool) string {
+	width = max(width, 4)
+	filled := int(m.level*float64(width) + 0.5)
+	peak := int(m.peak*float64(width)+0.5) - 1
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < width; i++ {
+		ch := " "
+		switch {
+		case i < filled:
+			ch = "="
+		case i == peak:
+			ch = "|"
+		}
+		if color && ch != " " {
+			ch = lipgloss.NewStyle().Foreground(meterColor(i, width)).Render(ch)
+		}
+		b.WriteString(ch)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func meterColor(cell, width int) lipgloss.Color {
+	switch at := float64(cell+1) / float64(width); {
+	case at > 0.85:
+		return "196"
+	case at > 0.6:
+		return "214"
+	}
+	return "42"
+}
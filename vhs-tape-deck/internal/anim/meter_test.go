@@ -0,0 +1,35 @@
+package anim
+
+import "testing"
+
+func TestMeterRisesAndFalls(t *testing.T) {
+	t.Parallel()
+
+	var m Meter
+	if got := m.Render(10, false); got != "[          ]" {
+		t.Fatalf("expected an empty meter, got %q", got)
+	}
+	for range 5 {
+		m.Hit(LogHit)
+	}
+	m.Hit(ProgressHit)
+	if got := m.Render(10, false); got != "[========= ]" {
+		t.Fatalf("unexpected meter after a burst: %q", got)
+	}
+	for range peakHold {
+		m.Tick()
+	}
+	if got := m.Render(10, false); got != "[==      | ]" {
+		t.Fatalf("expected the level to fall while the peak holds, got %q", got)
+	}
+	for range 100 {
+		m.Tick()
+	}
+	if m.Level() != 0 || m.Render(10, false) != "[          ]" {
+		t.Fatalf("expected a silent meter to flatline, got %q", m.Render(10, false))
+	}
+	m.Hit(5)
+	if m.Level() != 1 {
+		t.Fatalf("expected the level to clip at 1, got %v", m.Level())
+	}
+}
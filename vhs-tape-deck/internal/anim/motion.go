@@ -0,0 +1,76 @@
+package anim
+
+import "math"
+
+// Motion is a tape moving in the deck slot.
+type Motion string
+
+const (
+	MotionInsert Motion = "insert"
+	MotionEject  Motion = "eject"
+	// MotionShake jolts an inserted tape when its run fails.
+	MotionShake Motion = "shake"
+)
+
+// slideDistance is how far right of the slot an ejected tape rests.
+const slideDistance = 6
+
+var motionTicks = map[Motion]int{
+	MotionInsert: 10,
+	MotionEject:  8,
+	MotionShake:  len(shakeOffsets),
+}
+
+var shakeOffsets = []int{2, 0, 2, 0, 1, 0, 1, 0}
+
+// Slot is the state machine for the tape in the deck slot: the last motion
+// started, for which tape and at which tick. Between motions a tape rests
+// inserted or ejected.
+type Slot struct {
+	tapeID string
+	motion Motion
+	start  int
+}
+
+func (s *Slot) Insert(tapeID string, tick int) {
+	*s = Slot{tapeID: tapeID, motion: MotionInsert, start: tick}
+}
+
+func (s *Slot) Eject(tapeID string, tick int) {
+	*s = Slot{tapeID: tapeID, motion: MotionEject, start: tick}
+}
+
+func (s *Slot) Shake(tapeID string, tick int) {
+	*s = Slot{tapeID: tapeID, motion: MotionShake, start: tick}
+}
+
+// Animating reports whether a motion is still playing at tick.
+func (s *Slot) Animating(tick int) bool {
+	elapsed := tick - s.start
+	return s.motion != "" && elapsed >= 0 && elapsed < motionTicks[s.motion]
+}
+
+// Offset is how far right of the slot tapeID is drawn at tick. Inserts
+// ease out so the tape settles into place; ejects ease in so it pops.
+func (s *Slot) Offset(tapeID string, inserted bool, tick int) int {
+	rest := slideDistance
+	if inserted {
+		rest = 0
+	}
+	if s.tapeID != tapeID || !s.Animating(tick) {
+		return rest
+	}
+	elapsed := tick - s.start
+	p := float64(elapsed) / float64(motionTicks[s.motion])
+	switch s.motion {
+	case MotionInsert:
+		return int(math.Round(slideDistance * math.Pow(1-p, 3)))
+	case MotionEject:
+		return int(math.Round(slideDistance * p * p * p))
+	case MotionShake:
+		if inserted {
+			return shakeOffsets[elapsed]
+		}
+	}
+	return rest
+}
@@ -0,0 +1,82 @@
+package anim
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSlotMotions(t *testing.T) {
+	t.Parallel()
+
+	var s Slot
+	offsets := func(tapeID string, inserted bool, from int) []int {
+		var out []int
+		for tick := from; tick < from+11; tick++ {
+			out = append(out, s.Offset(tapeID, inserted, tick))
+		}
+		return out
+	}
+
+	s.Insert("alpha", 100)
+	if got := offsets("alpha", true, 100); !slices.Equal(got, []int{6, 4, 3, 2, 1, 1, 0, 0, 0, 0, 0}) {
+		t.Fatalf("unexpected insert offsets %v", got)
+	}
+	if !s.Animating(109) || s.Animating(110) {
+		t.Fatalf("expected the insert to last ten ticks")
+	}
+	if got := s.Offset("beta", false, 101); got != slideDistance {
+		t.Fatalf("expected other tapes to rest ejected, got %d", got)
+	}
+
+	s.Eject("alpha", 200)
+	if got := offsets("alpha", false, 200); !slices.Equal(got, []int{0, 0, 0, 0, 1, 1, 3, 4, 6, 6, 6}) {
+		t.Fatalf("unexpected eject offsets %v", got)
+	}
+
+	s.Shake("alpha", 300)
+	if got := offsets("alpha", true, 300); !slices.Equal(got, []int{2, 0, 2, 0, 1, 0, 1, 0, 0, 0, 0}) {
+		t.Fatalf("unexpected shake offsets %v", got)
+	}
+	if got := s.Offset("alpha", false, 300); got != slideDistance {
+		t.Fatalf("expected an ejected tape not to shake, got %d", got)
+	}
+}
+
+func TestRenderUsesSlot(t *testing.T) {
+	t.Parallel()
+
+	var s Slot
+	s.Eject("alpha", 10)
+	frame := NewCassetteAnimator().Render("Alpha", "alpha", 16, StateIdle, false, Options{Slot: &s})
+	if !strings.Contains(frame, "\n   +---------------------------+") || !strings.Contains(frame, "[ EJECT ]") {
+		t.Fatalf("expected the tape half way out, got:\n%s", frame)
+	}
+}
+
+func TestRenderIntensity(t *testing.T) {
+	t.Parallel()
+
+	a := NewCassetteAnimator()
+	var s Slot
+	s.Shake("alpha", 0)
+	for _, tick := range []int{0, 1, 2} {
+		for _, intensity := range []Intensity{IntensityReduced, IntensityOff} {
+			opts := Options{Slot: &s, Intensity: intensity}
+			if frame := a.Render("Alpha", "alpha", tick, StateFailed, true, opts); !strings.Contains(frame, "\n+---------------------------+") || strings.Contains(frame, "~") {
+				t.Fatalf("%s: expected a still tape at tick %d, got:\n%s", intensity, tick, frame)
+			}
+			running := a.Render("Alpha", "alpha", tick, StateRunning, true, opts)
+			if strings.Count(running, "(|)") != 2 {
+				t.Fatalf("%s: expected still reels at tick %d, got:\n%s", intensity, tick, running)
+			}
+		}
+	}
+	s.Insert("alpha", 0)
+	if frame := a.Render("Alpha", "alpha", 1, StateInserted, true, Options{Slot: &s, Intensity: IntensityReduced}); strings.Contains(frame, "\n+---------------------------+") {
+		t.Fatalf("expected reduced motion to keep the insert slide, got:\n%s", frame)
+	}
+	if frame := a.Render("Alpha", "alpha", 1, StateInserted, true, Options{Slot: &s, Intensity: IntensityOff}); !strings.Contains(frame, "\n+---------------------------+") {
+		t.Fatalf("expected no slide with animation off, got:\n%s", frame)
+	}
+}
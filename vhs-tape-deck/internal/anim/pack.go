@@ -0,0 +1,116 @@
+package anim
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/artpack"
+)
+
+// DefaultPack is the art drawn when a tape does not pick one.
+const DefaultPack = artpack.Default
+
+// fieldPattern matches label and id fields. A field is as wide as its
+// text in the template, so {label....} holds 11 characters.
+var fieldPattern = regexp.MustCompile(`\{(label|id)\.*\}`)
+
+// Pack is cassette art: the lines of a tape as it sits in the deck slot.
+// In the template '#' marks the shell, which takes the tape's colorway,
+// every '@' is a reel, and {label} and {id} fields hold the tape's text.
+type Pack struct {
+	Name  string
+	lines []string
+	width int
+}
+
+// Packs lists the built-in art packs.
+func Packs() []string {
+	return artpack.Names()
+}
+
+// LoadPack returns the built-in pack called art, or reads art as a path to
+// a template file. An empty art is the default pack.
+func LoadPack(art string) (*Pack, error) {
+	return fromTemplate(artpack.Load(art))
+}
+
+// ParsePack reads a template; see artpack.Parse.
+func ParsePack(name string, data []byte) (*Pack, error) {
+	return fromTemplate(artpack.Parse(name, data))
+}
+
+func fromTemplate(t *artpack.Template, err error) (*Pack, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Pack{Name: t.Name, lines: t.Lines, width: t.Width}, nil
+}
+
+// Width is the width of the tape in columns.
+func (p *Pack) Width() int {
+	return p.width
+}
+
+var defaultPack = sync.OnceValue(func() *Pack {
+	p, err := LoadPack(DefaultPack)
+	if err != nil {
+		panic(err)
+	}
+	return p
+})
+
+// draw renders the tape body: shimmer first, so only shell cells sparkle,
+// then colorway, reels and text fields.
+func (p *Pack) draw(shellChar string, reel func(n int) string, label, id string, tick int, sparkle bool, pal palette) []string {
+	out := make([]string, len(p.lines))
+	reels := 0
+	for i, line := range p.lines {
+		if sparkle && i > 0 && i < len(p.lines)-1 {
+			line = shimmer(line, tick, i+3)
+		}
+		var b strings.Builder
+		last := 0
+		for _, loc := range fieldPattern.FindAllStringIndex(line, -1) {
+			drawArt(&b, line[last:loc[0]], shellChar, reel, &reels, pal)
+			text := id
+			if strings.HasPrefix(line[loc[0]:], "{label") {
+				text = label
+			}
+			b.WriteString(pal.paint(pal.label, centerText(text, loc[1]-loc[0])))
+			last = loc[1]
+		}
+		drawArt(&b, line[last:], shellChar, reel, &reels, pal)
+		out[i] = b.String()
+	}
+	return out
+}
+
+// drawArt writes template text outside the fields, painting each run of
+// shell, reel or other cells in one go.
+func drawArt(b *strings.Builder, art, shellChar string, reel func(n int) string, reels *int, pal palette) {
+	var run strings.Builder
+	kind := artCell
+	flush := func(next cell) {
+		if next != kind && run.Len() > 0 {
+			b.WriteString(pal.paint(pal.cells[kind], run.String()))
+			run.Reset()
+		}
+		kind = next
+	}
+	for _, r := range art {
+		switch r {
+		case '#':
+			flush(shellCell)
+			run.WriteString(shellChar)
+		case '@':
+			flush(reelCell)
+			run.WriteString(reel(*reels))
+			*reels++
+		default:
+			flush(artCell)
+			run.WriteRune(r)
+		}
+	}
+	flush(-1)
+}
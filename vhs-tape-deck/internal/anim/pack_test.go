@@ -0,0 +1,54 @@
+package anim
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinPacksRender(t *testing.T) {
+	t.Parallel()
+
+	a := NewCassetteAnimator()
+	for _, name := range Packs() {
+		p, err := LoadPack(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		frame := a.Render("Alpha", "alpha", 1, StateRunning, true, Options{Pack: p})
+		if !strings.Contains(frame, "Alpha") || !strings.Contains(frame, "/") || !strings.Contains(frame, "[ PLAY ]") {
+			t.Fatalf("%s: unexpected frame:\n%s", name, frame)
+		}
+		if strings.ContainsAny(frame, "@{}") {
+			t.Fatalf("%s: unexpanded template in frame:\n%s", name, frame)
+		}
+	}
+}
+
+func TestLoadPackFromFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tiny.txt")
+	art := "\n+------------+\n|@ {label.} @|\n|####\n+------------+\n\n"
+	if err := os.WriteFile(path, []byte(art), 0o644); err != nil {
+		t.Fatalf("write art: %v", err)
+	}
+	p, err := LoadPack(path)
+	if err != nil {
+		t.Fatalf("LoadPack: %v", err)
+	}
+	if p.Name != "tiny" || p.Width() != 14 {
+		t.Fatalf("unexpected pack %q width %d", p.Name, p.Width())
+	}
+
+	frame := NewCassetteAnimator().Render("Alpha", "alpha", 0, StateRunning, true, Options{ShellColorway: "gray", Pack: p})
+	lines := strings.Split(frame, "\n")
+	if len(lines) != 8 || lines[4] != "      ||  Alpha   -|" || lines[5] != "      |====         " {
+		t.Fatalf("unexpected frame:\n%s", frame)
+	}
+
+	if _, err := LoadPack(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Fatalf("expected read error")
+	}
+}
@@ -0,0 +1,104 @@
+// Package artpack loads cassette art templates: the built-in packs and
+// custom template files. It only reads and checks templates; drawing them
+// is left to internal/anim.
+package artpack
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Default is the art drawn when a tape does not pick one.
+const Default = "classic"
+
+const (
+	maxLines = 12
+	maxWidth = 60
+)
+
+//go:embed packs/*.txt
+var builtin embed.FS
+
+// Template is cassette art as written: '#' marks the shell, every '@' is a
+// reel, and {label} and {id} fields hold the tape's text. Lines are padded
+// to Width columns.
+type Template struct {
+	Name  string
+	Lines []string
+	Width int
+}
+
+// Names lists the built-in packs.
+func Names() []string {
+	entries, _ := builtin.ReadDir("packs")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBuiltin reports whether name is a built-in pack rather than a file.
+func IsBuiltin(name string) bool {
+	for _, p := range Names() {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Load returns the built-in pack called art, or reads art as a path to a
+// template file. An empty art is the default pack.
+func Load(art string) (*Template, error) {
+	if art == "" {
+		art = Default
+	}
+	if IsBuiltin(art) {
+		data, err := builtin.ReadFile("packs/" + art + ".txt")
+		if err != nil {
+			return nil, err
+		}
+		return Parse(art, data)
+	}
+	data, err := os.ReadFile(art)
+	if err != nil {
+		return nil, fmt.Errorf("read art pack: %w", err)
+	}
+	return Parse(strings.TrimSuffix(filepath.Base(art), filepath.Ext(art)), data)
+}
+
+// Parse reads a template. Blank lines around the art are dropped and short
+// lines are padded to the widest one.
+func Parse(name string, data []byte) (*Template, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.Trim(text, "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("art pack %q: empty", name)
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > maxLines {
+		return nil, fmt.Errorf("art pack %q: %d lines, at most %d allowed", name, len(lines), maxLines)
+	}
+	width := 0
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.Contains(line, "\t") {
+			return nil, fmt.Errorf("art pack %q: line %d: tabs are not allowed", name, i+1)
+		}
+		lines[i] = line
+		width = max(width, len([]rune(line)))
+	}
+	if width > maxWidth {
+		return nil, fmt.Errorf("art pack %q: %d columns, at most %d allowed", name, width, maxWidth)
+	}
+	for i, line := range lines {
+		lines[i] = line + strings.Repeat(" ", width-len([]rune(line)))
+	}
+	return &Template{Name: name, Lines: lines, Width: width}, nil
+}
@@ -0,0 +1,44 @@
+package artpack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinPacks(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"classic", "mini", "wide", "betamax", "reel-to-reel"} {
+		if !IsBuiltin(name) {
+			t.Fatalf("expected built-in pack %q", name)
+		}
+	}
+	for _, name := range Names() {
+		if _, err := Load(name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if tmpl, err := Load(""); err != nil || tmpl.Name != Default {
+		t.Fatalf("expected the default pack, got %+v (%v)", tmpl, err)
+	}
+}
+
+func TestParseRejectsBadTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := Parse("tiny", []byte("\n+--+\n|@\n+--+\n\n"))
+	if err != nil || tmpl.Width != 4 || len(tmpl.Lines) != 3 || tmpl.Lines[1] != "|@  " {
+		t.Fatalf("unexpected template %+v (%v)", tmpl, err)
+	}
+
+	for want, art := range map[string]string{
+		"empty":                "\n  \n",
+		"at most 60 allowed":   strings.Repeat("-", maxWidth+1),
+		"lines, at most":       strings.Repeat("-\n", maxLines+1),
+		"tabs are not allowed": "+--+\n|\t@|\n+--+",
+	} {
+		if _, err := Parse("bad", []byte(art)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
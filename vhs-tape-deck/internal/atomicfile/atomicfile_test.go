@@ -0,0 +1,27 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReplacesFileAndLeavesNoTemp(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "state")
+	path := filepath.Join(dir, "queue.json")
+	for _, data := range []string{"first\n", "second\n"} {
+		if err := Write(path, []byte(data)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil || string(buf) != data {
+			t.Fatalf("expected %q, got %q (%v)", data, buf, err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected only the written file, got %v (%v)", entries, err)
+	}
+}
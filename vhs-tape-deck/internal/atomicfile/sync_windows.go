@@ -0,0 +1,9 @@
+//go:build windows
+
+package atomicfile
+
+// syncDir is a no-op: Windows cannot flush directory handles, and NTFS
+// journals renames itself.
+func syncDir(string) error {
+	return nil
+}
@@ -0,0 +1,310 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultColumn is the column name used when rows are given as plain strings.
+const DefaultColumn = "text"
+
+type Row map[string]string
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_\-]+)\s*\}\}`)
+
+func LoadRows(path string) ([]Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open rows: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return parseJSONRows(f)
+	case ".csv":
+		return parseCSVRows(f)
+	default:
+		return nil, fmt.Errorf("unsupported rows file %q (expected .csv or .json)", path)
+	}
+}
+
+func parseCSVRows(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("csv requires a header row and at least one data row")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		header[i] = strings.TrimSpace(name)
+		if header[i] == "" {
+			return nil, fmt.Errorf("csv header column %d is empty", i+1)
+		}
+	}
+
+	rows := make([]Row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := Row{}
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseJSONRows(r io.Reader) ([]Row, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("json rows file is empty")
+	}
+
+	rows := make([]Row, 0, len(raw))
+	for i, item := range raw {
+		var text string
+		if err := json.Unmarshal(item, &text); err == nil {
+			rows = append(rows, Row{DefaultColumn: text})
+			continue
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(item, &obj); err != nil {
+			return nil, fmt.Errorf("row %d: expected string or object", i+1)
+		}
+		row := Row{}
+		for k, v := range obj {
+			row[k] = fmt.Sprint(v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func Placeholders(template string) []string {
+	seen := map[string]struct{}{}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		seen[m[1]] = struct{}{}
+	}
+	out := make([]string, 0, len(seen))
+	for k := range seen {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Expand substitutes {{name}} placeholders in template with values from row.
+// Each value is escaped for where its placeholder sits: inside a
+// double-quoted or single-quoted YAML string, or in a plain scalar. Plain
+// scalars only take values YAML would read back unchanged; anything else
+// is an error asking for the placeholder to be quoted.
+func Expand(template string, row Row) (string, error) {
+	var missing []string
+	for _, name := range Placeholders(template) {
+		if _, ok := row[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("row is missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	var b strings.Builder
+	var lex yamlLexer
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		lex.scan(template[last:loc[0]])
+		b.WriteString(template[last:loc[0]])
+		name := template[loc[2]:loc[3]]
+		value, err := lex.escape(row[name])
+		if err != nil {
+			line := strings.Count(template[:loc[0]], "\n") + 1
+			return "", fmt.Errorf("column %q on line %d: %w", name, line, err)
+		}
+		b.WriteString(value)
+		lex.placeholder()
+		last = loc[1]
+	}
+	b.WriteString(template[last:])
+	return b.String(), nil
+}
+
+type yamlContext int
+
+const (
+	contextPlain yamlContext = iota
+	contextDouble
+	contextSingle
+	contextComment
+)
+
+var (
+	doubleEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	singleEscaper = strings.NewReplacer(`'`, `''`)
+)
+
+// yamlLexer tracks, line by line, whether the text so far ends inside a
+// quoted string or a comment. It knows just enough YAML for manifest
+// templates: a quote opens a string only where a scalar can start.
+type yamlLexer struct {
+	context yamlContext
+	// prev is the last non-space character outside strings on this line.
+	prev rune
+}
+
+func (l *yamlLexer) scan(text string) {
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch l.context {
+		case contextDouble:
+			if r == '\\' {
+				i++
+			} else if r == '"' {
+				l.context, l.prev = contextPlain, r
+			}
+			continue
+		case contextSingle:
+			if r == '\'' {
+				if i+1 < len(runes) && runes[i+1] == '\'' {
+					i++
+				} else {
+					l.context, l.prev = contextPlain, r
+				}
+			}
+			continue
+		}
+		if r == '\n' {
+			l.context, l.prev = contextPlain, 0
+			continue
+		}
+		if l.context == contextComment || r == ' ' || r == '\t' {
+			continue
+		}
+		switch {
+		case r == '#' && (l.prev == 0 || (i > 0 && (runes[i-1] == ' ' || runes[i-1] == '\t'))):
+			l.context = contextComment
+		case (r == '"' || r == '\'') && strings.ContainsRune("\x00:-[{,?", l.prev):
+			l.context = contextDouble
+			if r == '\'' {
+				l.context = contextSingle
+			}
+		}
+		l.prev = r
+	}
+}
+
+// placeholder records that a value was written in the current context.
+func (l *yamlLexer) placeholder() {
+	if l.context == contextPlain {
+		l.prev = 'x'
+	}
+}
+
+func (l *yamlLexer) escape(value string) (string, error) {
+	switch l.context {
+	case contextDouble:
+		return doubleEscaper.Replace(value), nil
+	case contextSingle:
+		if strings.ContainsAny(value, "\r\n") {
+			return "", errors.New("a single-quoted string can't hold a line break; use double quotes")
+		}
+		return singleEscaper.Replace(value), nil
+	case contextComment:
+		return strings.NewReplacer("\r", " ", "\n", " ").Replace(value), nil
+	}
+	if !plainSafe(value) {
+		return "", fmt.Errorf("value %q is not a plain YAML scalar; put the placeholder in double quotes, e.g. \"{{...}}\"", value)
+	}
+	return value, nil
+}
+
+// plainSafe reports whether value reads back unchanged as (part of) an
+// unquoted YAML scalar.
+func plainSafe(value string) bool {
+	if value == "" {
+		return true
+	}
+	if strings.TrimSpace(value) != value || strings.ContainsAny(value, "\r\n\t") {
+		return false
+	}
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") || strings.HasPrefix(value, "- ") {
+		return false
+	}
+	return !strings.ContainsRune("[]{}#&*!|>'\"%@`,?:", []rune(value)[0])
+}
+
+// RowManifestPath is where row i (counting from 1) of a batch is rendered
+// from: a hidden file beside the template, because vcr resolves relative
+// asset paths from the manifest's directory. It only lives for the batch;
+// ArchivePath is the copy that is kept.
+func RowManifestPath(templatePath, batchID string, i int) string {
+	return filepath.Join(filepath.Dir(templatePath), fmt.Sprintf(".batch_%s_row_%03d%s", batchID, i, manifestExt(templatePath)))
+}
+
+// ArchivePath is where the copy of row i's manifest is kept in the batch
+// dir.
+func ArchivePath(batchDir, templatePath string, i int) string {
+	return filepath.Join(batchDir, fmt.Sprintf("row_%03d%s", i, manifestExt(templatePath)))
+}
+
+func manifestExt(templatePath string) string {
+	if ext := filepath.Ext(templatePath); ext != "" {
+		return ext
+	}
+	return ".yaml"
+}
+
+// WriteRowManifests expands template for every row, archives each result
+// in batchDir and writes it to its RowManifestPath. It returns the paths
+// beside the template, which the caller removes with RemoveRowManifests
+// once the batch is done. Nothing is left beside the template if any row
+// fails to expand or write.
+func WriteRowManifests(template string, rows []Row, templatePath, batchID, batchDir string) ([]string, error) {
+	expanded := make([]string, len(rows))
+	for i, row := range rows {
+		var err error
+		if expanded[i], err = Expand(template, row); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	paths := make([]string, 0, len(rows))
+	for i, text := range expanded {
+		if err := os.WriteFile(ArchivePath(batchDir, templatePath, i+1), []byte(text), 0o644); err != nil {
+			RemoveRowManifests(paths)
+			return nil, fmt.Errorf("archive row %d manifest: %w", i+1, err)
+		}
+		path := RowManifestPath(templatePath, batchID, i+1)
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			RemoveRowManifests(append(paths, path))
+			return nil, fmt.Errorf("write row %d manifest: %w", i+1, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// RemoveRowManifests deletes the manifests written beside the template.
+func RemoveRowManifests(paths []string) {
+	for _, path := range paths {
+		_ = os.Remove(path)
+	}
+}
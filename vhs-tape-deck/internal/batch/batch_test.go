@@ -0,0 +1,161 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadRowsCSV(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	data := "name,title\nAda Lovelace,Engineer\n\"Grace \"\"Amazing\"\" Hopper\",Admiral\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write rows: %v", err)
+	}
+
+	rows, err := LoadRows(path)
+	if err != nil {
+		t.Fatalf("LoadRows: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[1]["name"] != `Grace "Amazing" Hopper` {
+		t.Fatalf("unexpected name: %q", rows[1]["name"])
+	}
+}
+
+func TestLoadRowsJSONStrings(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rows.json")
+	if err := os.WriteFile(path, []byte(`["One", {"text": "Two", "n": 2}]`), 0o644); err != nil {
+		t.Fatalf("write rows: %v", err)
+	}
+
+	rows, err := LoadRows(path)
+	if err != nil {
+		t.Fatalf("LoadRows: %v", err)
+	}
+	if rows[0][DefaultColumn] != "One" || rows[1]["n"] != "2" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+func TestExpandEscapesAndReportsMissing(t *testing.T) {
+	t.Parallel()
+
+	tmpl := `text: "{{ name }} - {{title}}"`
+	out, err := Expand(tmpl, Row{"name": `Grace "G" Hopper`, "title": "Admiral"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if out != `text: "Grace \"G\" Hopper - Admiral"` {
+		t.Fatalf("unexpected expansion: %s", out)
+	}
+
+	_, err = Expand(tmpl, Row{"name": "x"})
+	if err == nil || !strings.Contains(err.Error(), "title") {
+		t.Fatalf("expected missing column error, got %v", err)
+	}
+}
+
+func TestExpandEscapesForQuoteStyle(t *testing.T) {
+	t.Parallel()
+
+	tmpl := "double: \"{{v}}\"\nsingle: '{{v}}'\nplain: {{n}}\nmixed: 'it''s {{v}}' # {{v}}\n"
+	out, err := Expand(tmpl, Row{"v": `O"Brien's C:\path`, "n": "-2.5"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("expanded template is not YAML: %v\n%s", err, out)
+	}
+	want := `O"Brien's C:\path`
+	if doc["double"] != want || doc["single"] != want || doc["mixed"] != "it's "+want || doc["plain"] != -2.5 {
+		t.Fatalf("unexpected values %v from:\n%s", doc, out)
+	}
+
+	for _, value := range []string{"a: b", "#tag", "two\nlines", " padded"} {
+		if _, err := Expand("title: {{v}}\n", Row{"v": value}); err == nil || !strings.Contains(err.Error(), "double quotes") {
+			t.Fatalf("expected a quoting error for plain %q, got %v", value, err)
+		}
+	}
+	if _, err := Expand("title: '{{v}}'\n", Row{"v": "two\nlines"}); err == nil {
+		t.Fatalf("expected an error for a line break in single quotes")
+	}
+}
+
+func TestWriteRowManifestsBesideTemplate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	manifests := filepath.Join(root, "manifests")
+	if err := os.MkdirAll(filepath.Join(manifests, "assets"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"a.png", "b.png"} {
+		if err := os.WriteFile(filepath.Join(manifests, "assets", name), nil, 0o644); err != nil {
+			t.Fatalf("write asset: %v", err)
+		}
+	}
+	templatePath := filepath.Join(manifests, "entrance.vcr")
+	batchDir := filepath.Join(root, "batch")
+	if err := os.MkdirAll(batchDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	paths, err := WriteRowManifests("image:\n  path: \"assets/{{text}}.png\"\n", []Row{{"text": "a"}, {"text": "b"}}, templatePath, "20250301_101500_alpha", batchDir)
+	if err != nil {
+		t.Fatalf("WriteRowManifests: %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[1]) != ".batch_20250301_101500_alpha_row_002.vcr" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	for i, path := range paths {
+		var doc struct {
+			Image struct {
+				Path string `yaml:"path"`
+			} `yaml:"image"`
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read manifest: %v", err)
+		}
+		if err := yaml.Unmarshal(buf, &doc); err != nil {
+			t.Fatalf("parse row %d: %v", i+1, err)
+		}
+		// vcr joins relative asset paths onto the manifest's directory.
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), doc.Image.Path)); err != nil {
+			t.Fatalf("row %d asset does not resolve: %v", i+1, err)
+		}
+		archived, err := os.ReadFile(ArchivePath(batchDir, templatePath, i+1))
+		if err != nil || string(archived) != string(buf) {
+			t.Fatalf("row %d archive: %q (%v)", i+1, archived, err)
+		}
+	}
+	RemoveRowManifests(paths)
+	if entries, _ := os.ReadDir(manifests); len(entries) != 1 {
+		t.Fatalf("expected only assets beside the template, got %d entries", len(entries))
+	}
+
+	if _, err := WriteRowManifests("title: {{text}}\n", []Row{{"text": "ok"}, {"text": "a: b"}}, templatePath, "second", batchDir); err == nil {
+		t.Fatalf("expected an error for the second row")
+	}
+	if _, err := os.Stat(RowManifestPath(templatePath, "second", 1)); !os.IsNotExist(err) {
+		t.Fatalf("no row should be written when one fails, got %v", err)
+	}
+
+	// A failed write takes back the rows already written beside the template.
+	if _, err := WriteRowManifests("title: {{text}}\n", []Row{{"text": "a"}}, templatePath, "third", filepath.Join(root, "missing")); err == nil {
+		t.Fatalf("expected an error for a missing batch dir")
+	}
+	if entries, _ := os.ReadDir(manifests); len(entries) != 1 {
+		t.Fatalf("expected nothing left beside the template, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,337 @@
+// Package bench renders a tape repeatedly and reports render throughput,
+// for comparing quality presets or GPU and driver changes.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+type Options struct {
+	Config *config.Config
+	Runner *runner.Runner
+	Tape   config.Tape
+	// Runs is the number of measured renders per preset.
+	Runs int
+	// Warmup renders run before the measured ones and are not counted, so
+	// shader compilation and cold caches do not skew the numbers.
+	Warmup int
+	// Presets are --quality values; each one is benchmarked in turn. Empty
+	// benchmarks the tape's own args.
+	Presets []string
+	// Out receives a line per finished render.
+	Out io.Writer
+}
+
+// Sample is one measured render.
+type Sample struct {
+	Preset     string           `json:"preset,omitempty"`
+	Run        int              `json:"run"`
+	RunID      string           `json:"run_id"`
+	Status     runner.RunStatus `json:"status"`
+	DurationMS int64            `json:"duration_ms"`
+	// Frames is the highest frame count vcr reported; zero when it printed
+	// no progress.
+	Frames int     `json:"frames,omitempty"`
+	FPS    float64 `json:"fps,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Summary aggregates the successful samples of one preset.
+type Summary struct {
+	Preset    string  `json:"preset,omitempty"`
+	Runs      int     `json:"runs"`
+	Successes int     `json:"successes"`
+	MeanMS    int64   `json:"mean_ms"`
+	MinMS     int64   `json:"min_ms"`
+	MaxMS     int64   `json:"max_ms"`
+	StddevMS  float64 `json:"stddev_ms"`
+	MeanFPS   float64 `json:"mean_fps,omitempty"`
+}
+
+type Report struct {
+	ID         string    `json:"id"`
+	TapeID     string    `json:"tape_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	VCRVersion string    `json:"vcr_version,omitempty"`
+	Runs       int       `json:"runs"`
+	Warmup     int       `json:"warmup,omitempty"`
+	// ClaimPath holds the report id until the report is written; pass it
+	// to runner.ReleaseID afterwards.
+	ClaimPath string    `json:"-"`
+	Samples   []Sample  `json:"samples"`
+	Summaries []Summary `json:"summaries"`
+}
+
+func ReportPath(runsDir, id string) string {
+	return filepath.Join(runsDir, "bench", id+".json")
+}
+
+// Run benchmarks every preset in order. On cancellation it returns the
+// samples gathered so far along with ctx's error.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Runs < 1 {
+		return nil, fmt.Errorf("runs must be >= 1: %d", opts.Runs)
+	}
+	if len(opts.Tape.PrimaryArgs) > 0 && !strings.HasPrefix(strings.TrimSpace(opts.Tape.PrimaryArgs[0]), "-") && len(opts.Presets) > 0 {
+		return nil, fmt.Errorf("tape %q uses a full command in primary_args; presets need the default render command", opts.Tape.ID)
+	}
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+	presets := opts.Presets
+	if len(presets) == 0 {
+		presets = []string{""}
+	}
+
+	now := time.Now()
+	id, claim, err := runner.ReserveID(opts.Config.RunsDir, "bench", opts.Tape.ID, now, func(id string) bool {
+		_, err := os.Stat(ReportPath(opts.Config.RunsDir, id))
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		ID:         id,
+		ClaimPath:  claim,
+		TapeID:     opts.Tape.ID,
+		Timestamp:  now,
+		VCRVersion: opts.Runner.DetectFeatures(ctx, opts.Config).Version,
+		Runs:       opts.Runs,
+		Warmup:     opts.Warmup,
+	}
+	for _, preset := range presets {
+		tape := opts.Tape
+		if preset != "" {
+			tape.PrimaryArgs = append(withoutQuality(tape.PrimaryArgs), "--quality", preset)
+		}
+		label := preset
+		if label == "" {
+			label = "default"
+		}
+		for i := 1; i <= opts.Warmup+opts.Runs; i++ {
+			s := measure(ctx, opts, tape)
+			if ctx.Err() != nil {
+				report.Summaries = Summarize(report.Samples)
+				return report, ctx.Err()
+			}
+			if i <= opts.Warmup {
+				fmt.Fprintf(out, "[bench] %s warmup %d/%d: %s\n", label, i, opts.Warmup, describe(s))
+				continue
+			}
+			s.Preset = preset
+			s.Run = i - opts.Warmup
+			report.Samples = append(report.Samples, s)
+			fmt.Fprintf(out, "[bench] %s run %d/%d: %s\n", label, s.Run, opts.Runs, describe(s))
+		}
+	}
+	report.Summaries = Summarize(report.Samples)
+	return report, nil
+}
+
+func measure(ctx context.Context, opts Options, tape config.Tape) Sample {
+	s := Sample{Status: runner.StatusFailed}
+	events, err := opts.Runner.Start(ctx, runner.Request{Config: opts.Config, Tape: tape, Action: runner.ActionPrimary, Trigger: "bench", SkipUpload: true})
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	for event := range events {
+		switch event.Type {
+		case runner.EventProgress:
+			if p := event.Progress; p.Phase == progress.PhaseRender {
+				s.Frames = max(s.Frames, p.Done)
+			}
+		case runner.EventFinished:
+			if event.Record != nil {
+				s.RunID = event.Record.RunID
+				s.Status = event.Record.Status
+				s.DurationMS = event.Record.DurationMS
+			}
+			if event.ExitCode != 0 {
+				s.Error = event.Message
+			}
+		}
+	}
+	if s.Status == runner.StatusSuccess && s.Frames > 0 && s.DurationMS > 0 {
+		s.FPS = float64(s.Frames) / (float64(s.DurationMS) / 1000)
+	}
+	return s
+}
+
+func describe(s Sample) string {
+	if s.Status != runner.StatusSuccess {
+		return fmt.Sprintf("%s: %s", s.Status, s.Error)
+	}
+	line := (time.Duration(s.DurationMS) * time.Millisecond).String()
+	if s.FPS > 0 {
+		line += fmt.Sprintf(", %d frames, %.1f fps", s.Frames, s.FPS)
+	}
+	return line
+}
+
+// Summarize groups samples by preset, keeping the order presets first
+// appear in.
+func Summarize(samples []Sample) []Summary {
+	var out []Summary
+	index := map[string]int{}
+	var durations [][]int64
+	var fps [][]float64
+	for _, s := range samples {
+		i, ok := index[s.Preset]
+		if !ok {
+			i = len(out)
+			index[s.Preset] = i
+			out = append(out, Summary{Preset: s.Preset})
+			durations = append(durations, nil)
+			fps = append(fps, nil)
+		}
+		out[i].Runs++
+		if s.Status != runner.StatusSuccess {
+			continue
+		}
+		out[i].Successes++
+		durations[i] = append(durations[i], s.DurationMS)
+		if s.FPS > 0 {
+			fps[i] = append(fps[i], s.FPS)
+		}
+	}
+
+	for i := range out {
+		d := durations[i]
+		if len(d) == 0 {
+			continue
+		}
+		var sum int64
+		out[i].MinMS, out[i].MaxMS = d[0], d[0]
+		for _, v := range d {
+			sum += v
+			out[i].MinMS = min(out[i].MinMS, v)
+			out[i].MaxMS = max(out[i].MaxMS, v)
+		}
+		mean := float64(sum) / float64(len(d))
+		out[i].MeanMS = int64(math.Round(mean))
+		var sq float64
+		for _, v := range d {
+			sq += (float64(v) - mean) * (float64(v) - mean)
+		}
+		out[i].StddevMS = math.Sqrt(sq / float64(len(d)))
+		if len(fps[i]) > 0 {
+			var total float64
+			for _, v := range fps[i] {
+				total += v
+			}
+			out[i].MeanFPS = total / float64(len(fps[i]))
+		}
+	}
+	return out
+}
+
+// WriteTable prints one row per preset. With a baseline, presets it also
+// measured get the change in mean duration and fps.
+func (r *Report) WriteTable(w io.Writer, baseline *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "PRESET\tRUNS\tOK\tMEAN\tMIN\tMAX\tSTDDEV\tFPS"
+	if baseline != nil {
+		header += "\tΔ MEAN\tΔ FPS"
+	}
+	fmt.Fprintln(tw, header)
+	for _, s := range r.Summaries {
+		preset := s.Preset
+		if preset == "" {
+			preset = "default"
+		}
+		fpsText := "-"
+		if s.MeanFPS > 0 {
+			fpsText = fmt.Sprintf("%.1f", s.MeanFPS)
+		}
+		line := fmt.Sprintf("%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s", preset, s.Runs, s.Successes, ms(float64(s.MeanMS)), ms(float64(s.MinMS)), ms(float64(s.MaxMS)), ms(s.StddevMS), fpsText)
+		if baseline != nil {
+			line += "\t" + deltas(s, baseline)
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+func deltas(s Summary, baseline *Report) string {
+	for _, b := range baseline.Summaries {
+		if b.Preset != s.Preset {
+			continue
+		}
+		mean, fps := "-", "-"
+		if b.MeanMS > 0 && s.MeanMS > 0 {
+			mean = percent(float64(s.MeanMS), float64(b.MeanMS))
+		}
+		if b.MeanFPS > 0 && s.MeanFPS > 0 {
+			fps = percent(s.MeanFPS, b.MeanFPS)
+		}
+		return mean + "\t" + fps
+	}
+	return "-\t-"
+}
+
+func percent(v, base float64) string {
+	return fmt.Sprintf("%+.1f%%", (v-base)/base*100)
+}
+
+func ms(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return (time.Duration(v) * time.Millisecond).Round(time.Millisecond).String()
+}
+
+func WriteReport(path string, r *Report) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create bench dir: %w", err)
+	}
+	buf, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bench report: %w", err)
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write bench report: %w", err)
+	}
+	return nil
+}
+
+func ReadReport(path string) (*Report, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bench report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(buf, &r); err != nil {
+		return nil, fmt.Errorf("parse bench report %s: %w", filepath.Base(path), err)
+	}
+	return &r, nil
+}
+
+func withoutQuality(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--quality":
+			i++
+		case strings.HasPrefix(args[i], "--quality="):
+		default:
+			out = append(out, args[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestRunMeasuresPresets(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	calls := filepath.Join(tmp, "calls")
+	vcr := filepath.Join(tmp, "vcr")
+	script := "#!/bin/sh\n[ \"$1\" = render ] || exit 0\necho \"$@\" >> " + calls + "\nfor i in 1 2 3 4; do echo \"rendered frame $i/4\"; done\nsleep 0.05\n"
+	if err := os.WriteFile(vcr, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake vcr: %v", err)
+	}
+	cfg := &config.Config{
+		VCRBinary:   vcr,
+		ProjectRoot: filepath.Join(tmp, "project"),
+		RunsDir:     filepath.Join(tmp, "runs"),
+		Tapes:       []config.Tape{{ID: "alpha", Manifest: "./alpha.yaml", Mode: config.ModeVideo, PrimaryArgs: []string{"--quality", "high"}}},
+	}
+	if err := os.MkdirAll(cfg.ProjectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), cfg.ProjectRoot); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+
+	var out bytes.Buffer
+	report, err := Run(context.Background(), Options{
+		Config: cfg, Runner: runner.New(nil), Tape: cfg.Tapes[0],
+		Runs: 2, Warmup: 1, Presets: []string{"draft", "final"}, Out: &out,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	buf, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("read calls: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) != 6 || strings.Count(lines[0], "--quality") != 1 || !strings.Contains(lines[0], "--quality draft") || !strings.Contains(lines[5], "--quality final") {
+		t.Fatalf("unexpected renders: %q", lines)
+	}
+	if len(report.Samples) != 4 || len(report.Summaries) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, s := range report.Summaries {
+		if s.Successes != 2 || s.MeanMS <= 0 || s.MeanFPS <= 0 {
+			t.Fatalf("unexpected summary: %+v", s)
+		}
+	}
+	if report.Samples[0].Frames != 4 {
+		t.Fatalf("expected 4 frames, got %d", report.Samples[0].Frames)
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table, report); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	if !strings.Contains(table.String(), "draft") || !strings.Contains(table.String(), "+0.0%") {
+		t.Fatalf("unexpected table:\n%s", table.String())
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	got := Summarize([]Sample{
+		{Preset: "draft", Status: runner.StatusSuccess, DurationMS: 1000, FPS: 60},
+		{Preset: "draft", Status: runner.StatusSuccess, DurationMS: 3000, FPS: 20},
+		{Preset: "draft", Status: runner.StatusFailed},
+		{Preset: "", Status: runner.StatusSuccess, DurationMS: 500},
+	})
+	if len(got) != 2 || got[0].Preset != "draft" {
+		t.Fatalf("unexpected summaries: %+v", got)
+	}
+	d := got[0]
+	if d.Runs != 3 || d.Successes != 2 || d.MeanMS != 2000 || d.MinMS != 1000 || d.MaxMS != 3000 || d.StddevMS != 1000 || d.MeanFPS != 40 {
+		t.Fatalf("unexpected draft summary: %+v", d)
+	}
+	if got[1].MeanFPS != 0 || got[1].MeanMS != 500 {
+		t.Fatalf("unexpected default summary: %+v", got[1])
+	}
+}
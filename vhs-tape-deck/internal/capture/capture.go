@@ -0,0 +1,293 @@
+// Package capture saves a rendered terminal frame as ANSI text, plain text
+// and SVG, for bug reports and docs.
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	cellWidth  = 8.4
+	cellHeight = 17
+	fontSize   = 14
+	padding    = 12
+
+	defaultFG = "#d0d0d0"
+	defaultBG = "#1c1c1c"
+)
+
+// Dir is where screenshots of the deck are written.
+func Dir(runsDir string) string {
+	return filepath.Join(runsDir, "screenshots")
+}
+
+// Write saves frame as <name>_<time>.ansi, .txt and .svg under dir and
+// returns the paths.
+func Write(dir, name string, frame string, now time.Time) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create screenshot dir: %w", err)
+	}
+	base := filepath.Join(dir, name+"_"+now.Format("20060102_150405"))
+	files := []struct {
+		ext  string
+		data []byte
+	}{
+		{".ansi", []byte(frame)},
+		{".txt", []byte(Strip(frame))},
+		{".svg", SVG(frame)},
+	}
+	var paths []string
+	for _, f := range files {
+		path := base + f.ext
+		if err := os.WriteFile(path, f.data, 0o644); err != nil {
+			return paths, fmt.Errorf("write screenshot: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Strip removes escape sequences and trailing spaces from each line.
+func Strip(frame string) string {
+	var b strings.Builder
+	scan(frame, func(r rune, _ style) {
+		b.WriteRune(r)
+	})
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SVG draws frame as monospace text on the default terminal background,
+// keeping SGR colors, bold, italic and underline.
+func SVG(frame string) []byte {
+	type span struct {
+		col, width int
+		text       strings.Builder
+		style      style
+	}
+	var rows [][]*span
+	row, col, cols := 0, 0, 0
+	rows = append(rows, nil)
+	scan(frame, func(r rune, st style) {
+		if r == '\n' {
+			rows = append(rows, nil)
+			row++
+			col = 0
+			return
+		}
+		w := runewidth.RuneWidth(r)
+		spans := rows[row]
+		if n := len(spans); n > 0 && spans[n-1].style == st && spans[n-1].col+spans[n-1].width == col {
+			spans[n-1].text.WriteRune(r)
+			spans[n-1].width += w
+		} else {
+			s := &span{col: col, width: w, style: st}
+			s.text.WriteRune(r)
+			rows[row] = append(spans, s)
+		}
+		col += w
+		cols = max(cols, col)
+	})
+
+	width := float64(cols)*cellWidth + 2*padding
+	height := float64(len(rows))*cellHeight + 2*padding
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", defaultBG)
+	fmt.Fprintf(&b, `<g font-family="ui-monospace, Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" xml:space="preserve">`+"\n", fontSize)
+	for y, spans := range rows {
+		for _, s := range spans {
+			fg, bg := s.style.fg, s.style.bg
+			if s.style.reverse {
+				fg, bg = or(bg, defaultBG), or(fg, defaultFG)
+			}
+			x := padding + float64(s.col)*cellWidth
+			top := padding + float64(y)*cellHeight
+			if bg != "" {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"/>`+"\n", x, top, float64(s.width)*cellWidth, cellHeight, bg)
+			}
+			text := s.text.String()
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" fill="%s"`, x, top+cellHeight-4, or(fg, defaultFG))
+			if s.style.bold {
+				b.WriteString(` font-weight="bold"`)
+			}
+			if s.style.italic {
+				b.WriteString(` font-style="italic"`)
+			}
+			if s.style.underline {
+				b.WriteString(` text-decoration="underline"`)
+			}
+			if s.style.faint {
+				b.WriteString(` opacity="0.6"`)
+			}
+			fmt.Fprintf(&b, ">%s</text>\n", html.EscapeString(text))
+		}
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.Bytes()
+}
+
+func or(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+
+type style struct {
+	fg, bg                                  string
+	bold, faint, italic, underline, reverse bool
+}
+
+// scan calls emit for every printable rune and newline in s with the SGR
+// style in effect. Other escape sequences are skipped.
+func scan(s string, emit func(rune, style)) {
+	var st style
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == 0x1b && i+1 < len(runes) && runes[i+1] == '[':
+			j := i + 2
+			for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+				j++
+			}
+			if j < len(runes) && runes[j] == 'm' {
+				st = st.apply(string(runes[i+2 : j]))
+			}
+			i = j
+		case r == 0x1b && i+1 < len(runes) && runes[i+1] == ']':
+			// OSC, e.g. hyperlinks: ends with BEL or ESC \.
+			j := i + 2
+			for j < len(runes) && runes[j] != 0x07 && !(runes[j] == 0x1b && j+1 < len(runes) && runes[j+1] == '\\') {
+				j++
+			}
+			if j < len(runes) && runes[j] == 0x1b {
+				j++
+			}
+			i = j
+		case r == 0x1b:
+			i++
+		case r == '\r':
+		case r == '\n', r >= 0x20:
+			emit(r, st)
+		}
+	}
+}
+
+func (st style) apply(params string) style {
+	if params == "" {
+		return style{}
+	}
+	p := strings.Split(strings.ReplaceAll(params, ":", ";"), ";")
+	for i := 0; i < len(p); i++ {
+		n, err := strconv.Atoi(p[i])
+		if err != nil {
+			n = 0
+		}
+		switch {
+		case n == 0:
+			st = style{}
+		case n == 1:
+			st.bold = true
+		case n == 2:
+			st.faint = true
+		case n == 3:
+			st.italic = true
+		case n == 4:
+			st.underline = true
+		case n == 7:
+			st.reverse = true
+		case n == 22:
+			st.bold, st.faint = false, false
+		case n == 23:
+			st.italic = false
+		case n == 24:
+			st.underline = false
+		case n == 27:
+			st.reverse = false
+		case n >= 30 && n <= 37:
+			st.fg = palette(n - 30)
+		case n >= 90 && n <= 97:
+			st.fg = palette(n - 90 + 8)
+		case n >= 40 && n <= 47:
+			st.bg = palette(n - 40)
+		case n >= 100 && n <= 107:
+			st.bg = palette(n - 100 + 8)
+		case n == 39:
+			st.fg = ""
+		case n == 49:
+			st.bg = ""
+		case n == 38 || n == 48:
+			color, used := extendedColor(p[i+1:])
+			i += used
+			if n == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+		}
+	}
+	return st
+}
+
+// extendedColor reads the arguments of 38/48: 5;n or 2;r;g;b.
+func extendedColor(p []string) (string, int) {
+	num := func(i int) int {
+		if i >= len(p) {
+			return 0
+		}
+		n, _ := strconv.Atoi(p[i])
+		return max(0, min(255, n))
+	}
+	if len(p) == 0 {
+		return "", 0
+	}
+	switch p[0] {
+	case "5":
+		return palette(num(1)), 2
+	case "2":
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), 4
+	}
+	return "", 1
+}
+
+var basic = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// palette maps an xterm 256-color index to RGB.
+func palette(n int) string {
+	switch {
+	case n < 16:
+		return basic[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		v := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+	}
+}
@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const frame = "\x1b[1;38;5;203mREC\x1b[0m tape \x1b[7mA\x1b[27m  \n\x1b]8;;https://x\x1b\\link\x1b]8;;\x1b\\ \x1b[48;2;16;32;48m<&>\x1b[m\r\n"
+
+func TestStripRemovesEscapes(t *testing.T) {
+	t.Parallel()
+
+	if got, want := Strip(frame), "REC tape A\nlink <&>\n"; got != want {
+		t.Fatalf("Strip = %q, want %q", got, want)
+	}
+}
+
+func TestSVGKeepsColors(t *testing.T) {
+	t.Parallel()
+
+	svg := string(SVG(frame))
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("invalid svg: %v\n%s", err, svg)
+	}
+	for _, want := range []string{
+		`fill="#ff5f5f" font-weight="bold">REC</text>`,
+		`fill="#1c1c1c">A</text>`,
+		`fill="#102030"`,
+		`&lt;&amp;&gt;`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg is missing %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestWriteSavesAllFormats(t *testing.T) {
+	t.Parallel()
+
+	dir := Dir(t.TempDir())
+	paths, err := Write(dir, "deck", frame, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(paths) != 3 || filepath.Base(paths[2]) != "deck_20261016_093000.svg" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	if buf, _ := os.ReadFile(paths[0]); string(buf) != frame {
+		t.Fatalf("ansi file does not match the frame")
+	}
+}
@@ -0,0 +1,65 @@
+// Package changelog is the release history shown in the deck's what's-new
+// overlay. Add an entry at the top of Releases when bumping version.Deck.
+package changelog
+
+import "github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
+
+type Release struct {
+	Version  string
+	Features []string
+	// Keys lists new or changed keybindings as "key: action".
+	Keys []string
+}
+
+// Releases is newest first.
+var Releases = []Release{
+	{
+		Version: "0.3.0",
+		Features: []string{
+			"Output metadata from ffprobe and requires_alpha checks",
+			"Share the last output as a GIF or WebP at small, medium or large presets",
+			"Per-tape run statistics in the metadata panel",
+			"Tape templates, duplicating tapes and retiring tapes with disabled: true",
+			"Shelf sort modes that persist between launches",
+			"Shape-based status glyphs, --plain mode and Spanish translations",
+		},
+		Keys: []string{"g: share gif", "G: cycle gif preset", "c: duplicate tape", ".: show/hide disabled tapes", "s: cycle shelf sort", "w: what's new"},
+	},
+	{
+		Version: "0.2.0",
+		Features: []string{
+			"Render queue: play while a run is active to queue it, and resume pending renders after a restart",
+			"Session restore and crash reports",
+			"Edit manifests in $EDITOR with vcr check validation",
+			"Batch renders from CSV or JSON rows, and tape-deck doctor",
+			"Progress bars and stall detection",
+		},
+		Keys: []string{"e: edit manifest"},
+	},
+	{
+		Version:  "0.1.0",
+		Features: []string{"Tape shelf, insert/eject, play, preview frame, dry run and JSON run records"},
+	},
+}
+
+// Since returns the releases newer than lastSeen, up to the running build,
+// newest first. An unparseable lastSeen counts as older than every release.
+func Since(lastSeen string) []Release {
+	current, err := version.Parse(version.Deck)
+	if err != nil {
+		return nil
+	}
+	seen, seenErr := version.Parse(lastSeen)
+	var out []Release
+	for _, r := range Releases {
+		v, err := version.Parse(r.Version)
+		if err != nil || v.Compare(current) > 0 {
+			continue
+		}
+		if seenErr == nil && v.Compare(seen) <= 0 {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
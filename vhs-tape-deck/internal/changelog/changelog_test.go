@@ -0,0 +1,43 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
+)
+
+func TestReleasesAreNewestFirstAndMatchDeck(t *testing.T) {
+	t.Parallel()
+
+	if Releases[0].Version != version.Deck {
+		t.Fatalf("top release %s does not match version.Deck %s", Releases[0].Version, version.Deck)
+	}
+	for i := 1; i < len(Releases); i++ {
+		prev, err := version.Parse(Releases[i-1].Version)
+		if err != nil {
+			t.Fatalf("release %d: %v", i-1, err)
+		}
+		cur, err := version.Parse(Releases[i].Version)
+		if err != nil {
+			t.Fatalf("release %d: %v", i, err)
+		}
+		if prev.Compare(cur) <= 0 {
+			t.Fatalf("releases out of order: %s before %s", prev, cur)
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	t.Parallel()
+
+	if got := Since(version.Deck); len(got) != 0 {
+		t.Fatalf("expected nothing new for the current version, got %d releases", len(got))
+	}
+	got := Since("0.1.0")
+	if len(got) != 2 || got[0].Version != "0.3.0" || got[1].Version != "0.2.0" {
+		t.Fatalf("unexpected releases since 0.1.0: %+v", got)
+	}
+	if got := Since("garbage"); len(got) != len(Releases) {
+		t.Fatalf("expected every release for an unparseable version, got %d", len(got))
+	}
+}
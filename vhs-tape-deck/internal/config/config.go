@@ -3,18 +3,31 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/artpack"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/i18n"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
 )
 
 const (
 	DefaultAppDirName = "vhs-tape-deck"
 	DefaultConfigName = "config.yaml"
+
+	DefaultStallSeconds = 60
+	DefaultLineBufferKB = 64
+	DefaultMinFreeMB    = 2048
+	DefaultTickRate     = 16
+	maxLineBufferKB     = 16 * 1024
+	maxTickRate         = 60
 )
 
 type Mode string
@@ -46,7 +59,110 @@ type Config struct {
 	ProjectRoot string            `yaml:"project_root"`
 	RunsDir     string            `yaml:"runs_dir"`
 	Env         map[string]string `yaml:"env"`
+	Watchdog    Watchdog          `yaml:"watchdog,omitempty"`
+	Logs        Logs              `yaml:"logs,omitempty"`
+	Disk        Disk              `yaml:"disk,omitempty"`
+	Build       Build             `yaml:"build,omitempty"`
+	UI          UI                `yaml:"ui,omitempty"`
+	Templates   map[string]Tape   `yaml:"templates,omitempty"`
 	Tapes       []Tape            `yaml:"tapes"`
+	Pipelines   []Pipeline        `yaml:"pipelines,omitempty"`
+	Schedules   []Schedule        `yaml:"schedules,omitempty"`
+	Notify      Notify            `yaml:"notify,omitempty"`
+
+	// VCRMinVersion is the oldest vcr release this config supports; renders
+	// are refused when `vcr --version` reports something older.
+	VCRMinVersion string `yaml:"vcr_min_version,omitempty"`
+
+	// Path is the file the config was loaded from; it is not serialized.
+	Path string `yaml:"-"`
+	// Warnings lists keys in the file that nothing reads, each with its
+	// line and column.
+	Warnings []string `yaml:"-"`
+}
+
+// Watchdog controls stall detection for render processes. A run that prints
+// nothing for StallSeconds is reported as stalled; if KillSeconds is set, it
+// is terminated once the silence reaches that threshold. StallSeconds is nil
+// when the key is absent, so 0 can turn stall warnings off.
+type Watchdog struct {
+	StallSeconds *int `yaml:"stall_seconds,omitempty"`
+	KillSeconds  int  `yaml:"kill_seconds,omitempty"`
+}
+
+// StallAfter is how long a run may stay silent before it is reported as
+// stalled, DefaultStallSeconds when unset, or 0 when warnings are off.
+func (w Watchdog) StallAfter() time.Duration {
+	if w.StallSeconds == nil {
+		return DefaultStallSeconds * time.Second
+	}
+	return time.Duration(*w.StallSeconds) * time.Second
+}
+
+// Logs controls how render output is streamed. A line longer than
+// LineBufferKB is split into chunks of that size.
+type Logs struct {
+	LineBufferKB int `yaml:"line_buffer_kb,omitempty"`
+}
+
+// Disk sets the free-space floor for renders. Starting a render on a
+// volume with less than MinFreeMB free asks for confirmation first; a
+// negative value turns the check off.
+type Disk struct {
+	MinFreeMB int `yaml:"min_free_mb,omitempty"`
+}
+
+// MinFreeBytes is the free-space floor in bytes, or 0 when the check is off.
+func (d Disk) MinFreeBytes() uint64 {
+	if d.MinFreeMB <= 0 {
+		return 0
+	}
+	return uint64(d.MinFreeMB) << 20
+}
+
+// Build rebuilds vcr from source before renders. Command runs in Dir
+// (default project_root) whenever a file under Watch has changed since the
+// last successful build, or before every render when Watch is empty.
+type Build struct {
+	Command []string `yaml:"command,omitempty"`
+	Watch   []string `yaml:"watch,omitempty"`
+	Dir     string   `yaml:"dir,omitempty"`
+}
+
+// Enabled reports whether a build command is configured.
+func (b Build) Enabled() bool {
+	return len(b.Command) > 0
+}
+
+// UI holds display settings for the tape deck.
+type UI struct {
+	// StatusGlyphs is "dots" (colored dots), "unicode" (✓ ✗ ▶ ○ ◆) or
+	// "ascii" (+ x > o *). Empty means dots, or unicode when NO_COLOR is set.
+	StatusGlyphs string `yaml:"status_glyphs,omitempty"`
+	// CassetteColor is "on" or "off" for the colored cassette art. Empty
+	// means on unless NO_COLOR is set.
+	CassetteColor string `yaml:"cassette_color,omitempty"`
+	// Animation is "full", "reduced" (no shimmer, spinning reels or shake)
+	// or "off" (no motion at all). Empty means full.
+	Animation string `yaml:"animation,omitempty"`
+	// TickRate is how many times a second the deck redraws.
+	TickRate int `yaml:"tick_rate,omitempty"`
+	// Locale selects the message catalog ("en", "es"). Empty follows
+	// LC_ALL, LC_MESSAGES and LANG.
+	Locale string `yaml:"locale,omitempty"`
+	// Sounds plays feedback when a tape is inserted, ejected or finishes.
+	Sounds Sounds `yaml:"sounds,omitempty"`
+}
+
+// SoundBell as a sound rings the terminal bell instead of running a
+// command.
+const SoundBell = "bell"
+
+// Sounds holds one command per deck event, run without a shell.
+type Sounds struct {
+	Insert []string `yaml:"insert,omitempty"`
+	Eject  []string `yaml:"eject,omitempty"`
+	Finish []string `yaml:"finish,omitempty"`
 }
 
 type Tape struct {
@@ -59,6 +175,18 @@ type Tape struct {
 	Preview     Preview   `yaml:"preview"`
 	Aesthetic   Aesthetic `yaml:"aesthetic,omitempty"`
 	Notes       string    `yaml:"notes,omitempty"`
+	// RequiresAlpha fails a run whose output has no alpha channel.
+	RequiresAlpha bool `yaml:"requires_alpha,omitempty"`
+	// Template names an entry under templates whose fields this tape inherits
+	// unless it sets them itself.
+	Template string `yaml:"template,omitempty"`
+	// Disabled retires a tape: it stays in config so its run records keep
+	// their context, but the shelf hides it by default.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// Golden holds the reference frames checked by the verify action.
+	Golden Golden `yaml:"golden,omitempty"`
+	// Upload copies outputs elsewhere after a successful render.
+	Upload Upload `yaml:"upload,omitempty"`
 }
 
 type Preview struct {
@@ -70,6 +198,8 @@ type Preview struct {
 type Aesthetic struct {
 	LabelStyle    LabelStyle    `yaml:"label_style,omitempty"`
 	ShellColorway ShellColorway `yaml:"shell_colorway,omitempty"`
+	// Art is a built-in art pack or a path to a template file.
+	Art string `yaml:"art,omitempty"`
 }
 
 func DefaultConfigPath() (string, error) {
@@ -77,7 +207,14 @@ func DefaultConfigPath() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("resolve user config dir: %w", err)
 	}
-	return filepath.Join(base, DefaultAppDirName, DefaultConfigName), nil
+	dir := filepath.Join(base, DefaultAppDirName)
+	// A JSON or TOML config is used when it is the only one there.
+	for _, name := range []string{DefaultConfigName, "config.json", "config.toml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name), nil
+		}
+	}
+	return filepath.Join(dir, DefaultConfigName), nil
 }
 
 func ConfigDir(configPath string) string {
@@ -86,19 +223,97 @@ func ConfigDir(configPath string) string {
 
 func Load(configPath, launchCWD string) (*Config, error) {
 	buf, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, hint.Wrap(fmt.Errorf("%w: %s", ErrConfigMissing, configPath), "run `tape-deck init` to write a starter config, or pass --config")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
+	return parse(buf, configPath, launchCWD)
+}
+
+// parse is Load for config text that is not read from configPath, such as
+// an edit that has not been written yet.
+func parse(buf []byte, configPath, launchCWD string) (*Config, error) {
+	format := FormatOf(configPath)
+	doc, err := decodeDocument(buf, format)
+	if err != nil {
+		return nil, err
+	}
 	var cfg Config
-	if err := yaml.Unmarshal(buf, &cfg); err != nil {
-		return nil, fmt.Errorf("parse yaml: %w", err)
+	if err := doc.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", format, err)
+	}
+	file := filepath.Base(configPath)
+	warnings := unknownKeys(doc, file)
+	if err := applyTemplates(doc, &cfg); err != nil {
+		return nil, err
 	}
 	if err := ApplyDefaults(&cfg, configPath, launchCWD); err != nil {
+		var fe *fieldError
+		if errors.As(err, &fe) {
+			if line, col := position(doc, fe.path); line > 0 {
+				err = fmt.Errorf("%s:%d:%d: %w", file, line, col, err)
+			}
+		}
+		// A misspelled key is the usual reason a required one is missing.
+		if len(warnings) > 0 {
+			err = fmt.Errorf("%w\n  %s", err, strings.Join(warnings, "\n  "))
+		}
 		return nil, err
 	}
+	cfg.Path = configPath
+	cfg.Warnings = warnings
 	return &cfg, nil
 }
 
+// LoadStrict is Load, except that unknown keys are an error rather than
+// warnings.
+func LoadStrict(configPath, launchCWD string) (*Config, error) {
+	cfg, err := Load(configPath, launchCWD)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Warnings) > 0 {
+		return nil, hint.Wrap(fmt.Errorf("%w:\n  %s", ErrUnknownKeys, strings.Join(cfg.Warnings, "\n  ")), "fix or remove these keys, or drop --strict to only warn")
+	}
+	return cfg, nil
+}
+
+// applyTemplates re-decodes every tape that names a template on top of that
+// template, so only the keys the tape actually sets override it.
+func applyTemplates(doc *yaml.Node, cfg *Config) error {
+	var raw struct {
+		Templates map[string]yaml.Node `yaml:"templates"`
+		Tapes     []yaml.Node          `yaml:"tapes"`
+	}
+	if err := doc.Decode(&raw); err != nil {
+		return fmt.Errorf("parse templates: %w", err)
+	}
+	for i := range cfg.Tapes {
+		name := cfg.Tapes[i].Template
+		if name == "" {
+			continue
+		}
+		tmpl, ok := raw.Templates[name]
+		if !ok {
+			return hint.Wrap(fmt.Errorf("tape %q: %w %q", cfg.Tapes[i].ID, ErrUnknownTemplate, name), "define it under templates: or fix the tape's template name")
+		}
+		var merged Tape
+		if err := tmpl.Decode(&merged); err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+		if merged.Template != "" {
+			return fmt.Errorf("template %q: templates cannot inherit from other templates", name)
+		}
+		if err := raw.Tapes[i].Decode(&merged); err != nil {
+			return fmt.Errorf("tape %q: %w", cfg.Tapes[i].ID, err)
+		}
+		cfg.Tapes[i] = merged
+	}
+	return nil
+}
+
 func ApplyDefaults(cfg *Config, configPath, launchCWD string) error {
 	if cfg == nil {
 		return errors.New("config is nil")
@@ -142,6 +357,36 @@ func ApplyDefaults(cfg *Config, configPath, launchCWD string) error {
 		cfg.Env = map[string]string{}
 	}
 
+	if cfg.Logs.LineBufferKB == 0 {
+		cfg.Logs.LineBufferKB = DefaultLineBufferKB
+	}
+
+	if cfg.Disk.MinFreeMB == 0 {
+		cfg.Disk.MinFreeMB = DefaultMinFreeMB
+	}
+
+	if cfg.UI.TickRate == 0 {
+		cfg.UI.TickRate = DefaultTickRate
+	}
+
+	if cfg.Build.Enabled() {
+		if strings.TrimSpace(cfg.Build.Dir) == "" {
+			cfg.Build.Dir = cfg.ProjectRoot
+		}
+		buildDir, err := ResolvePath(cfg.Build.Dir, cfg.ProjectRoot)
+		if err != nil {
+			return fmt.Errorf("resolve build.dir: %w", err)
+		}
+		cfg.Build.Dir = buildDir
+		for i, p := range cfg.Build.Watch {
+			watch, err := ResolvePath(p, buildDir)
+			if err != nil {
+				return fmt.Errorf("resolve build.watch %q: %w", p, err)
+			}
+			cfg.Build.Watch[i] = watch
+		}
+	}
+
 	for i := range cfg.Tapes {
 		t := &cfg.Tapes[i]
 		if strings.TrimSpace(t.Name) == "" {
@@ -166,6 +411,13 @@ func ApplyDefaults(cfg *Config, configPath, launchCWD string) error {
 		if t.Aesthetic.ShellColorway == "" {
 			t.Aesthetic.ShellColorway = ShellColorwayBlack
 		}
+		if t.Aesthetic.Art != "" && !artpack.IsBuiltin(t.Aesthetic.Art) {
+			art, err := ResolvePath(t.Aesthetic.Art, cfg.ProjectRoot)
+			if err != nil {
+				return fmt.Errorf("resolve aesthetic.art for %q: %w", t.ID, err)
+			}
+			t.Aesthetic.Art = art
+		}
 	}
 
 	if err := Validate(cfg); err != nil {
@@ -223,13 +475,65 @@ func Validate(cfg *Config) error {
 	}
 	outputFlag := strings.TrimSpace(cfg.OutputFlag)
 	if outputFlag == "" {
-		return errors.New("output_flag is required")
+		return at("output_flag", errors.New("output_flag is required"))
 	}
 	if !strings.HasPrefix(outputFlag, "-") {
-		return fmt.Errorf("output_flag must start with '-': %q", cfg.OutputFlag)
+		return at("output_flag", fmt.Errorf("output_flag must start with '-': %q", cfg.OutputFlag))
+	}
+	if cfg.Watchdog.StallSeconds != nil && *cfg.Watchdog.StallSeconds < 0 {
+		return at("watchdog.stall_seconds", fmt.Errorf("watchdog.stall_seconds must be >= 0: %d", *cfg.Watchdog.StallSeconds))
+	}
+	if cfg.Watchdog.KillSeconds < 0 {
+		return at("watchdog.kill_seconds", fmt.Errorf("watchdog.kill_seconds must be >= 0: %d", cfg.Watchdog.KillSeconds))
+	}
+	if stall := cfg.Watchdog.StallAfter(); cfg.Watchdog.KillSeconds > 0 && stall > 0 && time.Duration(cfg.Watchdog.KillSeconds)*time.Second <= stall {
+		return at("watchdog.kill_seconds", fmt.Errorf("watchdog.kill_seconds (%d) must be greater than stall_seconds (%d)", cfg.Watchdog.KillSeconds, int(stall/time.Second)))
+	}
+	if cfg.Logs.LineBufferKB < 0 || cfg.Logs.LineBufferKB > maxLineBufferKB {
+		return at("logs.line_buffer_kb", fmt.Errorf("logs.line_buffer_kb must be between 1 and %d: %d", maxLineBufferKB, cfg.Logs.LineBufferKB))
+	}
+	switch cfg.UI.StatusGlyphs {
+	case "", "dots", "unicode", "ascii":
+	default:
+		return at("ui.status_glyphs", fmt.Errorf("ui.status_glyphs must be dots, unicode or ascii: %q", cfg.UI.StatusGlyphs))
+	}
+	switch cfg.UI.CassetteColor {
+	case "", "on", "off":
+	default:
+		return at("ui.cassette_color", fmt.Errorf("ui.cassette_color must be on or off: %q", cfg.UI.CassetteColor))
+	}
+	switch cfg.UI.Animation {
+	case "", "full", "reduced", "off":
+	default:
+		return at("ui.animation", fmt.Errorf("ui.animation must be full, reduced or off: %q", cfg.UI.Animation))
+	}
+	if cfg.UI.TickRate < 0 || cfg.UI.TickRate > maxTickRate {
+		return at("ui.tick_rate", fmt.Errorf("ui.tick_rate must be between 1 and %d: %d", maxTickRate, cfg.UI.TickRate))
+	}
+	sounds := []struct {
+		name    string
+		command []string
+	}{{"insert", cfg.UI.Sounds.Insert}, {"eject", cfg.UI.Sounds.Eject}, {"finish", cfg.UI.Sounds.Finish}}
+	for _, s := range sounds {
+		if len(s.command) > 0 && strings.TrimSpace(s.command[0]) == "" {
+			return at("ui.sounds."+s.name, fmt.Errorf("ui.sounds.%s: command is empty", s.name))
+		}
+	}
+	if !cfg.Build.Enabled() && (len(cfg.Build.Watch) > 0 || cfg.Build.Dir != "") {
+		return at("build", errors.New("build.command is required when build.watch or build.dir is set"))
+	}
+	if cfg.VCRMinVersion != "" {
+		if _, err := version.Parse(cfg.VCRMinVersion); err != nil {
+			return at("vcr_min_version", fmt.Errorf("vcr_min_version: %w", err))
+		}
+	}
+	if cfg.UI.Locale != "" {
+		if _, ok := i18n.Normalize(cfg.UI.Locale); !ok {
+			return at("ui.locale", fmt.Errorf("ui.locale must be one of %s: %q", strings.Join(i18n.Locales(), ", "), cfg.UI.Locale))
+		}
 	}
 	if len(cfg.Tapes) == 0 {
-		return errors.New("config requires at least one tape")
+		return hint.Wrap(ErrNoTapes, "add a tape under tapes:, or run `tape-deck init --force` for a starter config")
 	}
 
 	seen := map[string]struct{}{}
@@ -246,23 +550,23 @@ func Validate(cfg *Config) error {
 
 	for i, t := range cfg.Tapes {
 		if strings.TrimSpace(t.ID) == "" {
-			return fmt.Errorf("tapes[%d]: id is required", i)
+			return at(fmt.Sprintf("tapes.%d", i), fmt.Errorf("tapes[%d]: id is required", i))
 		}
 		if _, ok := seen[t.ID]; ok {
-			return fmt.Errorf("duplicate tape id: %s", t.ID)
+			return at(fmt.Sprintf("tapes.%d.id", i), hint.Wrap(fmt.Errorf("%w: %s", ErrDuplicateTape, t.ID), "give each tape a unique id"))
 		}
 		seen[t.ID] = struct{}{}
 
 		if strings.TrimSpace(t.Manifest) == "" {
-			return fmt.Errorf("tape %q: manifest is required", t.ID)
+			return at(fmt.Sprintf("tapes.%d", i), fmt.Errorf("tape %q: manifest is required", t.ID))
 		}
 
 		if t.Mode != ModeVideo && t.Mode != ModeFrame {
-			return fmt.Errorf("tape %q: mode must be %q or %q", t.ID, ModeVideo, ModeFrame)
+			return at(fmt.Sprintf("tapes.%d.mode", i), fmt.Errorf("tape %q: mode must be %q or %q", t.ID, ModeVideo, ModeFrame))
 		}
 
 		if t.Preview.Enabled && t.Preview.Frame < 0 {
-			return fmt.Errorf("tape %q: preview frame must be >= 0", t.ID)
+			return at(fmt.Sprintf("tapes.%d.preview.frame", i), fmt.Errorf("tape %q: preview frame must be >= 0", t.ID))
 		}
 
 		if _, ok := validLabelStyles[t.Aesthetic.LabelStyle]; !ok {
@@ -271,7 +575,7 @@ func Validate(cfg *Config) error {
 				values = append(values, string(k))
 			}
 			sort.Strings(values)
-			return fmt.Errorf("tape %q: invalid label_style %q (valid: %s)", t.ID, t.Aesthetic.LabelStyle, strings.Join(values, ", "))
+			return at(fmt.Sprintf("tapes.%d.aesthetic.label_style", i), fmt.Errorf("tape %q: invalid label_style %q (valid: %s)", t.ID, t.Aesthetic.LabelStyle, strings.Join(values, ", ")))
 		}
 
 		if _, ok := validShells[t.Aesthetic.ShellColorway]; !ok {
@@ -280,133 +584,23 @@ func Validate(cfg *Config) error {
 				values = append(values, string(k))
 			}
 			sort.Strings(values)
-			return fmt.Errorf("tape %q: invalid shell_colorway %q (valid: %s)", t.ID, t.Aesthetic.ShellColorway, strings.Join(values, ", "))
+			return at(fmt.Sprintf("tapes.%d.aesthetic.shell_colorway", i), fmt.Errorf("tape %q: invalid shell_colorway %q (valid: %s)", t.ID, t.Aesthetic.ShellColorway, strings.Join(values, ", ")))
 		}
-	}
 
-	return nil
-}
-
-func WriteStarterConfig(configPath, launchCWD string, overwrite bool) error {
-	if strings.TrimSpace(configPath) == "" {
-		var err error
-		configPath, err = DefaultConfigPath()
-		if err != nil {
-			return err
+		if _, err := artpack.Load(t.Aesthetic.Art); err != nil {
+			return at(fmt.Sprintf("tapes.%d.aesthetic.art", i), hint.Wrap(fmt.Errorf("tape %q: %w", t.ID, err), "use a built-in pack ("+strings.Join(artpack.Names(), ", ")+") or a path to a template file"))
 		}
-	}
 
-	if _, err := os.Stat(configPath); err == nil && !overwrite {
-		return fmt.Errorf("config already exists at %s", configPath)
-	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("check config path: %w", err)
+		if err := validateGolden(t); err != nil {
+			return at(fmt.Sprintf("tapes.%d.golden", i), err)
+		}
+		if err := validateUpload(t); err != nil {
+			return at(fmt.Sprintf("tapes.%d.upload", i), err)
+		}
 	}
 
-	cfg := StarterConfig(launchCWD)
-	if err := ApplyDefaults(&cfg, configPath, launchCWD); err != nil {
+	if err := validatePipelines(cfg); err != nil {
 		return err
 	}
-
-	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
-		return fmt.Errorf("create config dir: %w", err)
-	}
-	if err := os.MkdirAll(cfg.RunsDir, 0o755); err != nil {
-		return fmt.Errorf("create runs dir: %w", err)
-	}
-
-	buf, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("marshal config: %w", err)
-	}
-	if err := os.WriteFile(configPath, buf, 0o644); err != nil {
-		return fmt.Errorf("write config: %w", err)
-	}
-	return nil
-}
-
-func StarterConfig(launchCWD string) Config {
-	if strings.TrimSpace(launchCWD) == "" {
-		launchCWD, _ = os.Getwd()
-	}
-
-	projectRoot := launchCWD
-	if runtime.GOOS == "windows" {
-		projectRoot = filepath.Clean(projectRoot)
-	}
-
-	return Config{
-		VCRBinary:   "vcr",
-		OutputFlag:  "--output",
-		ProjectRoot: projectRoot,
-		Env: map[string]string{
-			"VCR_SEED": "0",
-		},
-		Tapes: []Tape{
-			{
-				ID:       "alpha-lower-third",
-				Name:     "Alpha Lower Third",
-				Manifest: "./manifests/alpha_lower_third.yaml",
-				Mode:     ModeVideo,
-				PrimaryArgs: []string{
-					"--duration", "5",
-					"--fps", "60",
-				},
-				Preview:   Preview{Enabled: true, Frame: 48, Args: []string{"--fps", "60"}},
-				Aesthetic: Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayBlack},
-				Notes:     "Broadcast-safe lower third with alpha",
-			},
-			{
-				ID:       "neon-title",
-				Name:     "Neon Title Card",
-				Manifest: "./manifests/neon_title.yaml",
-				Mode:     ModeVideo,
-				PrimaryArgs: []string{
-					"--duration", "6",
-					"--fps", "60",
-				},
-				Preview:   Preview{Enabled: true, Frame: 90, Args: []string{"--quality", "draft"}},
-				Aesthetic: Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayGray},
-				Notes:     "CRT glow and scanline feel",
-			},
-			{
-				ID:       "frame-poster",
-				Name:     "Poster Frame",
-				Manifest: "./manifests/poster_frame.yaml",
-				Mode:     ModeFrame,
-				PrimaryArgs: []string{
-					"--frame", "160",
-				},
-				Preview:   Preview{Enabled: true, Frame: 120, Args: []string{"--quality", "draft"}},
-				Aesthetic: Aesthetic{LabelStyle: LabelStyleHandwritten, ShellColorway: ShellColorwayClear},
-				Notes:     "High detail still export",
-			},
-			{
-				ID:       "pack-y2k",
-				Name:     "Y2K Pack Probe",
-				Manifest: "./manifests/pack_y2k.yaml",
-				Mode:     ModeVideo,
-				PrimaryArgs: []string{
-					"--duration", "4",
-					"--fps", "60",
-					"--seed", "0",
-				},
-				Preview:   Preview{Enabled: true, Frame: 36, Args: []string{"--seed", "0"}},
-				Aesthetic: Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayBlack},
-				Notes:     "Pack-driven scene validation",
-			},
-			{
-				ID:       "debug-safe-mode",
-				Name:     "Debug Safe Mode",
-				Manifest: "./manifests/debug_safe.yaml",
-				Mode:     ModeFrame,
-				PrimaryArgs: []string{
-					"--frame", "0",
-					"--seed", "0",
-				},
-				Preview:   Preview{Enabled: true, Frame: 0, Args: []string{"--seed", "0"}},
-				Aesthetic: Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayGray},
-				Notes:     "Deterministic sanity checks",
-			},
-		},
-	}
+	return validateSchedules(cfg)
 }
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
 )
 
 func TestApplyDefaults(t *testing.T) {
@@ -22,7 +26,8 @@ func TestApplyDefaults(t *testing.T) {
 		}},
 	}
 
-	if err := ApplyDefaults(cfg, cfgPath, "/workspace/project"); err != nil {
+	project := filepath.Join(tmp, "workspace", "project")
+	if err := ApplyDefaults(cfg, cfgPath, project); err != nil {
 		t.Fatalf("ApplyDefaults: %v", err)
 	}
 
@@ -32,7 +37,7 @@ func TestApplyDefaults(t *testing.T) {
 	if cfg.OutputFlag != "--output" {
 		t.Fatalf("unexpected output flag default: %s", cfg.OutputFlag)
 	}
-	if cfg.ProjectRoot != "/workspace/project" {
+	if cfg.ProjectRoot != project {
 		t.Fatalf("unexpected project root: %s", cfg.ProjectRoot)
 	}
 	if cfg.RunsDir != filepath.Join(tmp, "runs") {
@@ -41,6 +46,40 @@ func TestApplyDefaults(t *testing.T) {
 	if cfg.Tapes[0].OutputDir != filepath.Join(tmp, "runs", "alpha") {
 		t.Fatalf("unexpected output dir: %s", cfg.Tapes[0].OutputDir)
 	}
+	if cfg.Logs.LineBufferKB != DefaultLineBufferKB {
+		t.Fatalf("unexpected line buffer default: %d", cfg.Logs.LineBufferKB)
+	}
+	if cfg.Disk.MinFreeBytes() != DefaultMinFreeMB<<20 {
+		t.Fatalf("unexpected disk floor default: %d", cfg.Disk.MinFreeMB)
+	}
+	if cfg.UI.TickRate != DefaultTickRate {
+		t.Fatalf("unexpected tick rate default: %d", cfg.UI.TickRate)
+	}
+}
+
+func TestApplyDefaultsResolvesBuildPaths(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	cfg := &Config{
+		Build: Build{Command: []string{"cargo", "build"}, Dir: "..", Watch: []string{"src", "Cargo.toml"}},
+		Tapes: []Tape{{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo}},
+	}
+	if err := ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), project); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if cfg.Build.Dir != tmp || cfg.Build.Watch[0] != filepath.Join(tmp, "src") {
+		t.Fatalf("unexpected build paths: %+v", cfg.Build)
+	}
+
+	cfg = &Config{
+		Build: Build{Watch: []string{"src"}},
+		Tapes: []Tape{{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo}},
+	}
+	if err := ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), project); err == nil || !strings.Contains(err.Error(), "build.command") {
+		t.Fatalf("expected missing build.command error, got %v", err)
+	}
 }
 
 func TestLoadAndValidateDuplicateTapeID(t *testing.T) {
@@ -69,11 +108,20 @@ func TestLoadAndValidateDuplicateTapeID(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected duplicate tape id error")
 	}
-	if !strings.Contains(err.Error(), "duplicate tape id") {
+	if !strings.Contains(err.Error(), "duplicate tape id") || !errors.Is(err, ErrDuplicateTape) || hint.Of(err) == "" {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
+func TestLoadMissingConfigHintsAtInit(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load(filepath.Join(t.TempDir(), "config.yaml"), "")
+	if !errors.Is(err, ErrConfigMissing) || !strings.Contains(hint.Of(err), "tape-deck init") {
+		t.Fatalf("expected missing config hint, got %v (hint %q)", err, hint.Of(err))
+	}
+}
+
 func TestResolvePath(t *testing.T) {
 	t.Parallel()
 
@@ -88,6 +136,33 @@ func TestResolvePath(t *testing.T) {
 	}
 }
 
+func TestResolvePathHomeAndAbsolute(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home dir: %v", err)
+	}
+	for _, value := range []string{"~/renders", `~\renders`} {
+		resolved, err := ResolvePath(value, t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolvePath(%q): %v", value, err)
+		}
+		if want := filepath.Join(home, "renders"); resolved != want {
+			t.Fatalf("ResolvePath(%q): expected %s got %s", value, want, resolved)
+		}
+	}
+
+	abs := filepath.Join(t.TempDir(), "out dir", "tape.yaml")
+	resolved, err := ResolvePath(abs, t.TempDir())
+	if err != nil {
+		t.Fatalf("ResolvePath: %v", err)
+	}
+	if resolved != abs {
+		t.Fatalf("expected absolute path to pass through, got %s", resolved)
+	}
+}
+
 func TestWriteStarterConfig(t *testing.T) {
 	t.Parallel()
 
@@ -135,3 +210,84 @@ func TestValidateOutputFlag(t *testing.T) {
 		t.Fatalf("expected output_flag validation error")
 	}
 }
+
+func TestApplyDefaultsArtPack(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "deck.txt"), []byte("+--+\n|@@|\n+--+\n"), 0o644); err != nil {
+		t.Fatalf("write art: %v", err)
+	}
+	cfg := &Config{Tapes: []Tape{
+		{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo, Aesthetic: Aesthetic{Art: "betamax"}},
+		{ID: "beta", Manifest: "./b.yaml", Mode: ModeVideo, Aesthetic: Aesthetic{Art: "deck.txt"}},
+	}}
+	if err := ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if cfg.Tapes[0].Aesthetic.Art != "betamax" || cfg.Tapes[1].Aesthetic.Art != filepath.Join(tmp, "deck.txt") {
+		t.Fatalf("unexpected art: %q, %q", cfg.Tapes[0].Aesthetic.Art, cfg.Tapes[1].Aesthetic.Art)
+	}
+
+	cfg = &Config{Tapes: []Tape{{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo, Aesthetic: Aesthetic{Art: "betamx"}}}}
+	err := ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp)
+	if err == nil || !strings.Contains(err.Error(), "betamx") || !strings.Contains(hint.Of(err), "reel-to-reel") {
+		t.Fatalf("expected art error with hint, got %v", err)
+	}
+}
+
+func TestValidateUI(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfg := &Config{
+		UI:    UI{Sounds: Sounds{Insert: []string{SoundBell}, Finish: []string{"afplay", "done.aiff"}}},
+		Tapes: []Tape{{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo}},
+	}
+	if err := ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	cfg.UI.Animation = "slow"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "ui.animation") {
+		t.Fatalf("expected animation error, got %v", err)
+	}
+	cfg.UI.Animation = "reduced"
+	cfg.UI.TickRate = 120
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "ui.tick_rate") {
+		t.Fatalf("expected tick rate error, got %v", err)
+	}
+	cfg.UI.TickRate = 8
+	cfg.UI.Sounds.Eject = []string{" "}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "ui.sounds.eject") {
+		t.Fatalf("expected empty eject sound error, got %v", err)
+	}
+}
+
+func TestStallSecondsZeroTurnsWarningsOff(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	tapes := `tapes:
+  - id: alpha
+    manifest: ./manifests/a.yaml
+    mode: frame
+`
+	for watchdog, want := range map[string]time.Duration{
+		"":                               DefaultStallSeconds * time.Second,
+		"watchdog: {stall_seconds: 0}\n": 0,
+		"watchdog: {stall_seconds: 0, kill_seconds: 30}\n": 0,
+		"watchdog: {stall_seconds: 5}\n":                   5 * time.Second,
+	} {
+		if err := os.WriteFile(cfgPath, []byte(watchdog+tapes), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		cfg, err := Load(cfgPath, tmp)
+		if err != nil {
+			t.Fatalf("Load %q: %v", watchdog, err)
+		}
+		if got := cfg.Watchdog.StallAfter(); got != want {
+			t.Fatalf("%q: expected stall after %s, got %s", watchdog, want, got)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+)
+
+// Duplicate describes a tape copy. Empty Name and Manifest are derived from
+// the source tape.
+type Duplicate struct {
+	SourceID string
+	ID       string
+	Name     string
+	Manifest string
+}
+
+// DuplicateTape appends a copy of a tape to the config file at configPath.
+// The copy is made on the YAML tree, so comments, template references and
+// relative paths are kept as written. The source manifest is copied to the
+// new manifest path unless that file already exists. It returns the new
+// manifest path as written in the config. Nothing is written if the edited
+// config would not load.
+func DuplicateTape(configPath, projectRoot string, d Duplicate) (string, error) {
+	if strings.TrimSpace(d.ID) == "" {
+		return "", errors.New("new tape id is required")
+	}
+	doc, err := readDocument(configPath)
+	if err != nil {
+		return "", err
+	}
+	tapes, err := tapesNode(doc)
+	if err != nil {
+		return "", err
+	}
+
+	var source *yaml.Node
+	for _, tape := range tapes.Content {
+		switch scalarValue(tape, "id") {
+		case d.ID:
+			return "", fmt.Errorf("tape %q already exists", d.ID)
+		case d.SourceID:
+			source = tape
+		}
+	}
+	if source == nil {
+		return "", fmt.Errorf("unknown tape %q", d.SourceID)
+	}
+
+	var src Tape
+	if err := source.Decode(&src); err != nil {
+		return "", fmt.Errorf("decode tape %q: %w", d.SourceID, err)
+	}
+	if d.Name == "" {
+		d.Name = d.ID
+		if src.Name != "" {
+			d.Name = src.Name + " (copy)"
+		}
+	}
+	if d.Manifest == "" && src.Manifest != "" {
+		dir, file := filepath.Split(filepath.ToSlash(src.Manifest))
+		d.Manifest = dir + d.ID + filepath.Ext(file)
+	}
+
+	clone := cloneNode(source)
+	setScalar(clone, "id", d.ID)
+	setScalar(clone, "name", d.Name)
+	if d.Manifest != "" {
+		setScalar(clone, "manifest", d.Manifest)
+	}
+	tapes.Content = append(tapes.Content, clone)
+
+	buf, err := encodeChecked(configPath, projectRoot, doc)
+	if err != nil {
+		return "", err
+	}
+	if src.Manifest != "" && d.Manifest != src.Manifest {
+		if err := copyManifest(projectRoot, src.Manifest, d.Manifest); err != nil {
+			return "", err
+		}
+	}
+	if err := writeDocument(configPath, buf); err != nil {
+		return "", err
+	}
+	return d.Manifest, nil
+}
+
+// AddTape appends t to the config file at configPath, on the YAML tree like
+// DuplicateTape. Nothing is written if the edited config would not load.
+func AddTape(configPath, projectRoot string, t Tape) error {
+	if strings.TrimSpace(t.ID) == "" {
+		return errors.New("tape id is required")
+	}
+	doc, err := readDocument(configPath)
+	if err != nil {
+		return err
+	}
+	tapes, err := tapesNode(doc)
+	if err != nil {
+		return err
+	}
+	for _, tape := range tapes.Content {
+		if scalarValue(tape, "id") == t.ID {
+			return fmt.Errorf("tape %q already exists", t.ID)
+		}
+	}
+	var node yaml.Node
+	if err := node.Encode(t); err != nil {
+		return fmt.Errorf("encode tape %q: %w", t.ID, err)
+	}
+	tapes.Content = append(tapes.Content, &node)
+	buf, err := encodeChecked(configPath, projectRoot, doc)
+	if err != nil {
+		return err
+	}
+	return writeDocument(configPath, buf)
+}
+
+// TapeForManifest builds a shelf entry for a manifest made outside the
+// deck, such as one an agent generated. The id comes from the file name,
+// a manifest one frame long becomes a frame tape, and the preview shows
+// the middle frame.
+func TapeForManifest(cfg *Config, manifest string) (Tape, error) {
+	path, err := ResolveManifestPath(cfg.ProjectRoot, manifest)
+	if err != nil {
+		return Tape{}, fmt.Errorf("resolve manifest: %w", err)
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Tape{}, fmt.Errorf("read manifest: %w", err)
+	}
+	var m struct {
+		Environment struct {
+			FPS      float64   `yaml:"fps"`
+			Duration yaml.Node `yaml:"duration"`
+		} `yaml:"environment"`
+	}
+	if err := yaml.Unmarshal(buf, &m); err != nil {
+		return Tape{}, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	// Keep the path relative to project_root when the manifest is inside it.
+	if rel, err := filepath.Rel(cfg.ProjectRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		manifest = "./" + filepath.ToSlash(rel)
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	base = strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, base), "-")
+	if base == "" {
+		base = "tape"
+	}
+	id := base
+	taken := map[string]bool{}
+	for _, t := range cfg.Tapes {
+		taken[t.ID] = true
+	}
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	t := Tape{
+		ID:       id,
+		Name:     id,
+		Manifest: manifest,
+		Mode:     ModeVideo,
+		Preview:  Preview{Enabled: true},
+	}
+	frames := manifestFrames(m.Environment.FPS, &m.Environment.Duration)
+	switch {
+	case frames == 1:
+		t.Mode = ModeFrame
+		t.PrimaryArgs = []string{"--frame", "0"}
+	case frames > 1:
+		t.Preview.Frame = frames / 2
+	}
+	return t, nil
+}
+
+// manifestFrames reads a VCR duration, given in seconds or as
+// {frames: n}; it returns 0 when the length is unknown.
+func manifestFrames(fps float64, duration *yaml.Node) int {
+	var frames struct {
+		Frames int `yaml:"frames"`
+	}
+	if duration.Kind == yaml.MappingNode && duration.Decode(&frames) == nil {
+		return frames.Frames
+	}
+	var seconds float64
+	if duration.Kind == yaml.ScalarNode && duration.Decode(&seconds) == nil && fps > 0 {
+		return int(seconds * fps)
+	}
+	return 0
+}
+
+// NextTapeID returns base-copy, base-copy-2, ... whichever is not taken.
+func NextTapeID(cfg *Config, base string) string {
+	taken := map[string]bool{}
+	for _, t := range cfg.Tapes {
+		taken[t.ID] = true
+	}
+	id := base + "-copy"
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-copy-%d", base, n)
+	}
+	return id
+}
+
+func copyManifest(projectRoot, from, to string) error {
+	src, err := ResolveManifestPath(projectRoot, from)
+	if err != nil {
+		return fmt.Errorf("resolve manifest: %w", err)
+	}
+	dst, err := ResolveManifestPath(projectRoot, to)
+	if err != nil {
+		return fmt.Errorf("resolve new manifest: %w", err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	buf, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("create manifest dir: %w", err)
+	}
+	if err := os.WriteFile(dst, buf, 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+func readDocument(path string) (*yaml.Node, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	doc, err := decodeDocument(buf, FormatOf(path))
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("config is not a mapping")
+	}
+	return doc, nil
+}
+
+// encodeChecked encodes an edited config and loads the result, so an edit
+// that would break the config fails before anything is written.
+func encodeChecked(path, projectRoot string, doc *yaml.Node) ([]byte, error) {
+	buf, err := encodeDocument(doc, FormatOf(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parse(buf, path, projectRoot); err != nil {
+		return nil, fmt.Errorf("edited config does not load: %w", err)
+	}
+	return buf, nil
+}
+
+func writeDocument(path string, buf []byte) error {
+	if err := atomicfile.Write(path, buf); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+func tapesNode(doc *yaml.Node) (*yaml.Node, error) {
+	tapes := mappingValue(doc.Content[0], "tapes")
+	if tapes == nil || tapes.Kind != yaml.SequenceNode {
+		return nil, errors.New("config has no tapes list")
+	}
+	return tapes, nil
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarValue(m *yaml.Node, key string) string {
+	if v := mappingValue(m, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+func setScalar(m *yaml.Node, key, value string) {
+	if v := mappingValue(m, key); v != nil {
+		*v = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+		return
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+}
+
+func cloneNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneNode(child)
+	}
+	return &c
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const templatedConfig = `# studio tapes
+templates:
+  broadcast:
+    mode: video
+    primary_args: ["--fps", "60"]
+    preview: {enabled: true, frame: 24}
+    requires_alpha: true
+    aesthetic: {label_style: noisy}
+tapes:
+  - id: lower-third
+    template: broadcast
+    manifest: ./manifests/lower_third.yaml
+    preview: {frame: 48}
+  - id: plain
+    manifest: ./manifests/plain.yaml
+    mode: frame
+`
+
+func TestLoadAppliesTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(templatedConfig), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tape := cfg.Tapes[0]
+	if tape.Mode != ModeVideo || !tape.RequiresAlpha || strings.Join(tape.PrimaryArgs, " ") != "--fps 60" {
+		t.Fatalf("template fields not inherited: %+v", tape)
+	}
+	if !tape.Preview.Enabled || tape.Preview.Frame != 48 {
+		t.Fatalf("expected tape preview frame to override template, got %+v", tape.Preview)
+	}
+	if tape.Aesthetic.LabelStyle != LabelStyleNoisy || tape.Aesthetic.ShellColorway != ShellColorwayBlack {
+		t.Fatalf("unexpected aesthetic: %+v", tape.Aesthetic)
+	}
+	if cfg.Tapes[1].RequiresAlpha || cfg.Tapes[1].Mode != ModeFrame {
+		t.Fatalf("untemplated tape changed: %+v", cfg.Tapes[1])
+	}
+
+	bad := strings.Replace(templatedConfig, "template: broadcast", "template: missing", 1)
+	if err := os.WriteFile(cfgPath, []byte(bad), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath, tmp); err == nil || !strings.Contains(err.Error(), `unknown template "missing"`) {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+}
+
+func TestDuplicateTape(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(templatedConfig), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmp, "manifests"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "manifests", "lower_third.yaml"), []byte("fps: 60\n"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	cfg, err := Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	id := NextTapeID(cfg, "lower-third")
+	if id != "lower-third-copy" {
+		t.Fatalf("unexpected next id: %s", id)
+	}
+
+	manifest, err := DuplicateTape(cfgPath, tmp, Duplicate{SourceID: "lower-third", ID: id})
+	if err != nil {
+		t.Fatalf("DuplicateTape: %v", err)
+	}
+	if manifest != "./manifests/lower-third-copy.yaml" {
+		t.Fatalf("unexpected manifest: %s", manifest)
+	}
+	if buf, err := os.ReadFile(filepath.Join(tmp, "manifests", "lower-third-copy.yaml")); err != nil || string(buf) != "fps: 60\n" {
+		t.Fatalf("manifest not copied: %q err=%v", buf, err)
+	}
+
+	raw, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.Contains(string(raw), "# studio tapes") {
+		t.Fatalf("expected comments to survive:\n%s", raw)
+	}
+
+	reloaded, err := Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load after duplicate: %v", err)
+	}
+	if len(reloaded.Tapes) != 3 {
+		t.Fatalf("expected 3 tapes, got %d", len(reloaded.Tapes))
+	}
+	dup := reloaded.Tapes[2]
+	if dup.ID != id || dup.Template != "broadcast" || dup.Preview.Frame != 48 || !dup.RequiresAlpha {
+		t.Fatalf("unexpected duplicate: %+v", dup)
+	}
+	if NextTapeID(reloaded, "lower-third") != "lower-third-copy-2" {
+		t.Fatalf("expected numbered copy id")
+	}
+
+	if _, err := DuplicateTape(cfgPath, tmp, Duplicate{SourceID: "plain", ID: id}); err == nil {
+		t.Fatal("expected duplicate id error")
+	}
+}
+
+func TestAddTapeForManifest(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(templatedConfig), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	manifests := map[string]string{
+		"Agent Scene.yaml": "environment:\n  fps: 30\n  duration: 4\n",
+		"poster.yaml":      "environment:\n  fps: 24\n  duration: {frames: 1}\n",
+		"plain.yaml":       "environment:\n  fps: 24\n",
+	}
+	for name, body := range manifests {
+		if err := os.WriteFile(filepath.Join(tmp, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write manifest: %v", err)
+		}
+	}
+
+	cfg, err := Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := map[string]struct {
+		id    string
+		mode  Mode
+		frame int
+	}{
+		"Agent Scene.yaml": {"agent-scene", ModeVideo, 60},
+		"poster.yaml":      {"poster", ModeFrame, 0},
+		"plain.yaml":       {"plain-2", ModeVideo, 0},
+	}
+	for name, w := range want {
+		tape, err := TapeForManifest(cfg, filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatalf("TapeForManifest(%s): %v", name, err)
+		}
+		if tape.ID != w.id || tape.Mode != w.mode || tape.Preview.Frame != w.frame || tape.Manifest != "./"+name {
+			t.Fatalf("unexpected tape for %s: %+v", name, tape)
+		}
+		if err := AddTape(cfgPath, tmp, tape); err != nil {
+			t.Fatalf("AddTape(%s): %v", name, err)
+		}
+	}
+
+	cfg, err = Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load after add: %v", err)
+	}
+	if len(cfg.Tapes) != 5 {
+		t.Fatalf("expected 5 tapes, got %d", len(cfg.Tapes))
+	}
+	buf, _ := os.ReadFile(cfgPath)
+	if !strings.Contains(string(buf), "# studio tapes") {
+		t.Fatalf("comments lost:\n%s", buf)
+	}
+	if err := AddTape(cfgPath, tmp, Tape{ID: "poster", Manifest: "./poster.yaml", Mode: ModeFrame}); err == nil {
+		t.Fatal("expected duplicate id to fail")
+	}
+	if err := AddTape(cfgPath, tmp, Tape{ID: "broken", Manifest: "./poster.yaml", Mode: "tape"}); err == nil {
+		t.Fatal("expected a tape that does not validate to fail")
+	}
+	if after, _ := os.ReadFile(cfgPath); string(after) != string(buf) {
+		t.Fatalf("a rejected edit changed the config:\n%s", after)
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+import "errors"
+
+// Sentinels for config mistakes with a known fix. They are returned wrapped
+// with details and a hint (see internal/hint).
+var (
+	ErrConfigMissing   = errors.New("config not found")
+	ErrUnknownTemplate = errors.New("unknown template")
+	ErrNoTapes         = errors.New("config requires at least one tape")
+	ErrDuplicateTape   = errors.New("duplicate tape id")
+	ErrUnknownKeys     = errors.New("unknown config keys")
+)
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a config file syntax, picked by file extension.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// FormatOf returns the format for path's extension; anything other than
+// .json or .toml is YAML.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// decodeDocument parses buf into a YAML tree, so every format shares the
+// same decoding, template and unknown-key handling. JSON is valid YAML and
+// keeps its line numbers; TOML is converted and has none.
+func decodeDocument(buf []byte, format Format) (*yaml.Node, error) {
+	var doc yaml.Node
+	switch format {
+	case FormatJSON:
+		var v any
+		if err := json.Unmarshal(buf, &v); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		if err := yaml.Unmarshal(buf, &doc); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+	case FormatTOML:
+		var v map[string]any
+		if err := toml.Unmarshal(buf, &v); err != nil {
+			var de *toml.DecodeError
+			if errors.As(err, &de) {
+				line, col := de.Position()
+				return nil, fmt.Errorf("parse toml: line %d column %d: %w", line, col, err)
+			}
+			return nil, fmt.Errorf("parse toml: %w", err)
+		}
+		var root yaml.Node
+		if err := root.Encode(v); err != nil {
+			return nil, fmt.Errorf("parse toml: %w", err)
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	default:
+		if err := yaml.Unmarshal(buf, &doc); err != nil {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+	}
+	return &doc, nil
+}
+
+// encodeDocument writes a YAML tree back out in format, keeping key order
+// for YAML and JSON. TOML output is sorted by key.
+func encodeDocument(doc *yaml.Node, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, root(doc)); err != nil {
+			return nil, fmt.Errorf("marshal config: %w", err)
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, fmt.Errorf("marshal config: %w", err)
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	case FormatTOML:
+		var v map[string]any
+		if err := doc.Decode(&v); err != nil {
+			return nil, fmt.Errorf("marshal config: %w", err)
+		}
+		buf, err := toml.Marshal(withoutNulls(v))
+		if err != nil {
+			return nil, fmt.Errorf("marshal config: %w", err)
+		}
+		return buf, nil
+	default:
+		buf, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("marshal config: %w", err)
+		}
+		return buf, nil
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	if n == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch n.Kind {
+	case yaml.AliasNode:
+		return writeJSON(buf, n.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(n.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(out)
+	default:
+		return fmt.Errorf("unexpected yaml node kind %d", n.Kind)
+	}
+	return nil
+}
+
+// withoutNulls drops null values, which TOML cannot express.
+func withoutNulls(v map[string]any) map[string]any {
+	for k, item := range v {
+		switch item := item.(type) {
+		case nil:
+			delete(v, k)
+		case map[string]any:
+			v[k] = withoutNulls(item)
+		case []any:
+			for _, elem := range item {
+				if m, ok := elem.(map[string]any); ok {
+					withoutNulls(m)
+				}
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadJSONAndTOML(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"config.json": `{
+  "vcr_binary": "vcr-dev",
+  "templates": {"base": {"mode": "video", "primary_args": ["--fps", "60"]}},
+  "tapes": [
+    {"id": "alpha", "template": "base", "manifest": "./a.yaml", "preview": {"enabled": true, "frame": 12}}
+  ]
+}
+`,
+		"config.toml": `vcr_binary = "vcr-dev"
+
+[templates.base]
+mode = "video"
+primary_args = ["--fps", "60"]
+
+[[tapes]]
+id = "alpha"
+template = "base"
+manifest = "./a.yaml"
+preview = { enabled = true, frame = 12 }
+`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err := Load(path, filepath.Dir(path))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.VCRBinary != "vcr-dev" || len(cfg.Tapes) != 1 {
+				t.Fatalf("unexpected config: %+v", cfg)
+			}
+			tape := cfg.Tapes[0]
+			if tape.Mode != ModeVideo || strings.Join(tape.PrimaryArgs, " ") != "--fps 60" || tape.Preview.Frame != 12 {
+				t.Fatalf("template not applied: %+v", tape)
+			}
+			if len(cfg.Warnings) != 0 {
+				t.Fatalf("unexpected warnings: %q", cfg.Warnings)
+			}
+		})
+	}
+}
+
+func TestLoadJSONReportsPositions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	body := `{
+  "tapes": [
+    {"id": "alpha", "manifest": "./a.yaml", "mode": "video", "notez": "x"}
+  ]
+}
+`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := `config.json:3:62: unknown key "notez" in tapes[0] (did you mean "notes"?)`
+	if len(cfg.Warnings) != 1 || cfg.Warnings[0] != want {
+		t.Fatalf("unexpected warnings: %q", cfg.Warnings)
+	}
+}
+
+func TestWriteStarterConfigRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"config.yaml", "config.json", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, name)
+			if err := WriteStarterConfig(path, tmp, false); err != nil {
+				t.Fatalf("WriteStarterConfig: %v", err)
+			}
+			cfg, err := LoadStrict(path, tmp)
+			if err != nil {
+				t.Fatalf("LoadStrict: %v", err)
+			}
+			want := StarterConfig(tmp)
+			if len(cfg.Tapes) != len(want.Tapes) || cfg.Tapes[0].ID != want.Tapes[0].ID || cfg.Env["VCR_SEED"] != "0" {
+				t.Fatalf("round trip lost data: %+v", cfg)
+			}
+
+			if _, err := DuplicateTape(path, tmp, Duplicate{SourceID: want.Tapes[0].ID, ID: "copy"}); err != nil {
+				t.Fatalf("DuplicateTape: %v", err)
+			}
+			cfg, err = LoadStrict(path, tmp)
+			if err != nil {
+				t.Fatalf("LoadStrict after duplicate: %v", err)
+			}
+			if last := cfg.Tapes[len(cfg.Tapes)-1]; last.ID != "copy" {
+				t.Fatalf("duplicate not written: %+v", last)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultGoldenThreshold allows 0.1% of pixels to differ visibly.
+const DefaultGoldenThreshold = 0.001
+
+// Golden lists reference frames the verify action renders and compares.
+// Threshold is the largest share of pixels (0-1) that may differ visibly
+// before a frame fails; nil means the key is absent, so 0 can ask for an
+// exact match.
+type Golden struct {
+	Threshold *float64      `yaml:"threshold,omitempty"`
+	Frames    []GoldenFrame `yaml:"frames,omitempty"`
+}
+
+// GoldenFrame pairs a frame number with its reference PNG, relative to
+// project_root.
+type GoldenFrame struct {
+	Frame     int    `yaml:"frame"`
+	Reference string `yaml:"reference"`
+}
+
+// Enabled reports whether the tape has any golden frames.
+func (g Golden) Enabled() bool {
+	return len(g.Frames) > 0
+}
+
+// Limit is the threshold to apply: Threshold if set, else
+// DefaultGoldenThreshold.
+func (g Golden) Limit() float64 {
+	if g.Threshold == nil {
+		return DefaultGoldenThreshold
+	}
+	return *g.Threshold
+}
+
+func validateGolden(t Tape) error {
+	g := t.Golden
+	if g.Threshold != nil && (*g.Threshold < 0 || *g.Threshold > 1) {
+		return fmt.Errorf("tape %q: golden.threshold must be between 0 and 1: %v", t.ID, *g.Threshold)
+	}
+	seen := map[int]bool{}
+	for i, f := range g.Frames {
+		if f.Frame < 0 {
+			return fmt.Errorf("tape %q: golden.frames[%d]: frame must be >= 0", t.ID, i)
+		}
+		if strings.TrimSpace(f.Reference) == "" {
+			return fmt.Errorf("tape %q: golden.frames[%d]: reference is required", t.ID, i)
+		}
+		if seen[f.Frame] {
+			return fmt.Errorf("tape %q: golden frame %d is listed twice", t.ID, f.Frame)
+		}
+		seen[f.Frame] = true
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateGolden(t *testing.T) {
+	t.Parallel()
+
+	threshold, tooHigh := 0.01, 2.0
+	tape := Tape{ID: "alpha", Golden: Golden{Threshold: &threshold, Frames: []GoldenFrame{{Frame: 0, Reference: "golden/alpha_0.png"}}}}
+	if err := validateGolden(tape); err != nil {
+		t.Fatalf("valid golden config rejected: %v", err)
+	}
+
+	for want, g := range map[string]Golden{
+		"between 0 and 1":       {Threshold: &tooHigh},
+		"reference is required": {Frames: []GoldenFrame{{Frame: 3}}},
+		"frame must be >= 0":    {Frames: []GoldenFrame{{Frame: -1, Reference: "a.png"}}},
+		"listed twice":          {Frames: []GoldenFrame{{Frame: 1, Reference: "a.png"}, {Frame: 1, Reference: "b.png"}}},
+	} {
+		if err := validateGolden(Tape{ID: "alpha", Golden: g}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestGoldenThresholdZeroMeansExact(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	data := `tapes:
+  - id: exact
+    manifest: ./manifests/a.yaml
+    mode: frame
+    golden:
+      threshold: 0
+      frames: [{frame: 0, reference: golden/a.png}]
+  - id: loose
+    manifest: ./manifests/b.yaml
+    mode: frame
+    golden:
+      frames: [{frame: 0, reference: golden/b.png}]
+`
+	if err := os.WriteFile(cfgPath, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Tapes[0].Golden.Limit(); got != 0 {
+		t.Fatalf("expected an explicit 0 to stay 0, got %v", got)
+	}
+	if got := cfg.Tapes[1].Golden.Limit(); got != DefaultGoldenThreshold {
+		t.Fatalf("expected the default for an absent key, got %v", got)
+	}
+}
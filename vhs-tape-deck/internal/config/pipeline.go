@@ -0,0 +1,250 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Pipeline chains tape renders and commands. Steps run one at a time in
+// dependency order; later steps read earlier results through
+// {{steps.<id>.output}} style variables.
+type Pipeline struct {
+	ID    string         `yaml:"id"`
+	Name  string         `yaml:"name,omitempty"`
+	Steps []PipelineStep `yaml:"steps"`
+}
+
+// PipelineStep runs either a tape (with Args appended to the action's args)
+// or an arbitrary Command. Without any Needs in the pipeline, each step
+// depends on the one before it; once a step declares Needs the pipeline is a
+// DAG and steps without Needs have no dependencies.
+type PipelineStep struct {
+	ID      string            `yaml:"id"`
+	Tape    string            `yaml:"tape,omitempty"`
+	Action  string            `yaml:"action,omitempty"`
+	Command []string          `yaml:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	Needs   []string          `yaml:"needs,omitempty"`
+	// If gates the step on its dependencies; see the StepIf constants.
+	If              StepIf `yaml:"if,omitempty"`
+	ContinueOnError bool   `yaml:"continue_on_error,omitempty"`
+	Retries         int    `yaml:"retries,omitempty"`
+	// RetryBackoffSeconds is the wait before the first retry; it doubles
+	// for every retry after that, up to MaxRetryBackoff.
+	RetryBackoffSeconds int `yaml:"retry_backoff_seconds,omitempty"`
+}
+
+const (
+	// MaxStepRetries keeps a failing step from holding a pipeline for hours.
+	MaxStepRetries = 10
+	// MaxRetryBackoff caps the wait between two attempts.
+	MaxRetryBackoff = 10 * time.Minute
+)
+
+// RetryDelay is the wait before retry n, counting from 1.
+func (s PipelineStep) RetryDelay(n int) time.Duration {
+	delay := time.Duration(s.RetryBackoffSeconds) * time.Second
+	for i := 1; i < n && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRetryBackoff)
+}
+
+type StepIf string
+
+const (
+	// StepIfSuccess runs the step when every dependency succeeded or failed
+	// with continue_on_error. It is the default.
+	StepIfSuccess StepIf = "success"
+	// StepIfExitZero requires every dependency to have exited 0, even those
+	// allowed to fail.
+	StepIfExitZero StepIf = "exit_zero"
+	// StepIfOutputExists requires every dependency's output to exist on disk.
+	StepIfOutputExists StepIf = "output_exists"
+	// StepIfAlways runs the step even after the pipeline has failed, e.g. to
+	// clean up or send a notification. Only cancellation skips it.
+	StepIfAlways StepIf = "always"
+)
+
+var stepRefPattern = regexp.MustCompile(`\{\{\s*steps\.([A-Za-z0-9_\-]+)\.`)
+
+// Deps returns the ids each step waits for.
+func (p Pipeline) Deps() map[string][]string {
+	dag := false
+	for _, s := range p.Steps {
+		if len(s.Needs) > 0 {
+			dag = true
+		}
+	}
+	deps := make(map[string][]string, len(p.Steps))
+	for i, s := range p.Steps {
+		switch {
+		case dag:
+			deps[s.ID] = s.Needs
+		case i > 0:
+			deps[s.ID] = []string{p.Steps[i-1].ID}
+		default:
+			deps[s.ID] = nil
+		}
+	}
+	return deps
+}
+
+// Order returns the steps sorted so every step follows its dependencies,
+// keeping declaration order where the graph allows.
+func (p Pipeline) Order() ([]PipelineStep, error) {
+	deps := p.Deps()
+	done := make(map[string]bool, len(p.Steps))
+	out := make([]PipelineStep, 0, len(p.Steps))
+	for len(out) < len(p.Steps) {
+		progressed := false
+		for _, s := range p.Steps {
+			if done[s.ID] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[s.ID] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[s.ID] = true
+				out = append(out, s)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var stuck []string
+			for _, s := range p.Steps {
+				if !done[s.ID] {
+					stuck = append(stuck, s.ID)
+				}
+			}
+			return nil, fmt.Errorf("pipeline %q has a dependency cycle among: %s", p.ID, strings.Join(stuck, ", "))
+		}
+	}
+	return out, nil
+}
+
+// Ancestors returns every step id that id transitively depends on.
+func (p Pipeline) Ancestors(id string) map[string]bool {
+	deps := p.Deps()
+	seen := map[string]bool{}
+	var visit func(string)
+	visit = func(step string) {
+		for _, dep := range deps[step] {
+			if !seen[dep] {
+				seen[dep] = true
+				visit(dep)
+			}
+		}
+	}
+	visit(id)
+	return seen
+}
+
+func validatePipelines(cfg *Config) error {
+	tapes := make(map[string]bool, len(cfg.Tapes))
+	golden := make(map[string]bool, len(cfg.Tapes))
+	for _, t := range cfg.Tapes {
+		tapes[t.ID] = true
+		golden[t.ID] = t.Golden.Enabled()
+	}
+	seen := map[string]bool{}
+	for pi, p := range cfg.Pipelines {
+		path := fmt.Sprintf("pipelines.%d", pi)
+		if strings.TrimSpace(p.ID) == "" {
+			return at(path, errors.New("pipeline id is required"))
+		}
+		if seen[p.ID] {
+			return at(path+".id", fmt.Errorf("duplicate pipeline id %q", p.ID))
+		}
+		seen[p.ID] = true
+		if len(p.Steps) == 0 {
+			return at(path, fmt.Errorf("pipeline %q has no steps", p.ID))
+		}
+
+		steps := map[string]bool{}
+		for si, s := range p.Steps {
+			if strings.TrimSpace(s.ID) == "" {
+				return at(fmt.Sprintf("%s.steps.%d", path, si), fmt.Errorf("pipeline %q: step id is required", p.ID))
+			}
+			if steps[s.ID] {
+				return at(fmt.Sprintf("%s.steps.%d.id", path, si), fmt.Errorf("pipeline %q: duplicate step id %q", p.ID, s.ID))
+			}
+			steps[s.ID] = true
+		}
+		for si, s := range p.Steps {
+			where := fmt.Sprintf("pipeline %q step %q", p.ID, s.ID)
+			step := fmt.Sprintf("%s.steps.%d", path, si)
+			switch {
+			case s.Tape != "" && len(s.Command) > 0:
+				return at(step, fmt.Errorf("%s: set tape or command, not both", where))
+			case s.Tape == "" && len(s.Command) == 0:
+				return at(step, fmt.Errorf("%s: tape or command is required", where))
+			case s.Tape != "" && !tapes[s.Tape]:
+				return at(step+".tape", fmt.Errorf("%s: unknown tape %q", where, s.Tape))
+			case len(s.Command) > 0 && (s.Action != "" || len(s.Args) > 0):
+				return at(step, fmt.Errorf("%s: action and args only apply to tape steps", where))
+			}
+			switch s.Action {
+			case "", "primary", "preview":
+			case "verify":
+				if !golden[s.Tape] {
+					return at(step+".action", fmt.Errorf("%s: tape %q has no golden frames to verify", where, s.Tape))
+				}
+			default:
+				return at(step+".action", fmt.Errorf("%s: action must be primary, preview or verify: %q", where, s.Action))
+			}
+			switch s.If {
+			case "", StepIfSuccess, StepIfExitZero, StepIfOutputExists, StepIfAlways:
+			default:
+				return at(step+".if", fmt.Errorf("%s: if must be success, exit_zero, output_exists or always: %q", where, s.If))
+			}
+			if s.Retries < 0 || s.RetryBackoffSeconds < 0 {
+				return at(step, fmt.Errorf("%s: retries and retry_backoff_seconds must be >= 0", where))
+			}
+			if s.Retries > MaxStepRetries {
+				return at(step+".retries", fmt.Errorf("%s: retries must be at most %d: %d", where, MaxStepRetries, s.Retries))
+			}
+			if maxBackoff := int(MaxRetryBackoff / time.Second); s.RetryBackoffSeconds > maxBackoff {
+				return at(step+".retry_backoff_seconds", fmt.Errorf("%s: retry_backoff_seconds must be at most %d: %d", where, maxBackoff, s.RetryBackoffSeconds))
+			}
+			for _, need := range s.Needs {
+				if !steps[need] {
+					return at(step+".needs", fmt.Errorf("%s: needs unknown step %q", where, need))
+				}
+			}
+		}
+		if _, err := p.Order(); err != nil {
+			return at(path, err)
+		}
+
+		for si, s := range p.Steps {
+			ancestors := p.Ancestors(s.ID)
+			fields := append(append(append([]string{}, s.Command...), s.Args...), mapValues(s.Env)...)
+			for _, field := range fields {
+				for _, m := range stepRefPattern.FindAllStringSubmatch(field, -1) {
+					if !ancestors[m[1]] {
+						return at(fmt.Sprintf("%s.steps.%d", path, si), fmt.Errorf("pipeline %q step %q: references step %q, which it does not depend on", p.ID, s.ID, m[1]))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func mapValues(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipelineOrderAndDeps(t *testing.T) {
+	t.Parallel()
+
+	chain := Pipeline{ID: "chain", Steps: []PipelineStep{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+	if deps := chain.Deps(); len(deps["c"]) != 1 || deps["c"][0] != "b" || deps["a"] != nil {
+		t.Fatalf("expected an ordered chain, got %v", deps)
+	}
+
+	dag := Pipeline{ID: "dag", Steps: []PipelineStep{
+		{ID: "upload", Needs: []string{"export", "poster"}},
+		{ID: "render"},
+		{ID: "export", Needs: []string{"render"}},
+		{ID: "poster"},
+	}}
+	order, err := dag.Order()
+	if err != nil {
+		t.Fatalf("Order: %v", err)
+	}
+	var ids []string
+	for _, s := range order {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, ","); got != "render,export,poster,upload" {
+		t.Fatalf("unexpected order %s", got)
+	}
+	if anc := dag.Ancestors("upload"); !anc["render"] || anc["upload"] {
+		t.Fatalf("unexpected ancestors %v", anc)
+	}
+
+	cycle := Pipeline{ID: "loop", Steps: []PipelineStep{{ID: "a", Needs: []string{"b"}}, {ID: "b", Needs: []string{"a"}}}}
+	if _, err := cycle.Order(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestValidatePipelines(t *testing.T) {
+	t.Parallel()
+
+	base := func(steps ...PipelineStep) *Config {
+		return &Config{
+			Tapes:     []Tape{{ID: "alpha"}},
+			Pipelines: []Pipeline{{ID: "promo", Steps: steps}},
+		}
+	}
+	ok := base(
+		PipelineStep{ID: "render", Tape: "alpha"},
+		PipelineStep{ID: "upload", Command: []string{"rsync", "{{steps.render.output}}", "host:"}},
+	)
+	if err := validatePipelines(ok); err != nil {
+		t.Fatalf("valid pipeline rejected: %v", err)
+	}
+
+	for want, cfg := range map[string]*Config{
+		"unknown tape":                          base(PipelineStep{ID: "a", Tape: "nope"}),
+		"not both":                              base(PipelineStep{ID: "a", Tape: "alpha", Command: []string{"true"}}),
+		"needs unknown step":                    base(PipelineStep{ID: "a", Tape: "alpha", Needs: []string{"ghost"}}),
+		"if must be":                            base(PipelineStep{ID: "a", Tape: "alpha", If: "sometimes"}),
+		"must be >= 0":                          base(PipelineStep{ID: "a", Tape: "alpha", Retries: -1}),
+		"retries must be at most":               base(PipelineStep{ID: "a", Tape: "alpha", Retries: 50}),
+		"retry_backoff_seconds must be at most": base(PipelineStep{ID: "a", Tape: "alpha", RetryBackoffSeconds: 3600}),
+		"does not depend on": base(
+			PipelineStep{ID: "a", Tape: "alpha", Needs: []string{"b"}},
+			PipelineStep{ID: "b", Command: []string{"echo", "{{steps.a.output}}"}},
+		),
+	} {
+		if err := validatePipelines(cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestRetryDelayDoublesUpToCap(t *testing.T) {
+	t.Parallel()
+
+	step := PipelineStep{RetryBackoffSeconds: 30}
+	for n, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		5:  8 * time.Minute,
+		6:  MaxRetryBackoff,
+		64: MaxRetryBackoff,
+	} {
+		if got := step.RetryDelay(n); got != want {
+			t.Fatalf("retry %d: expected %s, got %s", n, want, got)
+		}
+	}
+	if got := (PipelineStep{}).RetryDelay(3); got != 0 {
+		t.Fatalf("expected no wait without a backoff, got %s", got)
+	}
+}
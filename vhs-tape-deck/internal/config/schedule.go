@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/cron"
+)
+
+// Schedule runs a tape or a pipeline at the times matched by Cron while
+// `tape-deck serve` is running.
+type Schedule struct {
+	ID       string `yaml:"id"`
+	Cron     string `yaml:"cron"`
+	Tape     string `yaml:"tape,omitempty"`
+	Pipeline string `yaml:"pipeline,omitempty"`
+	// Action is primary, preview or verify for tape schedules.
+	Action string `yaml:"action,omitempty"`
+	DryRun bool   `yaml:"dry_run,omitempty"`
+}
+
+// Notify is told when a scheduled run fails after the previous run of the
+// same schedule succeeded. Command receives the details in TAPE_DECK_*
+// environment variables; Webhook receives them as a JSON POST.
+type Notify struct {
+	Command []string `yaml:"command,omitempty"`
+	Webhook string   `yaml:"webhook,omitempty"`
+}
+
+// Enabled reports whether any notification target is configured.
+func (n Notify) Enabled() bool {
+	return len(n.Command) > 0 || n.Webhook != ""
+}
+
+func validateSchedules(cfg *Config) error {
+	tapes := make(map[string]bool, len(cfg.Tapes))
+	golden := make(map[string]bool, len(cfg.Tapes))
+	for _, t := range cfg.Tapes {
+		tapes[t.ID] = true
+		golden[t.ID] = t.Golden.Enabled()
+	}
+	pipelines := make(map[string]bool, len(cfg.Pipelines))
+	for _, p := range cfg.Pipelines {
+		pipelines[p.ID] = true
+	}
+
+	seen := map[string]bool{}
+	for i, s := range cfg.Schedules {
+		path := fmt.Sprintf("schedules.%d", i)
+		if strings.TrimSpace(s.ID) == "" {
+			return at(path, errors.New("schedule id is required"))
+		}
+		if seen[s.ID] {
+			return at(path+".id", fmt.Errorf("duplicate schedule id %q", s.ID))
+		}
+		seen[s.ID] = true
+		if _, err := cron.Parse(s.Cron); err != nil {
+			return at(path+".cron", fmt.Errorf("schedule %q: %w", s.ID, err))
+		}
+		switch {
+		case s.Tape != "" && s.Pipeline != "":
+			return at(path, fmt.Errorf("schedule %q: set tape or pipeline, not both", s.ID))
+		case s.Tape == "" && s.Pipeline == "":
+			return at(path, fmt.Errorf("schedule %q: tape or pipeline is required", s.ID))
+		case s.Tape != "" && !tapes[s.Tape]:
+			return at(path+".tape", fmt.Errorf("schedule %q: unknown tape %q", s.ID, s.Tape))
+		case s.Pipeline != "" && !pipelines[s.Pipeline]:
+			return at(path+".pipeline", fmt.Errorf("schedule %q: unknown pipeline %q", s.ID, s.Pipeline))
+		case s.Pipeline != "" && s.Action != "":
+			return at(path+".action", fmt.Errorf("schedule %q: action only applies to tape schedules", s.ID))
+		}
+		switch s.Action {
+		case "", "primary", "preview":
+		case "verify":
+			if !golden[s.Tape] {
+				return at(path+".action", fmt.Errorf("schedule %q: tape %q has no golden frames to verify", s.ID, s.Tape))
+			}
+		default:
+			return at(path+".action", fmt.Errorf("schedule %q: action must be primary, preview or verify: %q", s.ID, s.Action))
+		}
+	}
+	if cfg.Notify.Webhook != "" && !strings.HasPrefix(cfg.Notify.Webhook, "http://") && !strings.HasPrefix(cfg.Notify.Webhook, "https://") {
+		return at("notify.webhook", fmt.Errorf("notify.webhook must be an http(s) URL: %q", cfg.Notify.Webhook))
+	}
+	return nil
+}
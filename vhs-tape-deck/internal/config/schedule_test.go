@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSchedules(t *testing.T) {
+	t.Parallel()
+
+	base := func(s Schedule) *Config {
+		return &Config{
+			Tapes:     []Tape{{ID: "alpha"}},
+			Pipelines: []Pipeline{{ID: "promo", Steps: []PipelineStep{{ID: "render", Tape: "alpha"}}}},
+			Schedules: []Schedule{s},
+		}
+	}
+	if err := validateSchedules(base(Schedule{ID: "nightly", Cron: "0 3 * * *", Pipeline: "promo"})); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+
+	for want, cfg := range map[string]*Config{
+		"expected 5 fields":    base(Schedule{ID: "a", Cron: "nightly", Tape: "alpha"}),
+		"unknown pipeline":     base(Schedule{ID: "a", Cron: "@daily", Pipeline: "nope"}),
+		"tape or pipeline is":  base(Schedule{ID: "a", Cron: "@daily"}),
+		"only applies to tape": base(Schedule{ID: "a", Cron: "@daily", Pipeline: "promo", Action: "preview"}),
+	} {
+		if err := validateSchedules(cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+
+	cfg := base(Schedule{ID: "a", Cron: "@daily", Tape: "alpha"})
+	cfg.Notify.Webhook = "hooks.example.com"
+	if err := validateSchedules(cfg); err == nil || !strings.Contains(err.Error(), "http(s) URL") {
+		t.Fatalf("expected webhook URL error, got %v", err)
+	}
+}
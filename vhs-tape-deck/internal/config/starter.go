@@ -0,0 +1,319 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPreset is the starter config init writes without --preset.
+const DefaultPreset = "demo"
+
+// Preset is a starter config for one kind of workflow.
+type Preset struct {
+	Name        string
+	Description string
+	apply       func(cfg *Config)
+}
+
+// Presets lists the starter configs `tape-deck init --preset` offers.
+var Presets = []Preset{
+	{Name: "demo", Description: "five example tapes covering video, stills and alpha", apply: demoPreset},
+	{Name: "minimal", Description: "a single tape to build on", apply: minimalPreset},
+	{Name: "broadcast", Description: "lower third, corner bug and title card with alpha checks", apply: broadcastPreset},
+	{Name: "social", Description: "vertical story, square post and looping teaser", apply: socialPreset},
+	{Name: "regression", Description: "seeded stills with golden frames, verified nightly by serve", apply: regressionPreset},
+}
+
+// PresetNames returns the preset names in menu order.
+func PresetNames() []string {
+	names := make([]string, len(Presets))
+	for i, p := range Presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// StarterConfig is the default preset's config for a project in launchCWD.
+func StarterConfig(launchCWD string) Config {
+	cfg, _ := PresetConfig(DefaultPreset, launchCWD)
+	return cfg
+}
+
+// PresetConfig builds the named preset's config for a project in
+// launchCWD.
+func PresetConfig(name, launchCWD string) (Config, error) {
+	if strings.TrimSpace(launchCWD) == "" {
+		launchCWD, _ = os.Getwd()
+	}
+
+	projectRoot := launchCWD
+	if runtime.GOOS == "windows" {
+		projectRoot = filepath.Clean(projectRoot)
+	}
+
+	cfg := Config{
+		VCRBinary:   "vcr",
+		OutputFlag:  "--output",
+		ProjectRoot: projectRoot,
+		Env: map[string]string{
+			"VCR_SEED": "0",
+		},
+	}
+	for _, p := range Presets {
+		if p.Name == name {
+			p.apply(&cfg)
+			return cfg, nil
+		}
+	}
+	return Config{}, fmt.Errorf("unknown preset %q (valid: %s)", name, strings.Join(PresetNames(), ", "))
+}
+
+// WriteStarterConfig writes the default preset to configPath.
+func WriteStarterConfig(configPath, launchCWD string, overwrite bool) error {
+	return WriteConfig(configPath, launchCWD, StarterConfig(launchCWD), overwrite)
+}
+
+// WriteConfig applies defaults to cfg and writes it to configPath in the
+// format its extension names, creating the runs dir as well.
+func WriteConfig(configPath, launchCWD string, cfg Config, overwrite bool) error {
+	if strings.TrimSpace(configPath) == "" {
+		var err error
+		configPath, err = DefaultConfigPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(configPath); err == nil && !overwrite {
+		return fmt.Errorf("config already exists at %s", configPath)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("check config path: %w", err)
+	}
+
+	if err := ApplyDefaults(&cfg, configPath, launchCWD); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.MkdirAll(cfg.RunsDir, 0o755); err != nil {
+		return fmt.Errorf("create runs dir: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(cfg); err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	buf, err := encodeDocument(&doc, FormatOf(configPath))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(configPath, buf, 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+func demoPreset(cfg *Config) {
+	cfg.Tapes = []Tape{
+		{
+			ID:       "alpha-lower-third",
+			Name:     "Alpha Lower Third",
+			Manifest: "./manifests/alpha_lower_third.yaml",
+			Mode:     ModeVideo,
+			PrimaryArgs: []string{
+				"--duration", "5",
+				"--fps", "60",
+			},
+			Preview:   Preview{Enabled: true, Frame: 48, Args: []string{"--fps", "60"}},
+			Aesthetic: Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayBlack},
+			Notes:     "Broadcast-safe lower third with alpha",
+		},
+		{
+			ID:       "neon-title",
+			Name:     "Neon Title Card",
+			Manifest: "./manifests/neon_title.yaml",
+			Mode:     ModeVideo,
+			PrimaryArgs: []string{
+				"--duration", "6",
+				"--fps", "60",
+			},
+			Preview:   Preview{Enabled: true, Frame: 90, Args: []string{"--quality", "draft"}},
+			Aesthetic: Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayGray},
+			Notes:     "CRT glow and scanline feel",
+		},
+		{
+			ID:       "frame-poster",
+			Name:     "Poster Frame",
+			Manifest: "./manifests/poster_frame.yaml",
+			Mode:     ModeFrame,
+			PrimaryArgs: []string{
+				"--frame", "160",
+			},
+			Preview:   Preview{Enabled: true, Frame: 120, Args: []string{"--quality", "draft"}},
+			Aesthetic: Aesthetic{LabelStyle: LabelStyleHandwritten, ShellColorway: ShellColorwayClear},
+			Notes:     "High detail still export",
+		},
+		{
+			ID:       "pack-y2k",
+			Name:     "Y2K Pack Probe",
+			Manifest: "./manifests/pack_y2k.yaml",
+			Mode:     ModeVideo,
+			PrimaryArgs: []string{
+				"--duration", "4",
+				"--fps", "60",
+				"--seed", "0",
+			},
+			Preview:   Preview{Enabled: true, Frame: 36, Args: []string{"--seed", "0"}},
+			Aesthetic: Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayBlack},
+			Notes:     "Pack-driven scene validation",
+		},
+		{
+			ID:       "debug-safe-mode",
+			Name:     "Debug Safe Mode",
+			Manifest: "./manifests/debug_safe.yaml",
+			Mode:     ModeFrame,
+			PrimaryArgs: []string{
+				"--frame", "0",
+				"--seed", "0",
+			},
+			Preview:   Preview{Enabled: true, Frame: 0, Args: []string{"--seed", "0"}},
+			Aesthetic: Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayGray},
+			Notes:     "Deterministic sanity checks",
+		},
+	}
+}
+
+func minimalPreset(cfg *Config) {
+	cfg.Tapes = []Tape{
+		{
+			ID:          "first-tape",
+			Name:        "First Tape",
+			Manifest:    "./manifests/first_tape.yaml",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "3", "--fps", "30"},
+			Preview:     Preview{Enabled: true, Frame: 45},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayBlack},
+		},
+	}
+}
+
+func broadcastPreset(cfg *Config) {
+	// Tapes keep their own mode and args; the template adds the shared look
+	// and the alpha check.
+	cfg.Templates = map[string]Tape{
+		"broadcast": {
+			Mode:          ModeVideo,
+			Aesthetic:     Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayBlack},
+			RequiresAlpha: true,
+		},
+	}
+	cfg.Tapes = []Tape{
+		{
+			ID:          "lower-third",
+			Name:        "Lower Third",
+			Manifest:    "./manifests/lower_third.yaml",
+			Template:    "broadcast",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "6", "--fps", "60"},
+			Preview:     Preview{Enabled: true, Frame: 90, Args: []string{"--quality", "draft"}},
+			Notes:       "Name and title strap, keyed over program",
+		},
+		{
+			ID:          "corner-bug",
+			Name:        "Corner Bug",
+			Manifest:    "./manifests/corner_bug.yaml",
+			Template:    "broadcast",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "10", "--fps", "60"},
+			Preview:     Preview{Enabled: true, Frame: 30, Args: []string{"--quality", "draft"}},
+			Notes:       "Looping station bug",
+		},
+		{
+			ID:          "title-card",
+			Name:        "Title Card",
+			Manifest:    "./manifests/title_card.yaml",
+			Template:    "broadcast",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "5", "--fps", "60"},
+			Preview:     Preview{Enabled: true, Frame: 60, Args: []string{"--quality", "draft"}},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleHandwritten, ShellColorway: ShellColorwayGray},
+			Notes:       "Full-frame opener",
+		},
+	}
+}
+
+func socialPreset(cfg *Config) {
+	cfg.Tapes = []Tape{
+		{
+			ID:          "story-vertical",
+			Name:        "Vertical Story",
+			Manifest:    "./manifests/story_vertical.yaml",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "15", "--fps", "30"},
+			Preview:     Preview{Enabled: true, Frame: 45, Args: []string{"--quality", "draft"}},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayClear},
+			Notes:       "1080x1920 story",
+		},
+		{
+			ID:          "post-square",
+			Name:        "Square Post",
+			Manifest:    "./manifests/post_square.yaml",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "10", "--fps", "30"},
+			Preview:     Preview{Enabled: true, Frame: 30, Args: []string{"--quality", "draft"}},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayGray},
+			Notes:       "1080x1080 feed post",
+		},
+		{
+			ID:          "teaser-loop",
+			Name:        "Teaser Loop",
+			Manifest:    "./manifests/teaser_loop.yaml",
+			Mode:        ModeVideo,
+			PrimaryArgs: []string{"--duration", "4", "--fps", "30"},
+			Preview:     Preview{Enabled: true, Frame: 0, Args: []string{"--quality", "draft"}},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleNoisy, ShellColorway: ShellColorwayBlack},
+			Notes:       "Seamless loop; share it with G",
+		},
+	}
+}
+
+func regressionPreset(cfg *Config) {
+	still := func(id, name, manifest string, frames ...int) Tape {
+		t := Tape{
+			ID:          id,
+			Name:        name,
+			Manifest:    manifest,
+			Mode:        ModeFrame,
+			PrimaryArgs: []string{"--frame", strconv.Itoa(frames[0]), "--seed", "0"},
+			Preview:     Preview{Enabled: true, Frame: frames[0], Args: []string{"--seed", "0"}},
+			Aesthetic:   Aesthetic{LabelStyle: LabelStyleClean, ShellColorway: ShellColorwayGray},
+		}
+		for _, f := range frames {
+			t.Golden.Frames = append(t.Golden.Frames, GoldenFrame{Frame: f, Reference: fmt.Sprintf("./golden/%s_%d.png", id, f)})
+		}
+		return t
+	}
+	cfg.Tapes = []Tape{
+		still("text-layout", "Text Layout", "./manifests/text_layout.yaml", 0, 30),
+		still("shader-sweep", "Shader Sweep", "./manifests/shader_sweep.yaml", 0, 60, 120),
+	}
+	cfg.Pipelines = []Pipeline{{
+		ID:   "regression",
+		Name: "Verify golden frames",
+		Steps: []PipelineStep{
+			{ID: "text-layout", Tape: "text-layout", Action: "verify"},
+			// Verify every tape even when an earlier one regressed.
+			{ID: "shader-sweep", Tape: "shader-sweep", Action: "verify", If: StepIfAlways},
+		},
+	}}
+	cfg.Schedules = []Schedule{{ID: "nightly", Cron: "0 3 * * *", Pipeline: "regression"}}
+}
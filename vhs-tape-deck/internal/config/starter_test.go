@@ -0,0 +1,63 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPresetsWriteValidConfigs(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range Presets {
+		t.Run(p.Name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, "config.yaml")
+			cfg, err := PresetConfig(p.Name, tmp)
+			if err != nil {
+				t.Fatalf("PresetConfig: %v", err)
+			}
+			if err := WriteConfig(path, tmp, cfg, false); err != nil {
+				t.Fatalf("WriteConfig: %v", err)
+			}
+			loaded, err := LoadStrict(path, tmp)
+			if err != nil {
+				t.Fatalf("LoadStrict: %v", err)
+			}
+			if len(loaded.Tapes) != len(cfg.Tapes) {
+				t.Fatalf("expected %d tapes, got %d", len(cfg.Tapes), len(loaded.Tapes))
+			}
+		})
+	}
+}
+
+func TestBroadcastPresetInheritsAlphaCheck(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yaml")
+	cfg, err := PresetConfig("broadcast", tmp)
+	if err != nil {
+		t.Fatalf("PresetConfig: %v", err)
+	}
+	if err := WriteConfig(path, tmp, cfg, false); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+	loaded, err := Load(path, tmp)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, tape := range loaded.Tapes {
+		if !tape.RequiresAlpha {
+			t.Fatalf("tape %q did not inherit requires_alpha from its template", tape.ID)
+		}
+	}
+}
+
+func TestPresetConfigRejectsUnknownPreset(t *testing.T) {
+	t.Parallel()
+
+	if _, err := PresetConfig("cinema", t.TempDir()); err == nil {
+		t.Fatal("expected unknown preset to fail")
+	}
+}
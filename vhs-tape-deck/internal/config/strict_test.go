@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStrictConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadWarnsOnUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	path := writeStrictConfig(t, `vcr_binary: vcr
+tapes:
+  - id: alpha
+    manifest: ./a.yaml
+    mode: video
+    primay_args: ["--quality", "high"]
+`)
+	cfg, err := Load(path, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Warnings) != 1 {
+		t.Fatalf("expected one warning, got %q", cfg.Warnings)
+	}
+	want := `config.yaml:6:5: unknown key "primay_args" in tapes[0] (did you mean "primary_args"?)`
+	if cfg.Warnings[0] != want {
+		t.Fatalf("unexpected warning:\n got %s\nwant %s", cfg.Warnings[0], want)
+	}
+
+	if _, err := LoadStrict(path, filepath.Dir(path)); !errors.Is(err, ErrUnknownKeys) {
+		t.Fatalf("expected ErrUnknownKeys from LoadStrict, got %v", err)
+	}
+}
+
+func TestLoadStrictAcceptsKnownKeys(t *testing.T) {
+	t.Parallel()
+
+	path := writeStrictConfig(t, `tapes:
+  - id: alpha
+    manifest: ./a.yaml
+    mode: video
+    preview:
+      enabled: true
+      frame: 3
+`)
+	cfg, err := LoadStrict(path, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("LoadStrict: %v", err)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %q", cfg.Warnings)
+	}
+}
+
+func TestLoadReportsValidationPosition(t *testing.T) {
+	t.Parallel()
+
+	path := writeStrictConfig(t, `tapes:
+  - id: alpha
+    manifest: ./a.yaml
+    mode: video
+  - id: beta
+    manifest: ./b.yaml
+    mode: vidoe
+`)
+	_, err := Load(path, filepath.Dir(path))
+	if err == nil {
+		t.Fatal("expected invalid mode to fail")
+	}
+	if !strings.HasPrefix(err.Error(), "config.yaml:7:11: ") {
+		t.Fatalf("expected position prefix, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	stubFPS     = 30
+	stubSeconds = 5
+)
+
+// stubSizes overrides the 1920x1080 stub resolution for preset tapes made
+// for other aspect ratios.
+var stubSizes = map[string][2]int{
+	"story-vertical": {1080, 1920},
+	"post-square":    {1080, 1080},
+}
+
+// WriteManifestStubs writes a minimal VCR manifest for every tape whose
+// manifest does not exist yet, so a fresh config renders right away. It
+// returns the paths it wrote.
+func WriteManifestStubs(cfg *Config) ([]string, error) {
+	var written []string
+	for _, t := range cfg.Tapes {
+		if strings.TrimSpace(t.Manifest) == "" {
+			continue
+		}
+		path, err := ResolveManifestPath(cfg.ProjectRoot, t.Manifest)
+		if err != nil {
+			return written, fmt.Errorf("tape %q: resolve manifest: %w", t.ID, err)
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return written, fmt.Errorf("tape %q: check manifest: %w", t.ID, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return written, fmt.Errorf("create manifest dir: %w", err)
+		}
+		if err := os.WriteFile(path, ManifestStub(t), 0o644); err != nil {
+			return written, fmt.Errorf("tape %q: write manifest: %w", t.ID, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// ManifestStub is a gradient and a title card sized to the tape's args:
+// --fps and --duration when set, and long enough to reach every frame the
+// tape renders. Tapes that require alpha get no background.
+func ManifestStub(t Tape) []byte {
+	fps := stubFPS
+	if v, err := strconv.Atoi(flagValue(t.PrimaryArgs, "--fps")); err == nil && v > 0 {
+		fps = v
+	}
+	frames := fps * stubSeconds
+	if v, err := strconv.ParseFloat(flagValue(t.PrimaryArgs, "--duration"), 64); err == nil && v > 0 {
+		frames = int(math.Ceil(v * float64(fps)))
+	}
+	last := t.Preview.Frame
+	if v, err := strconv.Atoi(flagValue(t.PrimaryArgs, "--frame")); err == nil {
+		last = max(last, v)
+	}
+	for _, f := range t.Golden.Frames {
+		last = max(last, f.Frame)
+	}
+	frames = max(frames, last+1)
+	size, ok := stubSizes[t.ID]
+	if !ok {
+		size = [2]int{1920, 1080}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Stub written by `tape-deck init --with-manifests` for tape %q.\n", t.ID)
+	b.WriteString("# Replace the layers with your scene.\n")
+	b.WriteString("version: 1\n\n")
+	b.WriteString("environment:\n")
+	fmt.Fprintf(&b, "  resolution: { width: %d, height: %d }\n", size[0], size[1])
+	fmt.Fprintf(&b, "  fps: %d\n", fps)
+	fmt.Fprintf(&b, "  duration:\n    frames: %d\n\n", frames)
+	b.WriteString("layers:\n")
+	if !t.RequiresAlpha {
+		b.WriteString(`  - id: bg
+    z_index: 0
+    procedural:
+      kind: gradient
+      start_color: { r: 0.05, g: 0.05, b: 0.12, a: 1.0 }
+      end_color: { r: 0.25, g: 0.05, b: 0.3, a: 1.0 }
+      direction: vertical
+
+`)
+	}
+	name := t.Name
+	if name == "" {
+		name = t.ID
+	}
+	fmt.Fprintf(&b, `  - id: title
+    z_index: 10
+    text:
+      content: %s
+      font_family: "GeistPixel-Line"
+      font_size: %d
+      color: { r: 1.0, g: 1.0, b: 1.0, a: 1.0 }
+`, strconv.Quote(name), min(size[0], size[1])/12)
+	return []byte(b.String())
+}
+
+// flagValue returns the value of the last --name or --name=value in args.
+func flagValue(args []string, name string) string {
+	value := ""
+	for i, arg := range args {
+		if arg == name && i+1 < len(args) {
+			value = args[i+1]
+		} else if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			value = v
+		}
+	}
+	return value
+}
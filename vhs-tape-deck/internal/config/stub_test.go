@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteManifestStubsSkipsExisting(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	existing := filepath.Join(tmp, "manifests", "kept.yaml")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("mine"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg := &Config{
+		ProjectRoot: tmp,
+		Tapes: []Tape{
+			{ID: "kept", Manifest: "./manifests/kept.yaml"},
+			{ID: "new", Manifest: "./manifests/new.yaml"},
+		},
+	}
+
+	written, err := WriteManifestStubs(cfg)
+	if err != nil {
+		t.Fatalf("WriteManifestStubs: %v", err)
+	}
+	if len(written) != 1 || written[0] != filepath.Join(tmp, "manifests", "new.yaml") {
+		t.Fatalf("unexpected stubs: %v", written)
+	}
+	if buf, _ := os.ReadFile(existing); string(buf) != "mine" {
+		t.Fatalf("existing manifest overwritten: %q", buf)
+	}
+}
+
+func TestManifestStubCoversTapeFrames(t *testing.T) {
+	t.Parallel()
+
+	var stub struct {
+		Environment struct {
+			Resolution struct{ Width, Height int }
+			FPS        int `yaml:"fps"`
+			Duration   struct{ Frames int }
+		}
+		Layers []struct {
+			ID string `yaml:"id"`
+		}
+	}
+
+	video := Tape{ID: "story-vertical", Name: "Story", PrimaryArgs: []string{"--duration", "2.5", "--fps=24"}}
+	if err := yaml.Unmarshal(ManifestStub(video), &stub); err != nil {
+		t.Fatalf("parse stub: %v", err)
+	}
+	env := stub.Environment
+	if env.FPS != 24 || env.Duration.Frames != 60 || env.Resolution.Width != 1080 || env.Resolution.Height != 1920 {
+		t.Fatalf("unexpected environment: %+v", env)
+	}
+	if len(stub.Layers) != 2 {
+		t.Fatalf("expected background and title layers, got %+v", stub.Layers)
+	}
+
+	still := Tape{ID: "sweep", Mode: ModeFrame, RequiresAlpha: true, PrimaryArgs: []string{"--frame", "10"}, Golden: Golden{Frames: []GoldenFrame{{Frame: 400, Reference: "x.png"}}}}
+	stub.Layers = nil
+	if err := yaml.Unmarshal(ManifestStub(still), &stub); err != nil {
+		t.Fatalf("parse stub: %v", err)
+	}
+	if stub.Environment.Duration.Frames != 401 {
+		t.Fatalf("stub too short for golden frame 400: %d frames", stub.Environment.Duration.Frames)
+	}
+	if len(stub.Layers) != 1 || stub.Layers[0].ID != "title" {
+		t.Fatalf("alpha stub should have no background: %+v", stub.Layers)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Upload runs Command once per output of a successful render. {{output}},
+// {{name}} (the output's file name), {{tape}} and {{run_id}} are replaced
+// in Command and URL. The remote URL kept in the run record is URL when
+// set, or else the last line the command prints that parses as a URL.
+type Upload struct {
+	Command []string `yaml:"command,omitempty"`
+	URL     string   `yaml:"url,omitempty"`
+}
+
+// Enabled reports whether an upload command is configured.
+func (u Upload) Enabled() bool {
+	return len(u.Command) > 0
+}
+
+var uploadVarPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// UploadVars are the placeholders an upload command and URL may use.
+var UploadVars = []string{"output", "name", "tape", "run_id"}
+
+// ExpandUpload replaces {{var}} placeholders in s with values from vars.
+func ExpandUpload(s string, vars map[string]string) string {
+	return uploadVarPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := vars[uploadVarPattern.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+func validateUpload(t Tape) error {
+	u := t.Upload
+	if !u.Enabled() {
+		if u.URL != "" {
+			return fmt.Errorf("tape %q: upload.command is required when upload.url is set", t.ID)
+		}
+		return nil
+	}
+	if strings.TrimSpace(u.Command[0]) == "" {
+		return fmt.Errorf("tape %q: upload.command must start with a program", t.ID)
+	}
+	for _, s := range append([]string{u.URL}, u.Command...) {
+		for _, m := range uploadVarPattern.FindAllStringSubmatch(s, -1) {
+			if !isUploadVar(m[1]) {
+				return fmt.Errorf("tape %q: unknown upload variable {{%s}} (valid: %s)", t.ID, m[1], strings.Join(UploadVars, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func isUploadVar(name string) bool {
+	for _, v := range UploadVars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadValidationAndExpansion(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	load := func(upload Upload) error {
+		cfg := &Config{Tapes: []Tape{{ID: "alpha", Manifest: "./a.yaml", Mode: ModeVideo, Upload: upload}}}
+		return ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp)
+	}
+	if err := load(Upload{Command: []string{"rclone", "copyto", "{{output}}", "remote:{{ tape }}/{{name}}"}, URL: "https://cdn.example/{{run_id}}"}); err != nil {
+		t.Fatalf("valid upload rejected: %v", err)
+	}
+	if err := load(Upload{URL: "https://cdn.example/x"}); err == nil || !strings.Contains(err.Error(), "upload.command") {
+		t.Fatalf("expected missing command error, got %v", err)
+	}
+	if err := load(Upload{Command: []string{"scp", "{{file}}", "host:"}}); err == nil || !strings.Contains(err.Error(), "{{file}}") {
+		t.Fatalf("expected unknown variable error, got %v", err)
+	}
+
+	got := ExpandUpload("remote:{{ tape }}/{{name}}/{{other}}", map[string]string{"tape": "alpha", "name": "a.mov"})
+	if got != "remote:alpha/a.mov/{{other}}" {
+		t.Fatalf("unexpected expansion: %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC) // a Saturday
+	for expr, want := range map[string]string{
+		"* * * * *":            "2026-03-14 10:31",
+		"@daily":               "2026-03-15 00:00",
+		"0 3 * * *":            "2026-03-15 03:00",
+		"*/15 * * * *":         "2026-03-14 10:45",
+		"0 9-17/4 * * mon-fri": "2026-03-16 09:00",
+		"30 2 1 * *":           "2026-04-01 02:30",
+		"0 0 1,15 * 3":         "2026-03-15 00:00",
+		"0 0 * feb 7":          "2027-02-07 00:00",
+	} {
+		s, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr, err)
+		}
+		if got := s.Next(from).Format("2006-01-02 15:04"); got != want {
+			t.Fatalf("%q: expected %s got %s", expr, want, got)
+		}
+	}
+
+	never, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := never.Next(from); !got.IsZero() {
+		t.Fatalf("expected no match, got %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for expr, want := range map[string]string{
+		"* * * *":        "expected 5 fields",
+		"60 * * * *":     "outside 0-59",
+		"* * * * funday": "bad value",
+		"5-1 * * * *":    "backwards",
+		"*/0 * * * *":    "bad step",
+	} {
+		if _, err := Parse(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected %q error, got %v", expr, want, err)
+		}
+	}
+}
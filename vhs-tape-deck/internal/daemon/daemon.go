@@ -0,0 +1,391 @@
+// Package daemon runs the config's schedules for `tape-deck serve`.
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/cron"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/metrics"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/pipeline"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/trace"
+)
+
+const notifyTimeout = 30 * time.Second
+
+type Options struct {
+	Config *config.Config
+	Runner *runner.Runner
+	Logger *slog.Logger
+	// Out receives a line whenever a scheduled run starts or finishes.
+	Out io.Writer
+	// DryRun turns every scheduled run into a dry run.
+	DryRun bool
+}
+
+// Result is the outcome of one scheduled run. The latest result of every
+// schedule is kept in <runs_dir>/schedules.json so regressions are noticed
+// across restarts.
+type Result struct {
+	Schedule string           `json:"schedule"`
+	Target   string           `json:"target"`
+	RunID    string           `json:"run_id,omitempty"`
+	Status   runner.RunStatus `json:"status"`
+	Previous runner.RunStatus `json:"previous,omitempty"`
+	Message  string           `json:"message,omitempty"`
+	At       time.Time        `json:"at"`
+}
+
+// Regression reports a failure right after a success.
+func (r Result) Regression() bool {
+	return r.Status == runner.StatusFailed && r.Previous == runner.StatusSuccess
+}
+
+type Daemon struct {
+	cfg       *config.Config
+	run       *runner.Runner
+	log       *slog.Logger
+	out       io.Writer
+	dryRun    bool
+	now       func() time.Time
+	client    *http.Client
+	statePath string
+	state     map[string]Result
+	metrics   *daemonMetrics
+}
+
+// daemonMetrics are served on /metrics by `tape-deck serve --metrics-addr`.
+// Tape runs count whether a schedule runs the tape itself or as a pipeline
+// step.
+type daemonMetrics struct {
+	registry  *metrics.Registry
+	started   *metrics.Counter
+	succeeded *metrics.Counter
+	failed    *metrics.Counter
+	duration  *metrics.Histogram
+	queue     *metrics.Gauge
+}
+
+func newDaemonMetrics() *daemonMetrics {
+	r := metrics.NewRegistry()
+	return &daemonMetrics{
+		registry:  r,
+		started:   r.Counter("tape_deck_runs_started_total", "Tape runs started by schedules.", "tape"),
+		succeeded: r.Counter("tape_deck_runs_succeeded_total", "Scheduled tape runs that succeeded.", "tape"),
+		failed:    r.Counter("tape_deck_runs_failed_total", "Scheduled tape runs that failed or were aborted.", "tape"),
+		duration:  r.Histogram("tape_deck_render_duration_seconds", "Duration of finished scheduled tape runs.", "tape", metrics.DurationBuckets),
+		queue:     r.Gauge("tape_deck_queue_depth", "Schedules that are due and waiting for the current run to finish."),
+	}
+}
+
+// finished counts a tape run's outcome. Canceled runs only count as
+// started.
+func (m *daemonMetrics) finished(tape string, status runner.RunStatus, durationMS int64) {
+	switch status {
+	case runner.StatusSuccess:
+		m.succeeded.Inc(tape)
+	case runner.StatusFailed, runner.StatusAborted:
+		m.failed.Inc(tape)
+	default:
+		return
+	}
+	m.duration.Observe(tape, float64(durationMS)/1000)
+}
+
+func StatePath(runsDir string) string {
+	return filepath.Join(runsDir, "schedules.json")
+}
+
+func New(opts Options) *Daemon {
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+	d := &Daemon{
+		cfg:       opts.Config,
+		run:       opts.Runner,
+		log:       logging.Component(opts.Logger, "serve"),
+		out:       out,
+		dryRun:    opts.DryRun,
+		now:       time.Now,
+		client:    &http.Client{Timeout: notifyTimeout},
+		statePath: StatePath(opts.Config.RunsDir),
+		state:     map[string]Result{},
+		metrics:   newDaemonMetrics(),
+	}
+	if buf, err := os.ReadFile(d.statePath); err == nil {
+		if err := json.Unmarshal(buf, &d.state); err != nil {
+			d.log.Warn("schedule state unreadable, starting fresh", "path", d.statePath, "err", err)
+			d.state = map[string]Result{}
+		}
+	}
+	return d
+}
+
+// Metrics serves the daemon's metrics in the Prometheus text format.
+func (d *Daemon) Metrics() http.Handler {
+	return d.metrics.registry
+}
+
+// Run fires schedules until ctx is canceled. Runs happen one at a time;
+// a schedule that comes due while another run is going fires once it
+// finishes, and fires missed in the meantime are not made up.
+func (d *Daemon) Run(ctx context.Context) error {
+	specs := make(map[string]*cron.Schedule, len(d.cfg.Schedules))
+	next := make(map[string]time.Time, len(d.cfg.Schedules))
+	now := d.now()
+	for _, s := range d.cfg.Schedules {
+		spec, err := cron.Parse(s.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule %q: %w", s.ID, err)
+		}
+		specs[s.ID] = spec
+		next[s.ID] = spec.Next(now)
+		if next[s.ID].IsZero() {
+			fmt.Fprintf(d.out, "[serve] %s never fires (%s)\n", s.ID, s.Cron)
+			continue
+		}
+		fmt.Fprintf(d.out, "[serve] %s next at %s\n", s.ID, next[s.ID].Format("2006-01-02 15:04"))
+	}
+
+	for {
+		var due time.Time
+		for _, s := range d.cfg.Schedules {
+			if t := next[s.ID]; !t.IsZero() && (due.IsZero() || t.Before(due)) {
+				due = t
+			}
+		}
+		if due.IsZero() {
+			<-ctx.Done()
+			return nil
+		}
+
+		timer := time.NewTimer(due.Sub(d.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		fired := d.now()
+		var ready []config.Schedule
+		for _, s := range d.cfg.Schedules {
+			if t := next[s.ID]; !t.IsZero() && !t.After(fired) {
+				ready = append(ready, s)
+			}
+		}
+		for i, s := range ready {
+			d.metrics.queue.Set(float64(len(ready) - i - 1))
+			d.RunSchedule(ctx, s)
+			if ctx.Err() != nil {
+				return nil
+			}
+			next[s.ID] = specs[s.ID].Next(d.now())
+			if !next[s.ID].IsZero() {
+				fmt.Fprintf(d.out, "[serve] %s next at %s\n", s.ID, next[s.ID].Format("2006-01-02 15:04"))
+			}
+		}
+	}
+}
+
+// RunSchedule runs s once, records the result and sends a notification if
+// it is a regression.
+func (d *Daemon) RunSchedule(ctx context.Context, s config.Schedule) Result {
+	result := Result{Schedule: s.ID, Target: "tape:" + s.Tape, Previous: d.state[s.ID].Status, At: d.now()}
+	if s.Pipeline != "" {
+		result.Target = "pipeline:" + s.Pipeline
+	}
+	dryRun := s.DryRun || d.dryRun
+	trigger := "schedule:" + s.ID
+	ctx, span := trace.Start(ctx, "schedule", trace.String("schedule", s.ID), trace.String("target", result.Target))
+	defer span.End()
+	fmt.Fprintf(d.out, "[%s] starting %s\n", s.ID, result.Target)
+	d.log.Info("schedule fired", "schedule", s.ID, "target", result.Target, "dry_run", dryRun)
+
+	if s.Pipeline != "" {
+		d.runPipeline(ctx, s, dryRun, trigger, &result)
+	} else {
+		d.runTape(ctx, s, dryRun, trigger, &result)
+	}
+
+	line := fmt.Sprintf("[%s] %s %s", s.ID, result.Target, result.Status)
+	if result.RunID != "" {
+		line += " (" + result.RunID + ")"
+	}
+	if result.Status != runner.StatusSuccess && result.Message != "" {
+		line += ": " + result.Message
+	}
+	fmt.Fprintln(d.out, line)
+	d.log.Info("schedule finished", "schedule", s.ID, "run_id", result.RunID, "status", result.Status, "previous", result.Previous)
+	span.Set(trace.String("run_id", result.RunID), trace.String("status", string(result.Status)))
+	if result.Status == runner.StatusFailed {
+		span.Fail(result.Message)
+	}
+
+	// A canceled run says nothing about the render, so it does not replace
+	// the status regressions are measured against.
+	if result.Status != runner.StatusCanceled {
+		d.state[s.ID] = result
+		if err := d.saveState(); err != nil {
+			d.log.Error("save schedule state", "err", err)
+		}
+	}
+	if result.Regression() {
+		fmt.Fprintf(d.out, "[%s] regression: %s was passing\n", s.ID, result.Target)
+		d.notify(ctx, result)
+	}
+	return result
+}
+
+func (d *Daemon) runTape(ctx context.Context, s config.Schedule, dryRun bool, trigger string, result *Result) {
+	result.Status = runner.StatusFailed
+	var tape config.Tape
+	found := false
+	for _, t := range d.cfg.Tapes {
+		if t.ID == s.Tape {
+			tape, found = t, true
+		}
+	}
+	if !found {
+		result.Message = fmt.Sprintf("unknown tape %q", s.Tape)
+		return
+	}
+	if tape.Disabled {
+		result.Message = fmt.Sprintf("tape %q is disabled", s.Tape)
+		return
+	}
+	action := runner.ActionPrimary
+	if s.Action != "" {
+		action = runner.Action(s.Action)
+	}
+	events, err := d.run.Start(ctx, runner.Request{Config: d.cfg, Tape: tape, Action: action, DryRun: dryRun, Trigger: trigger})
+	if err != nil {
+		result.Message = err.Error()
+		return
+	}
+	d.metrics.started.Inc(tape.ID)
+	var durationMS int64
+	for event := range events {
+		if event.Type != runner.EventFinished {
+			continue
+		}
+		if event.Record != nil {
+			result.RunID = event.Record.RunID
+			result.Status = event.Record.Status
+			durationMS = event.Record.DurationMS
+		}
+		result.Message = event.Message
+	}
+	d.metrics.finished(tape.ID, result.Status, durationMS)
+}
+
+func (d *Daemon) runPipeline(ctx context.Context, s config.Schedule, dryRun bool, trigger string, result *Result) {
+	result.Status = runner.StatusFailed
+	p, ok := pipeline.Find(d.cfg, s.Pipeline)
+	if !ok {
+		result.Message = fmt.Sprintf("unknown pipeline %q", s.Pipeline)
+		return
+	}
+	events, err := pipeline.Start(ctx, d.run, pipeline.Request{Config: d.cfg, Pipeline: p, DryRun: dryRun, Trigger: trigger})
+	if err != nil {
+		result.Message = err.Error()
+		return
+	}
+	for event := range events {
+		switch event.Type {
+		case pipeline.EventStepStarted:
+			for _, step := range p.Steps {
+				if step.ID == event.Step && step.Tape != "" {
+					d.metrics.started.Inc(step.Tape)
+				}
+			}
+		case pipeline.EventStepFinished:
+			r := event.Result
+			if r.Tape != "" {
+				d.metrics.finished(r.Tape, r.Status, r.DurationMS)
+			}
+			if r.Status == runner.StatusFailed && !r.Ignored && result.Message == "" {
+				result.Message = fmt.Sprintf("step %s: %s", r.ID, r.Error)
+			}
+		case pipeline.EventFinished:
+			result.RunID = event.Record.RunID
+			result.Status = event.Record.Status
+		}
+	}
+}
+
+func (d *Daemon) saveState() error {
+	buf, err := json.MarshalIndent(d.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal schedule state: %w", err)
+	}
+	if err := atomicfile.Write(d.statePath, append(buf, '\n')); err != nil {
+		return fmt.Errorf("write schedule state: %w", err)
+	}
+	return nil
+}
+
+func (d *Daemon) notify(ctx context.Context, result Result) {
+	n := d.cfg.Notify
+	if !n.Enabled() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	if len(n.Command) > 0 {
+		cmd := exec.CommandContext(ctx, n.Command[0], n.Command[1:]...)
+		cmd.Dir = d.cfg.ProjectRoot
+		cmd.Env = append(os.Environ(),
+			"TAPE_DECK_SCHEDULE="+result.Schedule,
+			"TAPE_DECK_TARGET="+result.Target,
+			"TAPE_DECK_STATUS="+string(result.Status),
+			"TAPE_DECK_PREVIOUS="+string(result.Previous),
+			"TAPE_DECK_RUN_ID="+result.RunID,
+			"TAPE_DECK_MESSAGE="+result.Message,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			d.log.Error("notify command failed", "schedule", result.Schedule, "err", err, "output", string(bytes.TrimSpace(out)))
+		}
+	}
+
+	if n.Webhook != "" {
+		body, err := json.Marshal(struct {
+			Event string `json:"event"`
+			Result
+		}{Event: "regression", Result: result})
+		if err != nil {
+			d.log.Error("marshal webhook payload", "err", err)
+			return
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Webhook, bytes.NewReader(body))
+		if err != nil {
+			d.log.Error("notify webhook", "schedule", result.Schedule, "err", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := d.client.Do(req)
+		if err != nil {
+			d.log.Error("notify webhook", "schedule", result.Schedule, "err", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			d.log.Error("notify webhook", "schedule", result.Schedule, "status", resp.Status)
+		}
+	}
+}
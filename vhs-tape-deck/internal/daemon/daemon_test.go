@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestRunScheduleNotifiesOnRegression(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	flag := filepath.Join(tmp, "broken")
+	notified := filepath.Join(tmp, "notified")
+	var hooks []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		hooks = append(hooks, payload)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		VCRBinary:   "vcr",
+		ProjectRoot: tmp,
+		RunsDir:     filepath.Join(tmp, "runs"),
+		Tapes:       []config.Tape{{ID: "alpha", Manifest: "./alpha.yaml", Mode: config.ModeVideo}},
+		Pipelines: []config.Pipeline{{ID: "check", Steps: []config.PipelineStep{
+			{ID: "test", Command: []string{"sh", "-c", "test ! -f " + flag}},
+		}}},
+		Schedules: []config.Schedule{{ID: "nightly", Cron: "@daily", Pipeline: "check"}},
+		Notify: config.Notify{
+			Command: []string{"sh", "-c", `echo "$TAPE_DECK_SCHEDULE $TAPE_DECK_STATUS $TAPE_DECK_PREVIOUS" > ` + notified},
+			Webhook: server.URL,
+		},
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+
+	var out strings.Builder
+	d := New(Options{Config: cfg, Runner: runner.New(nil), Out: &out})
+	first := d.RunSchedule(context.Background(), cfg.Schedules[0])
+	if first.Status != runner.StatusSuccess || first.Regression() {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+
+	if err := os.WriteFile(flag, nil, 0o644); err != nil {
+		t.Fatalf("write flag: %v", err)
+	}
+	// A restarted daemon still remembers the last success.
+	d = New(Options{Config: cfg, Runner: runner.New(nil), Out: &out})
+	second := d.RunSchedule(context.Background(), cfg.Schedules[0])
+	if !second.Regression() || !strings.Contains(second.Message, "step test") {
+		t.Fatalf("expected a regression, got %+v", second)
+	}
+	buf, err := os.ReadFile(notified)
+	if err != nil || strings.TrimSpace(string(buf)) != "nightly failed success" {
+		t.Fatalf("notify command output %q err=%v", buf, err)
+	}
+	if len(hooks) != 1 || hooks[0]["event"] != "regression" || hooks[0]["schedule"] != "nightly" {
+		t.Fatalf("unexpected webhook calls: %v", hooks)
+	}
+
+	third := d.RunSchedule(context.Background(), cfg.Schedules[0])
+	if third.Regression() || len(hooks) != 1 {
+		t.Fatalf("a repeated failure should not notify again: %+v", third)
+	}
+	if !strings.Contains(out.String(), "[nightly] regression: pipeline:check was passing") {
+		t.Fatalf("missing regression line:\n%s", out.String())
+	}
+}
+
+func TestMetricsCountTapeRuns(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	cfg := &config.Config{
+		VCRBinary:   "vcr",
+		ProjectRoot: tmp,
+		RunsDir:     filepath.Join(tmp, "runs"),
+		Tapes:       []config.Tape{{ID: "alpha", Manifest: "./alpha.yaml", Mode: config.ModeVideo}},
+		Pipelines: []config.Pipeline{{ID: "check", Steps: []config.PipelineStep{
+			{ID: "render", Tape: "alpha"},
+			{ID: "test", Command: []string{"sh", "-c", "exit 1"}},
+		}}},
+		Schedules: []config.Schedule{
+			{ID: "hourly", Cron: "@hourly", Tape: "alpha", DryRun: true},
+			{ID: "nightly", Cron: "@daily", Pipeline: "check", DryRun: true},
+		},
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+
+	d := New(Options{Config: cfg, Runner: runner.New(nil)})
+	for _, s := range cfg.Schedules {
+		d.RunSchedule(context.Background(), s)
+	}
+
+	rec := httptest.NewRecorder()
+	d.Metrics().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`tape_deck_runs_started_total{tape="alpha"} 2`,
+		`tape_deck_runs_succeeded_total{tape="alpha"} 2`,
+		`tape_deck_render_duration_seconds_count{tape="alpha"} 2`,
+		"tape_deck_queue_depth 0",
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "tape_deck_runs_failed_total{") {
+		t.Fatalf("command steps should not count as tape runs:\n%s", body)
+	}
+}
+
+func TestRunScheduleRejectsDisabledTapes(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfg := &config.Config{
+		VCRBinary:   "vcr",
+		ProjectRoot: tmp,
+		RunsDir:     filepath.Join(tmp, "runs"),
+		Tapes:       []config.Tape{{ID: "retired", Manifest: "./retired.yaml", Mode: config.ModeVideo, Disabled: true}},
+		Pipelines: []config.Pipeline{{ID: "release", Steps: []config.PipelineStep{
+			{ID: "render", Tape: "retired"},
+		}}},
+		Schedules: []config.Schedule{
+			{ID: "hourly", Cron: "@hourly", Tape: "retired", DryRun: true},
+			{ID: "nightly", Cron: "@daily", Pipeline: "release", DryRun: true},
+		},
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+
+	d := New(Options{Config: cfg, Runner: runner.New(nil)})
+	for _, s := range cfg.Schedules {
+		result := d.RunSchedule(context.Background(), s)
+		if result.Status != runner.StatusFailed || !strings.Contains(result.Message, `tape "retired" is disabled`) {
+			t.Fatalf("%s: expected the disabled tape to be rejected, got %+v", s.ID, result)
+		}
+	}
+	if records, _ := filepath.Glob(filepath.Join(cfg.RunsDir, "records", "*.json")); len(records) != 0 {
+		t.Fatalf("disabled tape was rendered: %v", records)
+	}
+}
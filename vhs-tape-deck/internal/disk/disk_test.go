@@ -0,0 +1,61 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUsageSumsFiles(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "run", "frames"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "run", "out.mov"), make([]byte, 1000), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "run", "frames", "0.png"), make([]byte, 24), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, err := Usage(tmp)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if got != 1024 {
+		t.Fatalf("expected 1024 bytes, got %d", got)
+	}
+	if got, err := Usage(filepath.Join(tmp, "missing")); err != nil || got != 0 {
+		t.Fatalf("missing dir: got %d, %v", got, err)
+	}
+}
+
+func TestFreeUsesExistingParent(t *testing.T) {
+	t.Parallel()
+
+	free, err := Free(filepath.Join(t.TempDir(), "not", "yet", "made"))
+	if err != nil {
+		t.Fatalf("Free: %v", err)
+	}
+	if free == 0 {
+		t.Fatal("expected some free space in the temp dir")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int64]string{
+		512:             "512 B",
+		1536:            "1.5 KB",
+		3 * 1024 * 1024: "3.0 MB",
+		5 << 30:         "5.0 GB",
+	}
+	for n, want := range cases {
+		if got := Format(n); got != want {
+			t.Fatalf("Format(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
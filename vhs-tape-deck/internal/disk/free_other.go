@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || dragonfly || windows)
+
+package disk
+
+import "errors"
+
+func freeBytes(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
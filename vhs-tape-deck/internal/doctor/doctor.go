@@ -0,0 +1,261 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/disk"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r Report) OK() bool {
+	return r.Count(StatusFail) == 0
+}
+
+// Summary is a one-line digest suitable for a status panel.
+func (r Report) Summary() string {
+	summary := fmt.Sprintf("%d/%d ok", r.Count(StatusPass), len(r.Checks))
+	if n := r.Count(StatusWarn); n > 0 {
+		summary += fmt.Sprintf(", %d warn", n)
+	}
+	if n := r.Count(StatusFail); n > 0 {
+		summary += fmt.Sprintf(", %d fail", n)
+	}
+	return summary
+}
+
+type Options struct {
+	// LLMEndpoints maps a backend name to a URL probed with GET; unreachable
+	// backends are reported as warnings since the deck itself does not need them.
+	LLMEndpoints map[string]string
+	Timeout      time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		LLMEndpoints: map[string]string{
+			"LM Studio": "http://127.0.0.1:1234/v1/models",
+			"Ollama":    "http://127.0.0.1:11434/api/tags",
+		},
+		Timeout: 3 * time.Second,
+	}
+}
+
+func Run(ctx context.Context, cfg *config.Config, opts Options) Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+
+	var report Report
+	add := func(c Check) { report.Checks = append(report.Checks, c) }
+
+	vcrPath, vcrCheck := checkBinary("vcr binary", cfg.VCRBinary, "set vcr_binary in config to an absolute path, or add vcr to PATH")
+	add(vcrCheck)
+	if vcrPath != "" {
+		add(checkVCRVersion(ctx, vcrPath, cfg.ProjectRoot, cfg.VCRMinVersion, opts.Timeout))
+		add(checkVCRBackend(ctx, vcrPath, cfg.ProjectRoot, opts.Timeout))
+	}
+
+	_, ffmpeg := checkBinary("ffmpeg", "ffmpeg", "install ffmpeg (e.g. `brew install ffmpeg`); vcr needs it to encode video")
+	add(ffmpeg)
+	_, ffprobe := checkBinary("ffprobe", "ffprobe", "install ffprobe (ships with ffmpeg) for output metadata checks")
+	if ffprobe.Status == StatusFail {
+		ffprobe.Status = StatusWarn
+	}
+	add(ffprobe)
+
+	add(checkWritableDir("runs dir", cfg.RunsDir))
+	if floor := cfg.Disk.MinFreeBytes(); floor > 0 {
+		add(checkDiskSpace(cfg.RunsDir, floor))
+	}
+	if logDir, err := logging.DefaultDir(); err == nil {
+		add(checkWritableDir("log dir", logDir))
+	}
+	seenOutputs := map[string]struct{}{}
+	for _, tape := range cfg.Tapes {
+		if _, ok := seenOutputs[tape.OutputDir]; !ok {
+			seenOutputs[tape.OutputDir] = struct{}{}
+			add(checkWritableDir("output dir ("+tape.ID+")", tape.OutputDir))
+		}
+		add(checkManifest(cfg, tape))
+	}
+
+	for _, name := range sortedKeys(opts.LLMEndpoints) {
+		add(checkEndpoint(ctx, name, opts.LLMEndpoints[name], opts.Timeout))
+	}
+
+	return report
+}
+
+func checkBinary(name, binary, fix string) (string, Check) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("%s not found", binary), Fix: fix}
+	}
+	return path, Check{Name: name, Status: StatusPass, Detail: path}
+}
+
+func checkVCRVersion(ctx context.Context, vcrPath, dir, minVersion string, timeout time.Duration) Check {
+	out, err := runCommand(ctx, timeout, dir, vcrPath, "--version")
+	have, ok := version.Find(firstLine(out))
+	if err != nil || !ok {
+		return Check{Name: "vcr version", Status: StatusWarn, Detail: errDetail(err, out), Fix: "rebuild vcr; `vcr --version` should print its version"}
+	}
+	if minVersion != "" {
+		if want, err := version.Parse(minVersion); err == nil && have.Compare(want) < 0 {
+			return Check{Name: "vcr version", Status: StatusFail, Detail: fmt.Sprintf("%s (config requires >= %s)", have, minVersion), Fix: "rebuild or update vcr, or lower vcr_min_version in the config"}
+		}
+	}
+	return Check{Name: "vcr version", Status: StatusPass, Detail: have.String()}
+}
+
+func checkVCRBackend(ctx context.Context, vcrPath, dir string, timeout time.Duration) Check {
+	out, err := runCommand(ctx, timeout*5, dir, vcrPath, "doctor")
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+		if !strings.HasPrefix(line, "Backend:") {
+			continue
+		}
+		detail := strings.TrimSpace(strings.TrimPrefix(line, "Backend:"))
+		if strings.HasPrefix(detail, "OK") {
+			return Check{Name: "gpu backend", Status: StatusPass, Detail: strings.TrimSpace(strings.TrimPrefix(detail, "OK"))}
+		}
+		return Check{Name: "gpu backend", Status: StatusFail, Detail: detail, Fix: "run `vcr doctor` for details; check GPU drivers or use the software backend"}
+	}
+	return Check{Name: "gpu backend", Status: StatusWarn, Detail: errDetail(err, out), Fix: "run `vcr doctor` manually to inspect the render backend"}
+}
+
+func checkWritableDir(name, dir string) Check {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: err.Error(), Fix: "fix permissions or point the config at a writable directory"}
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: err.Error(), Fix: "fix permissions or point the config at a writable directory"}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return Check{Name: name, Status: StatusPass, Detail: dir}
+}
+
+func checkDiskSpace(dir string, floor uint64) Check {
+	free, err := disk.Free(dir)
+	if err != nil {
+		return Check{Name: "disk space", Status: StatusWarn, Detail: err.Error()}
+	}
+	detail := fmt.Sprintf("%s free in %s", disk.Format(int64(free)), dir)
+	if free < floor {
+		return Check{Name: "disk space", Status: StatusWarn, Detail: detail, Fix: "free up space, delete old runs, or lower disk.min_free_mb"}
+	}
+	return Check{Name: "disk space", Status: StatusPass, Detail: detail}
+}
+
+func checkManifest(cfg *config.Config, tape config.Tape) Check {
+	name := "manifest (" + tape.ID + ")"
+	path, err := config.ResolveManifestPath(cfg.ProjectRoot, tape.Manifest)
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: err.Error(), Fix: "fix the manifest path in config"}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("%s: %v", path, errors.Unwrap(err)), Fix: "create the manifest or fix the path (relative paths resolve from project_root)"}
+	}
+	return Check{Name: name, Status: StatusPass, Detail: path}
+}
+
+func checkEndpoint(ctx context.Context, name, url string, timeout time.Duration) Check {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return Check{Name: name, Status: StatusWarn, Detail: err.Error()}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Check{Name: name, Status: StatusWarn, Detail: "unreachable at " + url, Fix: "start " + name + " if you use the agent tools"}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 400 {
+		return Check{Name: name, Status: StatusWarn, Detail: fmt.Sprintf("%s returned %s", url, resp.Status)}
+	}
+	return Check{Name: name, Status: StatusPass, Detail: url}
+}
+
+func runCommand(ctx context.Context, timeout time.Duration, dir, binary string, args ...string) (string, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(cmdCtx, binary, args...)
+	if dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			cmd.Dir = dir
+		}
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+func errDetail(err error, out string) string {
+	if err != nil {
+		return err.Error()
+	}
+	if line := firstLine(out); line != "" {
+		return line
+	}
+	return "no output"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
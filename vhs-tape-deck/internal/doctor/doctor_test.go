@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func TestRunReportsMissingDependencies(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "manifests"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "manifests", "ok.yaml"), []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	cfg := &config.Config{
+		VCRBinary:   filepath.Join(tmp, "missing-vcr"),
+		ProjectRoot: tmp,
+		Tapes: []config.Tape{
+			{ID: "ok", Manifest: "./manifests/ok.yaml", Mode: config.ModeVideo},
+			{ID: "gone", Manifest: "./manifests/gone.yaml", Mode: config.ModeVideo},
+		},
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), tmp); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer llm.Close()
+
+	report := Run(context.Background(), cfg, Options{
+		LLMEndpoints: map[string]string{"Local LLM": llm.URL, "Down": "http://127.0.0.1:1/"},
+		Timeout:      time.Second,
+	})
+
+	want := map[string]Status{
+		"vcr binary":      StatusFail,
+		"runs dir":        StatusPass,
+		"manifest (ok)":   StatusPass,
+		"manifest (gone)": StatusFail,
+		"Local LLM":       StatusPass,
+		"Down":            StatusWarn,
+	}
+	for _, c := range report.Checks {
+		if expected, ok := want[c.Name]; ok {
+			if c.Status != expected {
+				t.Fatalf("%s: expected %s got %s (%s)", c.Name, expected, c.Status, c.Detail)
+			}
+			delete(want, c.Name)
+		}
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing checks: %v", want)
+	}
+	for _, c := range report.Checks {
+		if c.Name == "vcr version" || c.Name == "gpu backend" {
+			t.Fatalf("did not expect %s check without a vcr binary", c.Name)
+		}
+	}
+	if report.OK() {
+		t.Fatalf("expected failing report")
+	}
+	if !strings.Contains(report.Summary(), "fail") {
+		t.Fatalf("unexpected summary: %s", report.Summary())
+	}
+}
+
+func TestVCRVersionBelowMinimumFails(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	vcr := filepath.Join(tmp, "vcr")
+	if err := os.WriteFile(vcr, []byte("#!/bin/sh\necho 'vcr 0.1.2'\n"), 0o755); err != nil {
+		t.Fatalf("write fake vcr: %v", err)
+	}
+
+	for minVersion, want := range map[string]Status{"0.2.0": StatusFail, "0.1.0": StatusPass} {
+		c := checkVCRVersion(context.Background(), vcr, tmp, minVersion, time.Second)
+		if c.Status != want {
+			t.Fatalf("min %s: expected %s got %s (%s)", minVersion, want, c.Status, c.Detail)
+		}
+	}
+}
+
+func TestDiskSpaceWarnsBelowFloor(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	if c := checkDiskSpace(tmp, 1); c.Status != StatusPass {
+		t.Fatalf("expected pass with a 1 byte floor, got %s (%s)", c.Status, c.Detail)
+	}
+	if c := checkDiskSpace(tmp, 1<<62); c.Status != StatusWarn || c.Fix == "" {
+		t.Fatalf("expected warn with fix below the floor, got %+v", c)
+	}
+}
@@ -0,0 +1,139 @@
+// Package events fans a stream of typed events out to any number of
+// subscribers. Every subscriber has its own bounded queue, so a slow reader
+// holds up neither the publisher nor the other subscribers, and a
+// subscriber that joins late first receives the most recent events again.
+package events
+
+import "sync"
+
+type Bus[T any] struct {
+	mu        sync.Mutex
+	replay    int
+	limit     int
+	droppable func(T) bool
+	recent    []T
+	subs      map[*subscriber[T]]struct{}
+	closed    bool
+}
+
+type subscriber[T any] struct {
+	// queue and closed are guarded by the bus mutex.
+	queue  []T
+	closed bool
+	wake   chan struct{}
+	cancel chan struct{}
+	once   sync.Once
+	out    chan T
+}
+
+// New returns a bus that replays up to replay recent events to each new
+// subscriber. Once a subscriber has limit events waiting, further events
+// for which droppable reports true are dropped for it; other events are
+// always queued. A limit <= 0 or a nil droppable drops nothing.
+func New[T any](replay, limit int, droppable func(T) bool) *Bus[T] {
+	return &Bus[T]{replay: max(0, replay), limit: limit, droppable: droppable, subs: map[*subscriber[T]]struct{}{}}
+}
+
+// Publish queues event for every subscriber without blocking. Events
+// published after Close are dropped.
+func (b *Bus[T]) Publish(event T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	if b.replay > 0 {
+		b.recent = append(b.recent, event)
+		// Trim in batches so publishing stays amortized O(1).
+		if len(b.recent) >= 2*b.replay {
+			b.recent = append([]T(nil), b.recent[len(b.recent)-b.replay:]...)
+		}
+	}
+	drop := b.limit > 0 && b.droppable != nil && b.droppable(event)
+	for s := range b.subs {
+		if drop && len(s.queue) >= b.limit {
+			continue
+		}
+		s.queue = append(s.queue, event)
+		s.signal()
+	}
+}
+
+// Subscribe returns a channel that receives the replayed events and then
+// everything published until Close. The channel closes once the bus is
+// closed and the queue is drained, or after cancel is called.
+func (b *Bus[T]) Subscribe() (<-chan T, func()) {
+	s := &subscriber[T]{
+		wake:   make(chan struct{}, 1),
+		cancel: make(chan struct{}),
+		out:    make(chan T),
+	}
+	b.mu.Lock()
+	start := max(0, len(b.recent)-b.replay)
+	s.queue = append([]T(nil), b.recent[start:]...)
+	s.closed = b.closed
+	if !b.closed {
+		b.subs[s] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	go b.deliver(s)
+	cancel := func() {
+		s.once.Do(func() { close(s.cancel) })
+		b.mu.Lock()
+		delete(b.subs, s)
+		b.mu.Unlock()
+	}
+	return s.out, cancel
+}
+
+// Close ends the stream. Subscribers still receive what was already queued.
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		s.closed = true
+		s.signal()
+	}
+	b.subs = nil
+}
+
+func (b *Bus[T]) deliver(s *subscriber[T]) {
+	defer close(s.out)
+	for {
+		b.mu.Lock()
+		queue, closed := s.queue, s.closed
+		s.queue = nil
+		b.mu.Unlock()
+
+		if len(queue) == 0 {
+			if closed {
+				return
+			}
+			select {
+			case <-s.wake:
+				continue
+			case <-s.cancel:
+				return
+			}
+		}
+		for _, event := range queue {
+			select {
+			case s.out <- event:
+			case <-s.cancel:
+				return
+			}
+		}
+	}
+}
+
+func (s *subscriber[T]) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func collect(t *testing.T, ch <-chan int) []int {
+	t.Helper()
+	var got []int
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Fatalf("subscriber never closed, got %v", got)
+		}
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBusFansOutInOrder(t *testing.T) {
+	t.Parallel()
+
+	bus := New[int](0, 0, nil)
+	a, _ := bus.Subscribe()
+	b, _ := bus.Subscribe()
+	for i := 1; i <= 1000; i++ {
+		bus.Publish(i)
+	}
+	bus.Close()
+
+	for _, ch := range []<-chan int{a, b} {
+		got := collect(t, ch)
+		if len(got) != 1000 || got[0] != 1 || got[999] != 1000 {
+			t.Fatalf("unexpected events: %d received", len(got))
+		}
+		for i, v := range got {
+			if v != i+1 {
+				t.Fatalf("out of order at %d: %d", i, v)
+			}
+		}
+	}
+}
+
+func TestBusReplaysRecentEventsToLateSubscribers(t *testing.T) {
+	t.Parallel()
+
+	bus := New[int](3, 0, nil)
+	for i := 1; i <= 10; i++ {
+		bus.Publish(i)
+	}
+	late, _ := bus.Subscribe()
+	bus.Publish(11)
+	bus.Close()
+	if got := collect(t, late); !equal(got, []int{8, 9, 10, 11}) {
+		t.Fatalf("unexpected replay: %v", got)
+	}
+
+	closed, _ := bus.Subscribe()
+	if got := collect(t, closed); !equal(got, []int{9, 10, 11}) {
+		t.Fatalf("unexpected replay after close: %v", got)
+	}
+}
+
+func TestBusSlowSubscriberDoesNotBlockPublisher(t *testing.T) {
+	t.Parallel()
+
+	bus := New[int](0, 0, nil)
+	slow, cancel := bus.Subscribe()
+	fast, _ := bus.Subscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10000; i++ {
+			bus.Publish(i)
+		}
+		bus.Close()
+		close(done)
+	}()
+	if got := collect(t, fast); len(got) != 10000 {
+		t.Fatalf("fast subscriber got %d events", len(got))
+	}
+	<-done
+
+	<-slow
+	cancel()
+	for range slow {
+	}
+}
+
+func TestBusBoundsSlowSubscribers(t *testing.T) {
+	t.Parallel()
+
+	const limit = 8
+	odd := func(v int) bool { return v%2 == 1 }
+	bus := New[int](0, limit, odd)
+	slow, _ := bus.Subscribe()
+	for i := 1; i <= 1000; i++ {
+		bus.Publish(i)
+	}
+	bus.Close()
+
+	var evens, odds int
+	last := 0
+	for _, v := range collect(t, slow) {
+		if v <= last {
+			t.Fatalf("out of order: %d after %d", v, last)
+		}
+		last = v
+		if odd(v) {
+			odds++
+		} else {
+			evens++
+		}
+	}
+	if evens != 500 {
+		t.Fatalf("expected every event that cannot be dropped, got %d of 500", evens)
+	}
+	// One batch in delivery plus one full queue.
+	if odds > 2*limit {
+		t.Fatalf("expected droppable events beyond the limit to be dropped, got %d", odds)
+	}
+}
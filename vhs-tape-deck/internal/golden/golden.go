@@ -0,0 +1,131 @@
+// Package golden compares rendered frames with reference PNGs.
+package golden
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// JND is the CIE76 color difference below which two pixels look the same.
+const JND = 2.3
+
+type Diff struct {
+	Width   int
+	Height  int
+	Changed int
+	// Score is the share of pixels (0-1) that differ visibly.
+	Score float64
+	// MaxDelta is the largest color difference found, in CIE76 ΔE.
+	MaxDelta float64
+	// Image marks changed pixels in red over a faded copy of the reference.
+	Image *image.RGBA
+}
+
+// Compare decodes both PNGs and measures how many pixels differ by more
+// than JND. Colors are compared over black and over white so alpha changes
+// count as well.
+func Compare(referencePath, outputPath string) (*Diff, error) {
+	ref, err := readPNG(referencePath)
+	if err != nil {
+		return nil, fmt.Errorf("reference: %w", err)
+	}
+	out, err := readPNG(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	rb, ob := ref.Bounds(), out.Bounds()
+	if rb.Dx() != ob.Dx() || rb.Dy() != ob.Dy() {
+		return nil, fmt.Errorf("size mismatch: reference is %dx%d, output is %dx%d", rb.Dx(), rb.Dy(), ob.Dx(), ob.Dy())
+	}
+
+	d := &Diff{Width: rb.Dx(), Height: rb.Dy(), Image: image.NewRGBA(image.Rect(0, 0, rb.Dx(), rb.Dy()))}
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			rc := ref.At(rb.Min.X+x, rb.Min.Y+y)
+			oc := out.At(ob.Min.X+x, ob.Min.Y+y)
+			delta := math.Max(deltaE(rc, oc, 0), deltaE(rc, oc, 1))
+			d.MaxDelta = math.Max(d.MaxDelta, delta)
+			if delta > JND {
+				d.Changed++
+				d.Image.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			l := uint8(lab(rc, 1)[0] * 255 / 100 / 3)
+			d.Image.SetRGBA(x, y, color.RGBA{R: l, G: l, B: l, A: 255})
+		}
+	}
+	if total := d.Width * d.Height; total > 0 {
+		d.Score = float64(d.Changed) / float64(total)
+	}
+	return d, nil
+}
+
+// WritePNG saves the diff image, creating the directory if needed.
+func (d *Diff) WritePNG(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create diff dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create diff: %w", err)
+	}
+	if err := png.Encode(f, d.Image); err != nil {
+		f.Close()
+		return fmt.Errorf("encode diff: %w", err)
+	}
+	return f.Close()
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+func deltaE(a, b color.Color, background float64) float64 {
+	la, lb := lab(a, background), lab(b, background)
+	return math.Sqrt((la[0]-lb[0])*(la[0]-lb[0]) + (la[1]-lb[1])*(la[1]-lb[1]) + (la[2]-lb[2])*(la[2]-lb[2]))
+}
+
+// lab converts c, composited over a gray background level in [0,1], to
+// CIE L*a*b* (D65).
+func lab(c color.Color, background float64) [3]float64 {
+	r, g, b, a := c.RGBA()
+	alpha := float64(a) / 0xffff
+	// RGBA returns alpha-premultiplied values.
+	rl := linear(float64(r)/0xffff + background*(1-alpha))
+	gl := linear(float64(g)/0xffff + background*(1-alpha))
+	bl := linear(float64(b)/0xffff + background*(1-alpha))
+
+	x := (0.4124*rl + 0.3576*gl + 0.1805*bl) / 0.95047
+	y := 0.2126*rl + 0.7152*gl + 0.0722*bl
+	z := (0.0193*rl + 0.1192*gl + 0.9505*bl) / 1.08883
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27*t + 16) / 116
+}
@@ -0,0 +1,73 @@
+package golden
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base := solid(4, 4, color.NRGBA{R: 40, G: 90, B: 200, A: 255})
+	ref := writePNG(t, dir, "ref.png", base)
+
+	same := solid(4, 4, color.NRGBA{R: 41, G: 90, B: 200, A: 255})
+	d, err := Compare(ref, writePNG(t, dir, "same.png", same))
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if d.Changed != 0 {
+		t.Fatalf("an invisible change was counted: %+v", d)
+	}
+
+	changed := solid(4, 4, color.NRGBA{R: 40, G: 90, B: 200, A: 255})
+	changed.Set(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	changed.Set(1, 0, color.NRGBA{R: 40, G: 90, B: 200, A: 0})
+	d, err = Compare(ref, writePNG(t, dir, "changed.png", changed))
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if d.Changed != 2 || d.Score != 2.0/16 {
+		t.Fatalf("expected 2 changed pixels, got %d (score %v)", d.Changed, d.Score)
+	}
+	if got := d.Image.RGBAAt(1, 0); got.R != 255 || got.G != 0 {
+		t.Fatalf("alpha change not marked in diff image: %v", got)
+	}
+	if err := d.WritePNG(filepath.Join(dir, "out", "diff.png")); err != nil {
+		t.Fatalf("WritePNG: %v", err)
+	}
+
+	if _, err := Compare(ref, writePNG(t, dir, "small.png", solid(2, 2, color.NRGBA{A: 255}))); err == nil || !strings.Contains(err.Error(), "size mismatch") {
+		t.Fatalf("expected size mismatch, got %v", err)
+	}
+}
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func writePNG(t *testing.T, dir, name string, img image.Image) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("encode %s: %v", name, err)
+	}
+	return path
+}
@@ -0,0 +1,31 @@
+// Package hint attaches a user-facing fix to an error, so the UIs can show
+// what to do next below the failure instead of only the raw Go error.
+package hint
+
+import "errors"
+
+type Error struct {
+	Err  error
+	Hint string
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap returns err with hint attached; a nil err stays nil.
+func Wrap(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Err: err, Hint: hint}
+}
+
+// Of returns the outermost hint in err's chain, or "".
+func Of(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Hint
+	}
+	return ""
+}
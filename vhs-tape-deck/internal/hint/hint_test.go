@@ -0,0 +1,26 @@
+package hint
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOfFindsHintThroughWrapping(t *testing.T) {
+	t.Parallel()
+
+	errMissing := errors.New("manifest not found")
+	err := fmt.Errorf("start: %w", Wrap(fmt.Errorf("%w: a.yaml", errMissing), "check the manifest path"))
+	if got := Of(err); got != "check the manifest path" {
+		t.Fatalf("unexpected hint: %q", got)
+	}
+	if !errors.Is(err, errMissing) {
+		t.Fatalf("expected the sentinel to stay reachable")
+	}
+	if err.Error() != "start: manifest not found: a.yaml" {
+		t.Fatalf("hint leaked into the message: %q", err.Error())
+	}
+	if Of(errMissing) != "" || Wrap(nil, "x") != nil {
+		t.Fatalf("expected no hint for plain and nil errors")
+	}
+}
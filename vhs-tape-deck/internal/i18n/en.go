@@ -0,0 +1,203 @@
+package i18n
+
+var english = map[string]string{
+	"common.on":  "on",
+	"common.off": "off",
+	"common.yes": "yes",
+	"common.no":  "no",
+
+	"state.idle":     "idle",
+	"state.inserted": "inserted",
+	"state.running":  "running",
+	"state.success":  "success",
+	"state.failed":   "failed",
+
+	"sort.config": "config",
+	"sort.name":   "name",
+	"sort.recent": "recent",
+	"sort.status": "status",
+
+	"key.up":        "previous tape",
+	"key.down":      "next tape",
+	"key.insert":    "insert/eject",
+	"key.play":      "play",
+	"key.preview":   "preview frame",
+	"key.verify":    "verify golden frames",
+	"key.cancel":    "cancel run",
+	"key.edit":      "edit manifest",
+	"key.dup":       "duplicate tape",
+	"key.hidden":    "show/hide disabled tapes",
+	"key.sort":      "cycle shelf sort",
+	"key.share":     "share gif of last output",
+	"key.preset":    "cycle gif size preset",
+	"key.dry_run":   "toggle dry run",
+	"key.logs":      "clear logs",
+	"key.help":      "toggle help",
+	"key.quit":      "quit",
+	"key.confirm":   "confirm",
+	"key.dismiss":   "dismiss",
+	"key.whats_new": "what's new",
+	"key.pipelines": "pipelines",
+	"key.snapshot":  "save screenshot",
+
+	"status.stalled":             "stalled: %s",
+	"status.failed":              "failed (%d)",
+	"status.canceling":           "canceling...",
+	"status.dry_run":             "dry run: %s",
+	"status.logs_cleared":        "logs cleared",
+	"status.insert_first":        "insert a tape first",
+	"status.inserted_missing":    "inserted tape is missing",
+	"status.preview_disabled":    "preview is disabled for this tape",
+	"status.preview_unsupported": "preview unavailable (render-frame not supported)",
+	"hint.preview_unsupported":   "update vcr, or set preview.args to a subcommand this vcr supports",
+	"status.golden_none":         "no golden frames configured for this tape",
+	"status.queued":              "queued %s %s (%d pending)",
+	"status.start_failed":        "run failed to start",
+	"status.running_action":      "running %s",
+	"status.cannot_eject":        "cannot eject while running",
+	"status.tape_disabled":       "tape is disabled",
+	"status.ejected":             "tape ejected",
+	"status.inserted":            "tape inserted",
+	"status.session_restored":    "session restored",
+	"status.session_discarded":   "previous session discarded",
+	"status.queue_discarded":     "pending renders discarded",
+	"status.low_disk_skipped":    "%s not rendered: low disk space",
+	"status.gif_preset":          "gif preset: %s (%dpx @ %dfps)",
+	"status.gif_busy":            "gif already in progress",
+	"status.no_output":           "no output to share yet",
+	"status.creating":            "creating %s",
+	"status.gif_failed":          "gif failed",
+	"status.wrote":               "wrote %s",
+	"status.snapshot_failed":     "screenshot failed",
+	"status.dup_no_config":       "cannot duplicate: config path unknown",
+	"status.dup_busy":            "cannot duplicate while running",
+	"status.dup_failed":          "duplicate failed",
+	"status.reload_failed":       "config reload failed",
+	"status.duplicated":          "duplicated %s as %s",
+	"status.no_manifest":         "cannot resolve manifest",
+	"status.editing":             "editing %s",
+	"status.editor_failed":       "editor failed",
+	"status.validating":          "validating manifest...",
+	"status.manifest_invalid":    "manifest invalid",
+	"status.manifest_valid":      "manifest valid",
+	"status.sorted":              "shelf sorted by %s",
+	"status.showing_disabled":    "showing disabled tapes",
+	"status.hiding_disabled":     "hiding disabled tapes",
+	"status.vcr_outdated":        "vcr is older than vcr_min_version; only dry runs allowed",
+	"status.no_disabled":         "no disabled tapes",
+	"status.pipeline_busy":       "a pipeline is already running",
+	"status.pipeline_wait":       "wait for the current run to finish",
+	"status.pipeline_running":    "running pipeline %s",
+	"status.pipeline_done":       "pipeline %s: %s",
+
+	"view.loading": "loading tape deck...",
+	"help.title":   "Tape Deck Help",
+	"help.body":    "Enter inserts/ejects the selected tape.\nSpace plays the inserted tape.\nCtrl+X cancels an active run.\nP runs preview if enabled.",
+	"legend":       "Status: %s",
+
+	"whatsnew.title":   "What's new in tape-deck %s",
+	"whatsnew.keys":    "Keys:",
+	"whatsnew.dismiss": "Press any key to close.",
+
+	"pipelines.title":         "Pipelines",
+	"pipelines.none":          "No pipelines configured; add a pipelines: section to the config.",
+	"pipelines.item":          "%s (%d steps)",
+	"pipelines.run":           "%s (%s)",
+	"pipelines.footer":        "space/enter: run  d: dry run  ctrl+x: cancel  esc/P: close",
+	"pipelines.step_pending":  "pending",
+	"pipelines.step_running":  "running",
+	"pipelines.step_retrying": "retrying",
+	"pipelines.step_success":  "success",
+	"pipelines.step_failed":   "failed",
+	"pipelines.step_canceled": "canceled",
+	"pipelines.step_skipped":  "skipped",
+
+	"shelf.title":        "Tape Shelf",
+	"shelf.sorted":       " (by %s)",
+	"shelf.inserted":     " [IN]",
+	"shelf.off":          " [off]",
+	"shelf.render_frame": "render-frame: %s",
+	"shelf.vcr":          "vcr: %s",
+	"shelf.vcr_outdated": "vcr: %s (needs >= %s)",
+	"shelf.doctor":       "doctor: %s",
+
+	"meta.title":         "Tape Metadata",
+	"meta.manifest":      "Manifest: %s",
+	"meta.mode":          "Mode: %s",
+	"meta.output":        "Output: %s",
+	"meta.args":          "Primary Args: %s",
+	"meta.preview":       "Preview: frame=%d args=%s",
+	"meta.preview_off":   "Preview: disabled",
+	"meta.notes":         "Notes: %s",
+	"meta.disabled":      "Status: disabled",
+	"meta.template":      "Template: %s",
+	"meta.alpha":         "Alpha: required",
+	"meta.progress":      "Progress:",
+	"meta.activity":      "Activity: %s",
+	"meta.no_stats":      "Stats: no runs yet",
+	"meta.stats":         "Stats: %d runs | %.0f%% ok",
+	"meta.stats_avg":     " | avg %s",
+	"meta.stats_last":    " | last %s",
+	"meta.last_output":   "Last Output: %s",
+	"meta.probe_running": "Probe: running ffprobe...",
+	"meta.probe_missing": "Probe: install ffprobe to inspect outputs",
+	"meta.probe_failed":  "Probe: failed",
+	"meta.probe":         "Probe: %s",
+
+	"footer.status": "status=%s | dry-run=%s",
+	"footer.queue":  " | queue=%d",
+	"footer.last":   " | last=%s",
+	"footer.disk":   " | renders=%s",
+	"footer.hint":   "hint: %s",
+
+	"prompt.yes_no":          "[y] yes  [n] no",
+	"prompt.restore_title":   "Restore Session",
+	"prompt.crashed":         "The last session ended in a crash.",
+	"prompt.saved":           "A previous session was saved.",
+	"prompt.inserted":        "Inserted tape: %s",
+	"prompt.selected":        "Selected tape: %s",
+	"prompt.saved_at":        "Saved: %s",
+	"prompt.restore":         "Restore it?",
+	"prompt.resume_title":    "Resume Queue",
+	"prompt.resume_header":   "%d render(s) were pending when the deck last exited:",
+	"prompt.resume_more":     "  ... and %d more",
+	"prompt.resume":          "Resume them?",
+	"prompt.manifest_title":  "Manifest Updated",
+	"prompt.manifest_passed": "%s passed vcr check.\n\nInsert and render it now?",
+	"prompt.low_disk_title":  "Low Disk Space",
+	"prompt.low_disk":        "Only %s free where %s writes (disk.min_free_mb is %d).\n\nRender anyway?",
+
+	"plain.help":             "commands:\n  list               list tapes with their last result\n  play <tape>        render a tape (by id or list number)\n  preview <tape>     render the tape's preview frame\n  verify <tape>      compare the tape's golden frames\n  cancel             cancel the active run\n  dry on|off         toggle dry-run\n  logs on|off        stream render output (default off)\n  status             show the active run and queue\n  help               show this help\n  quit               cancel any run and exit",
+	"plain.ready":            "tape deck ready: %d tapes, type help for commands",
+	"plain.list_item":        "%d. %s: %s (%s%s), %s",
+	"plain.not_run":          "not run",
+	"plain.disabled_suffix":  ", disabled",
+	"plain.nothing_running":  "nothing is running",
+	"plain.cancel_requested": "cancel requested for %s",
+	"plain.dry_run":          "dry run %s",
+	"plain.logs":             "render output %s",
+	"plain.idle":             "idle, dry run %s",
+	"plain.running":          "running %s %s",
+	"plain.queued_count":     "%d queued",
+	"plain.unknown_command":  "unknown command %q, type help",
+	"plain.unknown_tape":     "unknown tape %q, type list",
+	"plain.tape_disabled":    "%s is disabled",
+	"plain.no_preview":       "%s has no preview configured",
+	"plain.no_golden":        "%s has no golden frames configured",
+	"plain.queued":           "queued %s %s, %d waiting",
+	"plain.tape_gone":        "skipping %s: tape no longer in config",
+	"plain.start_failed":     "%s failed to start: %v",
+	"plain.started":          "started %s %s",
+	"plain.warning":          "warning: %s",
+	"plain.low_disk":         "warning: only %s free in %s",
+	"plain.progress":         "%s %d%% (%s)",
+	"plain.success":          "success",
+	"plain.failed":           "failed, exit %d",
+	"plain.canceled":         "canceled",
+	"plain.finished":         "finished %s: %s, %s",
+	"plain.hint":             "hint: %s",
+	"plain.output":           "output: %s",
+	"plain.uploaded":         "uploaded: %s",
+	"plain.record_error":     "record error: %v",
+	"plain.canceling":        "canceling %s",
+}
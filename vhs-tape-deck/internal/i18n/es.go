@@ -0,0 +1,203 @@
+package i18n
+
+var spanish = map[string]string{
+	"common.on":  "sí",
+	"common.off": "no",
+	"common.yes": "sí",
+	"common.no":  "no",
+
+	"state.idle":     "inactiva",
+	"state.inserted": "insertada",
+	"state.running":  "en curso",
+	"state.success":  "correcta",
+	"state.failed":   "fallida",
+
+	"sort.config": "configuración",
+	"sort.name":   "nombre",
+	"sort.recent": "recientes",
+	"sort.status": "estado",
+
+	"key.up":        "cinta anterior",
+	"key.down":      "cinta siguiente",
+	"key.insert":    "insertar/expulsar",
+	"key.play":      "reproducir",
+	"key.preview":   "fotograma de vista previa",
+	"key.verify":    "verificar fotogramas de referencia",
+	"key.cancel":    "cancelar render",
+	"key.edit":      "editar manifiesto",
+	"key.dup":       "duplicar cinta",
+	"key.hidden":    "mostrar/ocultar cintas desactivadas",
+	"key.sort":      "cambiar orden del estante",
+	"key.share":     "compartir gif de la última salida",
+	"key.preset":    "cambiar tamaño del gif",
+	"key.dry_run":   "activar/desactivar simulación",
+	"key.logs":      "limpiar registros",
+	"key.help":      "mostrar/ocultar ayuda",
+	"key.quit":      "salir",
+	"key.confirm":   "confirmar",
+	"key.dismiss":   "descartar",
+	"key.whats_new": "novedades",
+	"key.pipelines": "pipelines",
+	"key.snapshot":  "guardar captura",
+
+	"status.stalled":             "detenido: %s",
+	"status.failed":              "falló (%d)",
+	"status.canceling":           "cancelando...",
+	"status.dry_run":             "simulación: %s",
+	"status.logs_cleared":        "registros borrados",
+	"status.insert_first":        "inserta una cinta primero",
+	"status.inserted_missing":    "la cinta insertada no existe",
+	"status.preview_disabled":    "la vista previa está desactivada para esta cinta",
+	"status.preview_unsupported": "vista previa no disponible (render-frame no soportado)",
+	"hint.preview_unsupported":   "actualiza vcr o define preview.args con un subcomando que este vcr admita",
+	"status.golden_none":         "esta cinta no tiene fotogramas de referencia",
+	"status.queued":              "en cola %s %s (%d pendientes)",
+	"status.start_failed":        "no se pudo iniciar el render",
+	"status.running_action":      "ejecutando %s",
+	"status.cannot_eject":        "no se puede expulsar durante un render",
+	"status.tape_disabled":       "la cinta está desactivada",
+	"status.ejected":             "cinta expulsada",
+	"status.inserted":            "cinta insertada",
+	"status.session_restored":    "sesión restaurada",
+	"status.session_discarded":   "sesión anterior descartada",
+	"status.queue_discarded":     "renders pendientes descartados",
+	"status.low_disk_skipped":    "%s no renderizada: poco espacio en disco",
+	"status.gif_preset":          "tamaño de gif: %s (%dpx @ %dfps)",
+	"status.gif_busy":            "ya se está creando un gif",
+	"status.no_output":           "todavía no hay salida para compartir",
+	"status.creating":            "creando %s",
+	"status.gif_failed":          "falló el gif",
+	"status.wrote":               "escrito %s",
+	"status.snapshot_failed":     "falló la captura",
+	"status.dup_no_config":       "no se puede duplicar: ruta de configuración desconocida",
+	"status.dup_busy":            "no se puede duplicar durante un render",
+	"status.dup_failed":          "falló la duplicación",
+	"status.reload_failed":       "falló la recarga de la configuración",
+	"status.duplicated":          "%s duplicada como %s",
+	"status.no_manifest":         "no se puede resolver el manifiesto",
+	"status.editing":             "editando %s",
+	"status.editor_failed":       "falló el editor",
+	"status.validating":          "validando manifiesto...",
+	"status.manifest_invalid":    "manifiesto no válido",
+	"status.manifest_valid":      "manifiesto válido",
+	"status.sorted":              "estante ordenado por %s",
+	"status.showing_disabled":    "mostrando cintas desactivadas",
+	"status.hiding_disabled":     "ocultando cintas desactivadas",
+	"status.vcr_outdated":        "vcr es anterior a vcr_min_version; solo se permiten simulaciones",
+	"status.no_disabled":         "no hay cintas desactivadas",
+	"status.pipeline_busy":       "ya hay un pipeline en marcha",
+	"status.pipeline_wait":       "espera a que termine el render actual",
+	"status.pipeline_running":    "ejecutando pipeline %s",
+	"status.pipeline_done":       "pipeline %s: %s",
+
+	"view.loading": "cargando tape deck...",
+	"help.title":   "Ayuda de Tape Deck",
+	"help.body":    "Enter inserta/expulsa la cinta seleccionada.\nEspacio reproduce la cinta insertada.\nCtrl+X cancela un render activo.\nP genera la vista previa si está activada.",
+	"legend":       "Estado: %s",
+
+	"whatsnew.title":   "Novedades de tape-deck %s",
+	"whatsnew.keys":    "Teclas:",
+	"whatsnew.dismiss": "Pulsa cualquier tecla para cerrar.",
+
+	"pipelines.title":         "Pipelines",
+	"pipelines.none":          "No hay pipelines configurados; añade una sección pipelines: a la configuración.",
+	"pipelines.item":          "%s (%d pasos)",
+	"pipelines.run":           "%s (%s)",
+	"pipelines.footer":        "espacio/enter: ejecutar  d: simulación  ctrl+x: cancelar  esc/P: cerrar",
+	"pipelines.step_pending":  "pendiente",
+	"pipelines.step_running":  "en curso",
+	"pipelines.step_retrying": "reintentando",
+	"pipelines.step_success":  "correcto",
+	"pipelines.step_failed":   "fallido",
+	"pipelines.step_canceled": "cancelado",
+	"pipelines.step_skipped":  "omitido",
+
+	"shelf.title":        "Estante de cintas",
+	"shelf.sorted":       " (por %s)",
+	"shelf.inserted":     " [DENTRO]",
+	"shelf.off":          " [desact.]",
+	"shelf.render_frame": "render-frame: %s",
+	"shelf.vcr":          "vcr: %s",
+	"shelf.vcr_outdated": "vcr: %s (requiere >= %s)",
+	"shelf.doctor":       "doctor: %s",
+
+	"meta.title":         "Datos de la cinta",
+	"meta.manifest":      "Manifiesto: %s",
+	"meta.mode":          "Modo: %s",
+	"meta.output":        "Salida: %s",
+	"meta.args":          "Argumentos: %s",
+	"meta.preview":       "Vista previa: fotograma=%d args=%s",
+	"meta.preview_off":   "Vista previa: desactivada",
+	"meta.notes":         "Notas: %s",
+	"meta.disabled":      "Estado: desactivada",
+	"meta.template":      "Plantilla: %s",
+	"meta.alpha":         "Alfa: obligatorio",
+	"meta.progress":      "Progreso:",
+	"meta.activity":      "Actividad: %s",
+	"meta.no_stats":      "Estadísticas: sin renders todavía",
+	"meta.stats":         "Estadísticas: %d renders | %.0f%% correctos",
+	"meta.stats_avg":     " | media %s",
+	"meta.stats_last":    " | último %s",
+	"meta.last_output":   "Última salida: %s",
+	"meta.probe_running": "Análisis: ejecutando ffprobe...",
+	"meta.probe_missing": "Análisis: instala ffprobe para inspeccionar salidas",
+	"meta.probe_failed":  "Análisis: falló",
+	"meta.probe":         "Análisis: %s",
+
+	"footer.status": "estado=%s | simulación=%s",
+	"footer.queue":  " | cola=%d",
+	"footer.last":   " | último=%s",
+	"footer.disk":   " | renders=%s",
+	"footer.hint":   "sugerencia: %s",
+
+	"prompt.yes_no":          "[y] sí  [n] no",
+	"prompt.restore_title":   "Restaurar sesión",
+	"prompt.crashed":         "La última sesión terminó con un fallo.",
+	"prompt.saved":           "Se guardó una sesión anterior.",
+	"prompt.inserted":        "Cinta insertada: %s",
+	"prompt.selected":        "Cinta seleccionada: %s",
+	"prompt.saved_at":        "Guardada: %s",
+	"prompt.restore":         "¿Restaurarla?",
+	"prompt.resume_title":    "Reanudar cola",
+	"prompt.resume_header":   "%d render(s) quedaron pendientes al cerrar el deck:",
+	"prompt.resume_more":     "  ... y %d más",
+	"prompt.resume":          "¿Reanudarlos?",
+	"prompt.manifest_title":  "Manifiesto actualizado",
+	"prompt.manifest_passed": "%s superó vcr check.\n\n¿Insertarla y renderizarla ahora?",
+	"prompt.low_disk_title":  "Poco espacio en disco",
+	"prompt.low_disk":        "Solo quedan %s libres donde escribe %s (disk.min_free_mb es %d).\n\n¿Renderizar de todos modos?",
+
+	"plain.help":             "comandos:\n  list               lista las cintas con su último resultado\n  play <cinta>       renderiza una cinta (por id o número)\n  preview <cinta>    renderiza el fotograma de vista previa\n  verify <cinta>     compara los fotogramas de referencia\n  cancel             cancela el render activo\n  dry on|off         activa/desactiva la simulación\n  logs on|off        muestra la salida del render (desactivado por defecto)\n  status             muestra el render activo y la cola\n  help               muestra esta ayuda\n  quit               cancela cualquier render y sale",
+	"plain.ready":            "tape deck listo: %d cintas, escribe help para ver los comandos",
+	"plain.list_item":        "%d. %s: %s (%s%s), %s",
+	"plain.not_run":          "sin renders",
+	"plain.disabled_suffix":  ", desactivada",
+	"plain.nothing_running":  "no hay nada en curso",
+	"plain.cancel_requested": "cancelación solicitada para %s",
+	"plain.dry_run":          "simulación %s",
+	"plain.logs":             "salida del render %s",
+	"plain.idle":             "inactivo, simulación %s",
+	"plain.running":          "ejecutando %s %s",
+	"plain.queued_count":     "%d en cola",
+	"plain.unknown_command":  "comando desconocido %q, escribe help",
+	"plain.unknown_tape":     "cinta desconocida %q, escribe list",
+	"plain.tape_disabled":    "%s está desactivada",
+	"plain.no_preview":       "%s no tiene vista previa configurada",
+	"plain.no_golden":        "%s no tiene fotogramas de referencia",
+	"plain.queued":           "en cola %s %s, %d esperando",
+	"plain.tape_gone":        "omitiendo %s: la cinta ya no está en la configuración",
+	"plain.start_failed":     "%s no pudo iniciarse: %v",
+	"plain.started":          "iniciado %s %s",
+	"plain.warning":          "aviso: %s",
+	"plain.low_disk":         "aviso: solo quedan %s libres en %s",
+	"plain.progress":         "%s %d%% (%s)",
+	"plain.success":          "correcto",
+	"plain.failed":           "falló, código %d",
+	"plain.canceled":         "cancelado",
+	"plain.finished":         "terminado %s: %s, %s",
+	"plain.hint":             "sugerencia: %s",
+	"plain.output":           "salida: %s",
+	"plain.uploaded":         "subida: %s",
+	"plain.record_error":     "error del registro: %v",
+	"plain.canceling":        "cancelando %s",
+}
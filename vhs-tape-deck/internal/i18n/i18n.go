@@ -0,0 +1,98 @@
+// Package i18n holds the deck's user-facing strings. Messages are looked up
+// by key in the active locale, falling back to English and then to the key
+// itself so a missing translation never blanks the UI.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": english,
+	"es": spanish,
+}
+
+type Catalog struct {
+	locale string
+	msgs   map[string]string
+}
+
+// New returns the catalog for locale, or English when it is unknown.
+func New(locale string) *Catalog {
+	if code, ok := Normalize(locale); ok {
+		return &Catalog{locale: code, msgs: catalogs[code]}
+	}
+	return &Catalog{locale: DefaultLocale, msgs: english}
+}
+
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// T formats the message for key with args, fmt.Sprintf style.
+func (c *Catalog) T(key string, args ...any) string {
+	msg, ok := c.msgs[key]
+	if !ok {
+		if msg, ok = english[key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// OnOff renders a toggle state in the catalog's language.
+func (c *Catalog) OnOff(v bool) string {
+	if v {
+		return c.T("common.on")
+	}
+	return c.T("common.off")
+}
+
+// Locales lists the supported locale codes.
+func Locales() []string {
+	out := make([]string, 0, len(catalogs))
+	for code := range catalogs {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Normalize reduces a locale tag such as "es_MX.UTF-8" or "es-419" to a
+// supported catalog code.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := strings.IndexAny(tag, "_-"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag, true
+	}
+	return "", false
+}
+
+// Detect picks the locale: the configured value when set, otherwise the
+// first of LC_ALL, LC_MESSAGES and LANG that is set, following POSIX
+// precedence. Unsupported locales (including C and POSIX) give English.
+func Detect(configured string) string {
+	if strings.TrimSpace(configured) != "" {
+		return New(configured).Locale()
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return New(v).Locale()
+		}
+	}
+	return DefaultLocale
+}
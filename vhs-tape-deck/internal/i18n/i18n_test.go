@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	t.Parallel()
+
+	for code, msgs := range catalogs {
+		for key, en := range english {
+			msg, ok := msgs[key]
+			if !ok {
+				t.Fatalf("%s: missing key %q", code, key)
+			}
+			if got, want := verbs(msg), verbs(en); got != want {
+				t.Fatalf("%s: %q has verbs %q, english has %q", code, key, got, want)
+			}
+		}
+		for key := range msgs {
+			if _, ok := english[key]; !ok {
+				t.Fatalf("%s: key %q is not in the english catalog", code, key)
+			}
+		}
+	}
+}
+
+func TestNormalizeAndFallback(t *testing.T) {
+	t.Parallel()
+
+	for tag, want := range map[string]string{"es_MX.UTF-8": "es", "es-419": "es", "EN_us": "en", "de_DE": "", "C": "", "": ""} {
+		got, _ := Normalize(tag)
+		if got != want {
+			t.Fatalf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+
+	c := New("fr_FR.UTF-8")
+	if c.Locale() != "en" || c.T("status.wrote", "a.gif") != "wrote a.gif" {
+		t.Fatalf("expected english fallback, got %s %q", c.Locale(), c.T("status.wrote", "a.gif"))
+	}
+	if got := New("es").T("no.such.key"); got != "no.such.key" {
+		t.Fatalf("expected key fallback, got %q", got)
+	}
+}
+
+func TestDetectPrefersConfigThenEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+
+	if got := Detect(""); got != "es" {
+		t.Fatalf("expected LANG to select es, got %q", got)
+	}
+	if got := Detect("en"); got != "en" {
+		t.Fatalf("expected config to win, got %q", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := Detect(""); got != "en" {
+		t.Fatalf("expected LC_ALL=C to select en, got %q", got)
+	}
+}
+
+// verbs keeps order: translations must consume args in the same sequence.
+func verbs(msg string) string {
+	return strings.Join(verbPattern.FindAllString(msg, -1), "")
+}
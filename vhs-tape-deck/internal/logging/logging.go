@@ -0,0 +1,273 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultMaxBytes = 5 * 1024 * 1024
+	DefaultMaxFiles = 3
+)
+
+type Options struct {
+	Dir      string
+	Name     string
+	Level    slog.Level
+	JSON     bool
+	MaxBytes int64
+	MaxFiles int
+	// Mirror receives every record formatted as a single line, e.g. to echo
+	// logs into the TUI when --verbose is set.
+	Mirror func(line string)
+}
+
+// DefaultDir is the log directory shared by the VCR Go tools.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, ".vcr", "logs"), nil
+}
+
+func ParseLevel(v string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (valid: debug, info, warn, error)", v)
+	}
+}
+
+// New opens <Dir>/<Name>.log with size-based rotation and returns a logger
+// writing to it. The returned closer must be closed on exit.
+func New(opts Options) (*slog.Logger, io.Closer, error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return nil, nil, fmt.Errorf("log name is required")
+	}
+	if strings.TrimSpace(opts.Dir) == "" {
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.Dir = dir
+	}
+
+	w, err := OpenRotating(filepath.Join(opts.Dir, opts.Name+".log"), opts.MaxBytes, opts.MaxFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	var handler slog.Handler
+	if opts.JSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOpts)
+	}
+	if opts.Mirror != nil {
+		handler = fanout{handler, &mirrorHandler{level: opts.Level, emit: opts.Mirror}}
+	}
+	return slog.New(handler), w, nil
+}
+
+// Discard returns a logger that drops everything; packages use it when no
+// logger has been configured.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// Component tags every record from the returned logger with its subsystem.
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	if logger == nil {
+		return Discard()
+	}
+	return logger.With("component", name)
+}
+
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+type mirrorHandler struct {
+	level slog.Level
+	emit  func(string)
+	attrs []slog.Attr
+}
+
+func (h *mirrorHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *mirrorHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	component := ""
+	var rest []string
+	add := func(a slog.Attr) {
+		if a.Key == "component" {
+			component = a.Value.String()
+			return
+		}
+		rest = append(rest, a.Key+"="+a.Value.String())
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(a)
+		return true
+	})
+	if component != "" {
+		b.WriteString(" [" + component + "]")
+	}
+	b.WriteString(" " + r.Message)
+	if len(rest) > 0 {
+		b.WriteString(" " + strings.Join(rest, " "))
+	}
+	h.emit(b.String())
+	return nil
+}
+
+func (h *mirrorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &next
+}
+
+func (h *mirrorHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// RotatingFile is an io.WriteCloser that rolls path over to path.1, path.2,
+// ... once it would grow beyond maxBytes, keeping at most maxFiles backups.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+func OpenRotating(path string, maxBytes int64, maxFiles int) (*RotatingFile, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+	w := &RotatingFile{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFile) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingFile) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotatingFile) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *RotatingFile) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", w.path, i)
+		if _, err := os.Stat(from); err == nil {
+			_ = os.Rename(from, fmt.Sprintf("%s.%d", w.path, i+1))
+		}
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return w.open()
+}
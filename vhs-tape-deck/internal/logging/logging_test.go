@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "deck.log")
+	w, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotating: %v", err)
+	}
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	assertFile(t, path, "dddddddd\n")
+	assertFile(t, path+".1", "cccccccc\n")
+	assertFile(t, path+".2", "bbbbbbbb\n")
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only two backups, stat err=%v", err)
+	}
+}
+
+func TestNewMirrorsWithComponentAndLevel(t *testing.T) {
+	t.Parallel()
+
+	var mirrored []string
+	logger, closer, err := New(Options{
+		Dir:    t.TempDir(),
+		Name:   "tape-deck",
+		Level:  slog.LevelInfo,
+		Mirror: func(line string) { mirrored = append(mirrored, line) },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer closer.Close()
+
+	log := Component(logger, "runner")
+	log.Debug("hidden")
+	log.Info("run started", "run_id", "r1")
+
+	if len(mirrored) != 1 {
+		t.Fatalf("expected one mirrored line, got %v", mirrored)
+	}
+	if mirrored[0] != "INFO [runner] run started run_id=r1" {
+		t.Fatalf("unexpected mirrored line: %q", mirrored[0])
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	level, err := ParseLevel("WARN")
+	if err != nil || level != slog.LevelWarn {
+		t.Fatalf("unexpected level %v err=%v", level, err)
+	}
+	if _, err := ParseLevel("loud"); err == nil || !strings.Contains(err.Error(), "loud") {
+		t.Fatalf("expected unknown level error, got %v", err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(buf) != want {
+		t.Fatalf("%s: expected %q got %q", filepath.Base(path), want, buf)
+	}
+}
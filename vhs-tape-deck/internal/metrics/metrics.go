@@ -0,0 +1,208 @@
+// Package metrics keeps counters, gauges and histograms for `tape-deck
+// serve` and writes them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Prometheus text format served by Registry.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DurationBuckets are histogram bounds in seconds, from quick previews to
+// long renders.
+var DurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600}
+
+// Registry holds metrics in the order they were added. Every metric has at
+// most one label, keyed by its value.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+type metric interface {
+	write(w io.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+type header struct {
+	name, help, label string
+}
+
+func (h header) write(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", h.name, escapeHelp(h.help), h.name, kind)
+}
+
+// labels renders {label="value"} plus extra pairs, or nothing without any.
+func (h header) labels(value string, extra ...string) string {
+	var pairs []string
+	if h.label != "" {
+		pairs = append(pairs, h.label+`="`+escapeLabel(value)+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter only goes up. An empty label makes it a single series.
+type Counter struct {
+	header
+	reg    *Registry
+	values map[string]float64
+}
+
+func (r *Registry) Counter(name, help, label string) *Counter {
+	c := &Counter{header: header{name, help, label}, reg: r, values: map[string]float64{}}
+	r.add(c)
+	return c
+}
+
+// Inc adds one to the series for the label value.
+func (c *Counter) Inc(value string) {
+	c.reg.mu.Lock()
+	defer c.reg.mu.Unlock()
+	c.values[value]++
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.header.write(w, "counter")
+	for _, v := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labels(v), number(c.values[v]))
+	}
+}
+
+// Gauge is a single value that goes up and down.
+type Gauge struct {
+	header
+	reg   *Registry
+	value float64
+}
+
+func (r *Registry) Gauge(name, help string) *Gauge {
+	g := &Gauge{header: header{name: name, help: help}, reg: r}
+	r.add(g)
+	return g
+}
+
+func (g *Gauge) Set(v float64) {
+	g.reg.mu.Lock()
+	defer g.reg.mu.Unlock()
+	g.value = v
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.header.write(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, number(g.value))
+}
+
+// Histogram counts observations into cumulative buckets per label value.
+type Histogram struct {
+	header
+	reg     *Registry
+	buckets []float64
+	series  map[string]*histSeries
+}
+
+type histSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (r *Registry) Histogram(name, help, label string, buckets []float64) *Histogram {
+	h := &Histogram{header: header{name, help, label}, reg: r, buckets: buckets, series: map[string]*histSeries{}}
+	r.add(h)
+	return h
+}
+
+func (h *Histogram) Observe(value string, v float64) {
+	h.reg.mu.Lock()
+	defer h.reg.mu.Unlock()
+	s := h.series[value]
+	if s == nil {
+		s = &histSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[value] = s
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.header.write(w, "histogram")
+	for _, v := range sortedKeys(h.series) {
+		s := h.series[v]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(v, "le", number(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(v, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labels(v), number(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labels(v), s.count)
+	}
+}
+
+func (r *Registry) add(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Write prints every metric in the text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	r.mu.Lock()
+	for _, m := range r.metrics {
+		m.write(bw)
+	}
+	r.mu.Unlock()
+	return bw.Flush()
+}
+
+// ServeHTTP answers scrapes.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = r.Write(w)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func number(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
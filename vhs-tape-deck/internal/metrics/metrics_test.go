@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteTextFormat(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	runs := r.Counter("runs_total", "Runs.", "tape")
+	depth := r.Gauge("queue_depth", "Waiting jobs.")
+	duration := r.Histogram("duration_seconds", "Durations.", "tape", []float64{1, 10})
+
+	runs.Inc("beta")
+	runs.Inc("alpha")
+	runs.Inc("beta")
+	runs.Inc(`say "hi"`)
+	depth.Set(3)
+	duration.Observe("alpha", 0.5)
+	duration.Observe("alpha", 4)
+	duration.Observe("alpha", 20)
+
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := `# HELP runs_total Runs.
+# TYPE runs_total counter
+runs_total{tape="alpha"} 1
+runs_total{tape="beta"} 2
+runs_total{tape="say \"hi\""} 1
+# HELP queue_depth Waiting jobs.
+# TYPE queue_depth gauge
+queue_depth 3
+# HELP duration_seconds Durations.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{tape="alpha",le="1"} 1
+duration_seconds_bucket{tape="alpha",le="10"} 2
+duration_seconds_bucket{tape="alpha",le="+Inf"} 3
+duration_seconds_sum{tape="alpha"} 24.5
+duration_seconds_count{tape="alpha"} 3
+`
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Counter("runs_total", "Runs.", "").Inc("")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("content type %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "\nruns_total 1\n") {
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+}
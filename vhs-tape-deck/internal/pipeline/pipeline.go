@@ -0,0 +1,464 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/trace"
+)
+
+// StatusSkipped marks steps that never ran because an earlier step failed or
+// the pipeline was canceled.
+const StatusSkipped runner.RunStatus = "skipped"
+
+type EventType string
+
+const (
+	EventStepStarted  EventType = "step_started"
+	EventLog          EventType = "log"
+	EventProgress     EventType = "progress"
+	EventStepRetrying EventType = "step_retrying"
+	EventStepFinished EventType = "step_finished"
+	EventFinished     EventType = "finished"
+)
+
+type Event struct {
+	Type    EventType
+	Step    string
+	Message string
+	// Overall is the pipeline's progress in [0,1]: finished steps plus the
+	// running step's own fraction, divided by the step count.
+	Overall   float64
+	Result    *StepResult
+	Record    *Record
+	RecordErr error
+}
+
+type StepResult struct {
+	ID          string           `json:"id"`
+	Tape        string           `json:"tape,omitempty"`
+	Command     []string         `json:"command,omitempty"`
+	RunID       string           `json:"run_id,omitempty"`
+	Status      runner.RunStatus `json:"status"`
+	ExitCode    int              `json:"exit_code"`
+	OutputPaths []string         `json:"output_paths,omitempty"`
+	// Output is the step's {{steps.<id>.output}} value: the first output
+	// path of a tape, or the last line a command printed on stdout.
+	Output     string `json:"output,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Attempts   int    `json:"attempts,omitempty"`
+	// Ignored is set when the step failed but has continue_on_error.
+	Ignored bool `json:"ignored,omitempty"`
+	// Error is the failure message, or why the step was skipped.
+	Error string `json:"error,omitempty"`
+}
+
+// Record is the combined record of one pipeline run; each step also writes
+// its own run record.
+type Record struct {
+	RunID      string           `json:"run_id"`
+	PipelineID string           `json:"pipeline_id"`
+	Timestamp  time.Time        `json:"timestamp"`
+	DryRun     bool             `json:"dry_run"`
+	Trigger    string           `json:"trigger,omitempty"`
+	Status     runner.RunStatus `json:"status"`
+	DurationMS int64            `json:"duration_ms,omitempty"`
+	Steps      []StepResult     `json:"steps"`
+}
+
+type Request struct {
+	Config   *config.Config
+	Pipeline config.Pipeline
+	DryRun   bool
+	// Trigger is copied into the pipeline record and every step's run record.
+	Trigger string
+}
+
+var varPattern = regexp.MustCompile(`\{\{\s*(?:pipeline\.([a-z_]+)|steps\.([A-Za-z0-9_\-]+)\.([a-z_]+))\s*\}\}`)
+
+func Find(cfg *config.Config, id string) (config.Pipeline, bool) {
+	for _, p := range cfg.Pipelines {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return config.Pipeline{}, false
+}
+
+func RecordPath(runsDir, runID string) string {
+	return filepath.Join(runsDir, "pipelines", runID+".json")
+}
+
+func WriteRecord(path string, record *Record) error {
+	buf, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal pipeline record: %w", err)
+	}
+	if err := atomicfile.Write(path, append(buf, '\n')); err != nil {
+		return fmt.Errorf("write pipeline record: %w", err)
+	}
+	return nil
+}
+
+// Start runs the pipeline's steps one at a time in dependency order. The
+// returned channel closes after the EventFinished carrying the record.
+func Start(ctx context.Context, run *runner.Runner, req Request) (<-chan Event, error) {
+	if req.Config == nil {
+		return nil, errors.New("missing config")
+	}
+	steps, err := req.Pipeline.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	runID, claim, err := runner.ReserveID(req.Config.RunsDir, "pipelines", req.Pipeline.ID, now, func(id string) bool {
+		_, err := os.Stat(RecordPath(req.Config.RunsDir, id))
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	record := &Record{
+		RunID:      runID,
+		PipelineID: req.Pipeline.ID,
+		Timestamp:  now,
+		DryRun:     req.DryRun,
+		Trigger:    req.Trigger,
+		Status:     runner.StatusSuccess,
+	}
+	events := make(chan Event, 128)
+	go func() {
+		defer runner.ReleaseID(claim)
+		execute(ctx, run, req, steps, record, events)
+	}()
+	return events, nil
+}
+
+func execute(ctx context.Context, run *runner.Runner, req Request, steps []config.PipelineStep, record *Record, events chan<- Event) {
+	defer close(events)
+	ctx, span := trace.Start(ctx, "pipeline", trace.String("pipeline", req.Pipeline.ID), trace.String("run_id", record.RunID), trace.Bool("dry_run", req.DryRun))
+	defer func() {
+		span.Set(trace.String("status", string(record.Status)))
+		if record.Status == runner.StatusFailed {
+			span.Fail("pipeline failed")
+		}
+		span.End()
+	}()
+
+	startedAt := time.Now()
+	deps := req.Pipeline.Deps()
+	byID := make(map[string]config.PipelineStep, len(steps))
+	for _, step := range steps {
+		byID[step.ID] = step
+	}
+	results := map[string]*StepResult{}
+	total := float64(len(steps))
+	overall := 0.0
+	for i, step := range steps {
+		result := StepResult{ID: step.ID, Tape: step.Tape, Command: step.Command, ExitCode: -1}
+		reason := ""
+		if ctx.Err() != nil {
+			if record.Status == runner.StatusSuccess {
+				record.Status = runner.StatusCanceled
+			}
+			reason = "pipeline canceled"
+		} else {
+			reason = skipReason(step, deps[step.ID], byID, results, record.Status, req.DryRun)
+		}
+		if reason != "" {
+			result.Status = StatusSkipped
+			result.Error = reason
+			results[step.ID] = &result
+			record.Steps = append(record.Steps, result)
+			events <- Event{Type: EventStepFinished, Step: step.ID, Message: string(StatusSkipped), Overall: overall, Result: &result}
+			continue
+		}
+
+		events <- Event{Type: EventStepStarted, Step: step.ID, Overall: overall}
+		stepCtx, stepSpan := trace.Start(ctx, "step", trace.String("step", step.ID))
+		if step.Tape != "" {
+			stepSpan.Set(trace.String("tape", step.Tape))
+		}
+		for attempt := 1; ; attempt++ {
+			result = runStep(stepCtx, run, req, step, vars{pipeline: record, steps: results, dryRun: req.DryRun}, func(fraction float64) float64 {
+				overall = (float64(i) + fraction) / total
+				return overall
+			}, events)
+			result.Attempts = attempt
+			if result.Status == runner.StatusSuccess || result.Status == runner.StatusCanceled || attempt > step.Retries {
+				break
+			}
+			delay := step.RetryDelay(attempt)
+			events <- Event{Type: EventStepRetrying, Step: step.ID, Message: fmt.Sprintf("attempt %d of %d failed (%s); retrying in %s", attempt, step.Retries+1, result.Error, delay), Overall: overall, Result: &result}
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+			if ctx.Err() != nil {
+				result.Status = runner.StatusCanceled
+				break
+			}
+			overall = float64(i) / total
+		}
+
+		switch {
+		case result.Status == runner.StatusSuccess:
+		case result.Status == runner.StatusCanceled:
+			record.Status = runner.StatusCanceled
+		case step.ContinueOnError:
+			result.Ignored = true
+		case record.Status == runner.StatusSuccess:
+			record.Status = result.Status
+		}
+		stepSpan.Set(trace.String("status", string(result.Status)), trace.Int("attempts", result.Attempts))
+		if result.Status == runner.StatusFailed && !result.Ignored {
+			stepSpan.Fail(result.Error)
+		}
+		stepSpan.End()
+		results[step.ID] = &result
+		record.Steps = append(record.Steps, result)
+		overall = float64(i+1) / total
+		events <- Event{Type: EventStepFinished, Step: step.ID, Message: string(result.Status), Overall: overall, Result: &result}
+	}
+
+	record.DurationMS = time.Since(startedAt).Milliseconds()
+	recordErr := WriteRecord(RecordPath(req.Config.RunsDir, record.RunID), record)
+	events <- Event{Type: EventFinished, Message: string(record.Status), Overall: 1, Record: record, RecordErr: recordErr}
+}
+
+// skipReason explains why step must not run, or returns "" when its if
+// condition holds.
+func skipReason(step config.PipelineStep, deps []string, steps map[string]config.PipelineStep, results map[string]*StepResult, status runner.RunStatus, dryRun bool) string {
+	if step.If == config.StepIfAlways {
+		return ""
+	}
+	if status != runner.StatusSuccess {
+		return "pipeline " + string(status)
+	}
+	for _, dep := range deps {
+		r := results[dep]
+		switch step.If {
+		case config.StepIfExitZero:
+			if r.ExitCode != 0 {
+				return fmt.Sprintf("%s exited %d", dep, r.ExitCode)
+			}
+		case config.StepIfOutputExists:
+			if r.Output == "" {
+				return dep + " has no output"
+			}
+			// Dry runs plan outputs without writing them.
+			if _, err := os.Stat(r.Output); err != nil && !dryRun {
+				return dep + " output is missing: " + r.Output
+			}
+		default:
+			if r.Status != runner.StatusSuccess && !(r.Status == runner.StatusFailed && steps[dep].ContinueOnError) {
+				return dep + " " + string(r.Status)
+			}
+		}
+	}
+	return ""
+}
+
+// runStep makes one attempt at step, forwarding its logs and reporting its
+// progress through advance, which returns the pipeline's overall fraction.
+func runStep(ctx context.Context, run *runner.Runner, req Request, step config.PipelineStep, v vars, advance func(float64) float64, events chan<- Event) StepResult {
+	result := StepResult{ID: step.ID, Tape: step.Tape, Command: step.Command, ExitCode: -1}
+	overall := advance(0)
+	stepEvents, err := startStep(ctx, run, req, step, v)
+	if err != nil {
+		result.Status = runner.StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	for event := range stepEvents {
+		switch event.Type {
+		case runner.EventLog, runner.EventStalled:
+			if len(step.Command) > 0 {
+				if line, ok := strings.CutPrefix(event.Message, "[out] "); ok && strings.TrimSpace(line) != "" {
+					result.Output = strings.TrimSpace(line)
+				}
+			}
+			events <- Event{Type: EventLog, Step: step.ID, Message: event.Message, Overall: overall}
+		case runner.EventProgress:
+			if event.Progress != nil {
+				overall = advance(event.Progress.Overall)
+				events <- Event{Type: EventProgress, Step: step.ID, Overall: overall}
+			}
+		case runner.EventFinished:
+			result.ExitCode = event.ExitCode
+			if event.Record != nil {
+				result.RunID = event.Record.RunID
+				result.Status = event.Record.Status
+				result.OutputPaths = event.Record.OutputPaths
+				result.DurationMS = event.Record.DurationMS
+			}
+			if event.ExitCode != 0 {
+				result.Error = event.Message
+			}
+		}
+	}
+	if result.Status == "" {
+		result.Status = runner.StatusFailed
+	}
+	if step.Tape != "" && len(result.OutputPaths) > 0 {
+		result.Output = result.OutputPaths[0]
+	}
+	return result
+}
+
+func startStep(ctx context.Context, run *runner.Runner, req Request, step config.PipelineStep, v vars) (<-chan runner.Event, error) {
+	env := make(map[string]string, len(step.Env))
+	for k, val := range step.Env {
+		expanded, err := v.expand(val)
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %w", k, err)
+		}
+		env[k] = expanded
+	}
+
+	if len(step.Command) > 0 {
+		command, err := v.expandAll(step.Command)
+		if err != nil {
+			return nil, err
+		}
+		return run.StartCommand(ctx, runner.CommandRequest{
+			Config:  req.Config,
+			Name:    req.Pipeline.ID + "-" + step.ID,
+			Command: command,
+			Env:     env,
+			DryRun:  req.DryRun,
+			Trigger: req.Trigger,
+		})
+	}
+
+	tape, ok := findTape(req.Config, step.Tape)
+	if !ok {
+		return nil, fmt.Errorf("unknown tape %q", step.Tape)
+	}
+	if tape.Disabled {
+		return nil, fmt.Errorf("tape %q is disabled", step.Tape)
+	}
+	args, err := v.expandAll(step.Args)
+	if err != nil {
+		return nil, err
+	}
+	action := runner.ActionPrimary
+	switch step.Action {
+	case string(runner.ActionPreview), string(runner.ActionVerify):
+		// Verify renders its frames with the preview args.
+		action = runner.Action(step.Action)
+		tape.Preview.Args = append(append([]string(nil), tape.Preview.Args...), args...)
+	default:
+		tape.PrimaryArgs = append(append([]string(nil), tape.PrimaryArgs...), args...)
+	}
+
+	cfg := req.Config
+	if len(env) > 0 {
+		copied := *req.Config
+		copied.Env = make(map[string]string, len(req.Config.Env)+len(env))
+		for k, val := range req.Config.Env {
+			copied.Env[k] = val
+		}
+		for k, val := range env {
+			copied.Env[k] = val
+		}
+		cfg = &copied
+	}
+	return run.Start(ctx, runner.Request{Config: cfg, Tape: tape, Action: action, DryRun: req.DryRun, Trigger: req.Trigger})
+}
+
+// vars resolves {{pipeline.*}} and {{steps.<id>.*}} references against the
+// steps that have already finished.
+type vars struct {
+	pipeline *Record
+	steps    map[string]*StepResult
+	dryRun   bool
+}
+
+func (v vars) expandAll(in []string) ([]string, error) {
+	out := make([]string, len(in))
+	for i, s := range in {
+		expanded, err := v.expand(s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = expanded
+	}
+	return out, nil
+}
+
+func (v vars) expand(s string) (string, error) {
+	var firstErr error
+	out := varPattern.ReplaceAllStringFunc(s, func(m string) string {
+		value, err := v.lookup(varPattern.FindStringSubmatch(m))
+		if err != nil {
+			// Dry runs never produce command output, so the reference is
+			// kept visible rather than failing the plan.
+			if firstErr == nil && !v.dryRun {
+				firstErr = err
+			}
+			return m
+		}
+		return value
+	})
+	return out, firstErr
+}
+
+func (v vars) lookup(m []string) (string, error) {
+	if m[1] != "" {
+		switch m[1] {
+		case "id":
+			return v.pipeline.PipelineID, nil
+		case "run_id":
+			return v.pipeline.RunID, nil
+		default:
+			return "", fmt.Errorf("unknown variable pipeline.%s", m[1])
+		}
+	}
+
+	step, field := m[2], m[3]
+	result, ok := v.steps[step]
+	if !ok {
+		return "", fmt.Errorf("step %q has not run", step)
+	}
+	var value string
+	switch field {
+	case "output":
+		value = result.Output
+	case "output_dir":
+		if len(result.OutputPaths) > 0 {
+			value = filepath.Dir(result.OutputPaths[0])
+		}
+	case "run_id":
+		value = result.RunID
+	case "exit_code":
+		value = strconv.Itoa(result.ExitCode)
+	default:
+		return "", fmt.Errorf("unknown variable steps.%s.%s", step, field)
+	}
+	if value == "" {
+		return "", fmt.Errorf("steps.%s.%s is empty", step, field)
+	}
+	return value, nil
+}
+
+func findTape(cfg *config.Config, id string) (config.Tape, bool) {
+	for _, tape := range cfg.Tapes {
+		if tape.ID == id {
+			return tape, true
+		}
+	}
+	return config.Tape{}, false
+}
@@ -0,0 +1,215 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestStartChainsStepOutputs(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	p := config.Pipeline{ID: "promo", Steps: []config.PipelineStep{
+		{ID: "render", Tape: "alpha"},
+		{ID: "export", Command: []string{"sh", "-c", "echo exporting {{steps.render.output}}; echo {{steps.render.output_dir}}/promo.gif"}},
+		{ID: "upload", Command: []string{"sh", "-c", `echo "$DEST {{steps.export.output}} {{pipeline.id}}"`}, Env: map[string]string{"DEST": "s3"}},
+	}}
+
+	events, err := Start(context.Background(), runner.New(nil), Request{Config: cfg, Pipeline: p})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	var logs []string
+	var record *Record
+	last := 0.0
+	for event := range events {
+		if event.Overall < last {
+			t.Fatalf("progress went backwards: %v -> %v", last, event.Overall)
+		}
+		last = event.Overall
+		switch event.Type {
+		case EventLog:
+			logs = append(logs, event.Step+": "+event.Message)
+		case EventFinished:
+			record = event.Record
+			if event.RecordErr != nil {
+				t.Fatalf("write record: %v", event.RecordErr)
+			}
+		}
+	}
+
+	if record == nil || record.Status != runner.StatusSuccess || len(record.Steps) != 3 {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	gif := filepath.Join(filepath.Dir(record.Steps[0].Output), "promo.gif")
+	if got := record.Steps[1].Output; got != gif {
+		t.Fatalf("export output: expected %q got %q", gif, got)
+	}
+	if !containsLine(logs, "upload: [out] s3 "+gif+" promo") {
+		t.Fatalf("upload did not see earlier outputs: %v", logs)
+	}
+	if _, err := os.Stat(RecordPath(cfg.RunsDir, record.RunID)); err != nil {
+		t.Fatalf("pipeline record not written: %v", err)
+	}
+}
+
+func TestStartSkipsStepsAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	p := config.Pipeline{ID: "broken", Steps: []config.PipelineStep{
+		{ID: "fail", Command: []string{"sh", "-c", "exit 3"}},
+		{ID: "after", Command: []string{"sh", "-c", "echo unreachable"}},
+	}}
+
+	events, err := Start(context.Background(), runner.New(nil), Request{Config: cfg, Pipeline: p})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	var record *Record
+	for event := range events {
+		if event.Type == EventFinished {
+			record = event.Record
+		}
+	}
+
+	if record.Status != runner.StatusFailed {
+		t.Fatalf("expected failed pipeline, got %s", record.Status)
+	}
+	if record.Steps[0].ExitCode != 3 || record.Steps[1].Status != StatusSkipped {
+		t.Fatalf("unexpected steps: %+v", record.Steps)
+	}
+}
+
+func TestStartAppliesFailurePolicies(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	counter := filepath.Join(t.TempDir(), "attempts")
+	p := config.Pipeline{ID: "policies", Steps: []config.PipelineStep{
+		{ID: "flaky", Command: []string{"sh", "-c", "n=$(cat " + counter + " 2>/dev/null || echo 0); n=$((n+1)); echo $n > " + counter + "; [ $n -ge 2 ]"}, Retries: 2},
+		{ID: "lint", Command: []string{"sh", "-c", "exit 1"}, ContinueOnError: true, Needs: []string{"flaky"}},
+		{ID: "after-lint", Command: []string{"echo", "ran"}, Needs: []string{"lint"}},
+		{ID: "strict", Command: []string{"echo", "ran"}, Needs: []string{"lint"}, If: config.StepIfExitZero},
+		{ID: "missing", Command: []string{"echo", "ran"}, Needs: []string{"after-lint"}, If: config.StepIfOutputExists},
+		{ID: "fail", Command: []string{"sh", "-c", "exit 2"}, Needs: []string{"flaky"}},
+		{ID: "next", Command: []string{"echo", "ran"}, Needs: []string{"flaky"}},
+		{ID: "cleanup", Command: []string{"echo", "ran"}, Needs: []string{"fail"}, If: config.StepIfAlways},
+	}}
+
+	events, err := Start(context.Background(), runner.New(nil), Request{Config: cfg, Pipeline: p})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	retries := 0
+	var record *Record
+	for event := range events {
+		switch event.Type {
+		case EventStepRetrying:
+			retries++
+		case EventFinished:
+			record = event.Record
+		}
+	}
+
+	want := map[string]runner.RunStatus{
+		"flaky":      runner.StatusSuccess,
+		"lint":       runner.StatusFailed,
+		"after-lint": runner.StatusSuccess,
+		"strict":     StatusSkipped,
+		"missing":    StatusSkipped,
+		"fail":       runner.StatusFailed,
+		"next":       StatusSkipped,
+		"cleanup":    runner.StatusSuccess,
+	}
+	for _, step := range record.Steps {
+		if step.Status != want[step.ID] {
+			t.Fatalf("step %s: expected %s got %s (%s)", step.ID, want[step.ID], step.Status, step.Error)
+		}
+	}
+	if retries != 1 || record.Steps[0].Attempts != 2 {
+		t.Fatalf("expected one retry, got %d events and %d attempts", retries, record.Steps[0].Attempts)
+	}
+	if !record.Steps[1].Ignored || record.Status != runner.StatusFailed {
+		t.Fatalf("unexpected lint result %+v / pipeline %s", record.Steps[1], record.Status)
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	t.Parallel()
+
+	v := vars{
+		pipeline: &Record{PipelineID: "promo", RunID: "r1"},
+		steps: map[string]*StepResult{
+			"render": {RunID: "r0", Output: "/out/a.mp4", OutputPaths: []string{"/out/a.mp4"}},
+			"silent": {},
+		},
+	}
+	got, err := v.expand("{{ pipeline.run_id }}:{{steps.render.run_id}}:{{steps.render.exit_code}}:{{steps.render.output_dir}}")
+	if err != nil || got != "r1:r0:0:/out" {
+		t.Fatalf("unexpected expansion %q err=%v", got, err)
+	}
+	if _, err := v.expand("{{steps.silent.output}}"); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Fatalf("expected empty output error, got %v", err)
+	}
+	if _, err := v.expand("{{steps.render.size}}"); err == nil {
+		t.Fatalf("expected unknown variable error")
+	}
+
+	v.dryRun = true
+	if got, err := v.expand("{{steps.silent.output}}"); err != nil || got != "{{steps.silent.output}}" {
+		t.Fatalf("dry run should keep the reference, got %q err=%v", got, err)
+	}
+}
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	tmp := t.TempDir()
+	vcr := filepath.Join(tmp, "vcr")
+	if err := os.WriteFile(vcr, []byte("#!/bin/sh\necho rendered\n"), 0o755); err != nil {
+		t.Fatalf("write fake vcr: %v", err)
+	}
+	cfg := &config.Config{
+		VCRBinary:   vcr,
+		ProjectRoot: filepath.Join(tmp, "project"),
+		RunsDir:     filepath.Join(tmp, "runs"),
+		Tapes: []config.Tape{
+			{ID: "alpha", Name: "Alpha", Manifest: "./manifests/alpha.yaml", Mode: config.ModeVideo},
+		},
+	}
+	if err := os.MkdirAll(cfg.ProjectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	if err := config.ApplyDefaults(cfg, filepath.Join(tmp, "config.yaml"), cfg.ProjectRoot); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	return cfg
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package probe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+)
+
+const CacheFileName = "probe_cache.json"
+
+var ErrNotInstalled = errors.New("ffprobe not installed")
+
+// Info is the subset of ffprobe output worth checking on a deliverable.
+type Info struct {
+	Format    string  `json:"format,omitempty"`
+	Codec     string  `json:"codec,omitempty"`
+	Width     int     `json:"width,omitempty"`
+	Height    int     `json:"height,omitempty"`
+	FrameRate float64 `json:"frame_rate,omitempty"`
+	Duration  float64 `json:"duration_seconds,omitempty"`
+	BitRate   int64   `json:"bit_rate,omitempty"`
+	PixFmt    string  `json:"pix_fmt,omitempty"`
+	HasAlpha  bool    `json:"has_alpha"`
+}
+
+func (i Info) Summary() string {
+	parts := []string{i.Codec}
+	if i.Width > 0 && i.Height > 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", i.Width, i.Height))
+	}
+	if i.FrameRate > 0 {
+		parts = append(parts, strconv.FormatFloat(i.FrameRate, 'f', -1, 64)+"fps")
+	}
+	if i.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("%.2fs", i.Duration))
+	}
+	if i.BitRate > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f Mb/s", float64(i.BitRate)/1e6))
+	}
+	if i.HasAlpha {
+		parts = append(parts, "alpha ("+i.PixFmt+")")
+	} else {
+		parts = append(parts, "no alpha ("+i.PixFmt+")")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Run invokes ffprobe on path. An empty binary means "ffprobe" on PATH.
+func Run(ctx context.Context, binary, path string) (Info, error) {
+	if binary == "" {
+		binary = "ffprobe"
+	}
+	resolved, err := exec.LookPath(binary)
+	if err != nil {
+		return Info{}, ErrNotInstalled
+	}
+	out, err := exec.CommandContext(ctx, resolved, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", path).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return Info{}, fmt.Errorf("ffprobe %s: %s", filepath.Base(path), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return Info{}, fmt.Errorf("ffprobe %s: %w", filepath.Base(path), err)
+	}
+	return Parse(out)
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string            `json:"codec_type"`
+		CodecName    string            `json:"codec_name"`
+		Width        int               `json:"width"`
+		Height       int               `json:"height"`
+		PixFmt       string            `json:"pix_fmt"`
+		AvgFrameRate string            `json:"avg_frame_rate"`
+		RFrameRate   string            `json:"r_frame_rate"`
+		Duration     string            `json:"duration"`
+		BitRate      string            `json:"bit_rate"`
+		Tags         map[string]string `json:"tags"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// Parse reads `ffprobe -print_format json -show_format -show_streams` output
+// and describes the first video stream.
+func Parse(data []byte) (Info, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return Info{}, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+	for _, s := range out.Streams {
+		if s.CodecType != "video" {
+			continue
+		}
+		info := Info{
+			Format: out.Format.FormatName,
+			Codec:  s.CodecName,
+			Width:  s.Width,
+			Height: s.Height,
+			PixFmt: s.PixFmt,
+		}
+		info.FrameRate = parseRate(s.AvgFrameRate)
+		if info.FrameRate == 0 {
+			info.FrameRate = parseRate(s.RFrameRate)
+		}
+		info.Duration = parseFloat(out.Format.Duration)
+		if info.Duration == 0 {
+			info.Duration = parseFloat(s.Duration)
+		}
+		info.BitRate = int64(parseFloat(out.Format.BitRate))
+		if info.BitRate == 0 {
+			info.BitRate = int64(parseFloat(s.BitRate))
+		}
+		// VP8/VP9 in WebM keep alpha in a side channel flagged by a tag.
+		info.HasAlpha = PixFmtHasAlpha(s.PixFmt) || s.Tags["alpha_mode"] == "1" || s.Tags["ALPHA_MODE"] == "1"
+		return info, nil
+	}
+	return Info{}, errors.New("no video stream found")
+}
+
+// PixFmtHasAlpha reports whether an ffmpeg pixel format carries an alpha
+// component (rgba, yuva444p10le, gbrap, ya8, ...).
+func PixFmtHasAlpha(pixFmt string) bool {
+	f := strings.ToLower(pixFmt)
+	if strings.HasPrefix(f, "yuva") || strings.HasPrefix(f, "gbrap") || strings.HasPrefix(f, "ya") {
+		return true
+	}
+	for _, s := range []string{"rgba", "bgra", "argb", "abgr"} {
+		if strings.Contains(f, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRate(v string) float64 {
+	num, den, ok := strings.Cut(v, "/")
+	if !ok {
+		return parseFloat(v)
+	}
+	n, d := parseFloat(num), parseFloat(den)
+	if d == 0 {
+		return 0
+	}
+	return float64(int(n/d*1000+0.5)) / 1000
+}
+
+func parseFloat(v string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open output: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash output: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func CachePath(runsDir string) string {
+	return filepath.Join(runsDir, CacheFileName)
+}
+
+// Cache memoizes probe results by file content hash so re-selecting an
+// output, or an identical re-render, does not spawn ffprobe again. An empty
+// path keeps the cache in memory only.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Info
+}
+
+func OpenCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: map[string]Info{}}
+	if path == "" {
+		return c, nil
+	}
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("read probe cache: %w", err)
+	}
+	if err := json.Unmarshal(buf, &c.entries); err != nil {
+		return c, fmt.Errorf("parse probe cache: %w", err)
+	}
+	return c, nil
+}
+
+func (c *Cache) Lookup(hash string) (Info, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, ok := c.entries[hash]
+	return info, ok
+}
+
+func (c *Cache) Store(hash string, info Info) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[hash] = info
+	if c.path == "" {
+		return nil
+	}
+	buf, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal probe cache: %w", err)
+	}
+	if err := atomicfile.Write(c.path, append(buf, '\n')); err != nil {
+		return fmt.Errorf("write probe cache: %w", err)
+	}
+	return nil
+}
+
+// Probe returns cached metadata for path, running ffprobe on a miss.
+func (c *Cache) Probe(ctx context.Context, binary, path string) (Info, error) {
+	hash, err := HashFile(path)
+	if err != nil {
+		return Info{}, err
+	}
+	if info, ok := c.Lookup(hash); ok {
+		return info, nil
+	}
+	info, err := Run(ctx, binary, path)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := c.Store(hash, info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
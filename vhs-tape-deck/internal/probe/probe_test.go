@@ -0,0 +1,103 @@
+package probe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const proresJSON = `{
+  "streams": [
+    {"codec_type": "audio", "codec_name": "aac"},
+    {"codec_type": "video", "codec_name": "prores", "width": 1920, "height": 1080,
+     "pix_fmt": "yuva444p12le", "avg_frame_rate": "30000/1001", "r_frame_rate": "30000/1001"}
+  ],
+  "format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "10.010000", "bit_rate": "120300000"}
+}`
+
+func TestParseVideoStream(t *testing.T) {
+	t.Parallel()
+
+	info, err := Parse([]byte(proresJSON))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if info.Codec != "prores" || info.Width != 1920 || info.Height != 1080 {
+		t.Fatalf("unexpected stream info: %+v", info)
+	}
+	if info.FrameRate != 29.97 || info.Duration != 10.01 || info.BitRate != 120300000 {
+		t.Fatalf("unexpected timing info: %+v", info)
+	}
+	if !info.HasAlpha {
+		t.Fatalf("expected alpha for %s", info.PixFmt)
+	}
+	want := "prores | 1920x1080 | 29.97fps | 10.01s | 120.3 Mb/s | alpha (yuva444p12le)"
+	if got := info.Summary(); got != want {
+		t.Fatalf("expected %q got %q", want, got)
+	}
+}
+
+func TestParseWebMAlphaTag(t *testing.T) {
+	t.Parallel()
+
+	info, err := Parse([]byte(`{"streams":[{"codec_type":"video","codec_name":"vp9","pix_fmt":"yuv420p","tags":{"alpha_mode":"1"}}],"format":{}}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !info.HasAlpha {
+		t.Fatal("expected alpha_mode tag to count as alpha")
+	}
+
+	if _, err := Parse([]byte(`{"streams":[{"codec_type":"audio"}],"format":{}}`)); err == nil {
+		t.Fatal("expected error for audio-only file")
+	}
+}
+
+func TestPixFmtHasAlpha(t *testing.T) {
+	t.Parallel()
+
+	for fmt, want := range map[string]bool{
+		"rgba": true, "yuva420p": true, "gbrap10le": true, "ya8": true, "rgba64be": true,
+		"yuv420p": false, "yuv444p10le": false, "rgb24": false, "gray": false,
+	} {
+		if got := PixFmtHasAlpha(fmt); got != want {
+			t.Fatalf("PixFmtHasAlpha(%q) = %v", fmt, got)
+		}
+	}
+}
+
+func TestCacheHitSkipsFFprobe(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "out.mov")
+	if err := os.WriteFile(output, []byte("not really a movie"), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+	hash, err := HashFile(output)
+	if err != nil {
+		t.Fatalf("HashFile: %v", err)
+	}
+
+	cachePath := CachePath(dir)
+	c, err := OpenCache(cachePath)
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	if err := c.Store(hash, Info{Codec: "prores", HasAlpha: true}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	reopened, err := OpenCache(cachePath)
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	info, err := reopened.Probe(context.Background(), filepath.Join(dir, "missing-ffprobe"), output)
+	if err != nil {
+		t.Fatalf("Probe should be served from cache: %v", err)
+	}
+	if info.Codec != "prores" || !info.HasAlpha {
+		t.Fatalf("unexpected cached info: %+v", info)
+	}
+}
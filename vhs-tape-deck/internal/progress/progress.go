@@ -0,0 +1,233 @@
+package progress
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Phase string
+
+const (
+	// PhaseContext is a skill gathering context (files, references) before
+	// it prompts a model.
+	PhaseContext  Phase = "context"
+	PhaseLLM      Phase = "llm"
+	PhaseValidate Phase = "validate"
+	PhaseRender   Phase = "render"
+	PhaseMux      Phase = "mux"
+	// PhaseBuild is the optional vcr rebuild before a render; its length is
+	// unknown, so it only ever reports a fraction of zero.
+	PhaseBuild Phase = "build"
+	// PhaseUpload copies outputs elsewhere after the render; Done counts
+	// uploaded outputs.
+	PhaseUpload Phase = "upload"
+)
+
+type Weight struct {
+	Phase  Phase
+	Weight float64
+}
+
+// RenderWeights splits a plain vcr render into its observable phases.
+var RenderWeights = []Weight{
+	{Phase: PhaseValidate, Weight: 0.05},
+	{Phase: PhaseRender, Weight: 0.85},
+	{Phase: PhaseMux, Weight: 0.10},
+}
+
+// AgentWeights covers skills that generate a manifest with an LLM first, so
+// the bar moves through model inference instead of sitting at zero.
+var AgentWeights = []Weight{
+	{Phase: PhaseContext, Weight: 0.05},
+	{Phase: PhaseLLM, Weight: 0.30},
+	{Phase: PhaseValidate, Weight: 0.05},
+	{Phase: PhaseRender, Weight: 0.50},
+	{Phase: PhaseMux, Weight: 0.10},
+}
+
+// Sample is one progress observation parsed from a log line. Fraction is in
+// [0,1], or negative when only Done is known.
+type Sample struct {
+	Phase    Phase
+	Fraction float64
+	Done     int
+	Total    int
+	Status   string
+}
+
+type Snapshot struct {
+	Phase         Phase   `json:"phase"`
+	PhaseFraction float64 `json:"phase_fraction"`
+	Overall       float64 `json:"overall"`
+	Done          int     `json:"done,omitempty"`
+	Total         int     `json:"total,omitempty"`
+	Status        string  `json:"status,omitempty"`
+}
+
+var (
+	renderedFramePattern = regexp.MustCompile(`rendered frame (\d+)/(\d+)`)
+	buildPattern         = regexp.MustCompile(`\[VCR\] Build: .*?(\d+) frames`)
+	ffmpegFramePattern   = regexp.MustCompile(`^frame=\s*(\d+)`)
+)
+
+type skillMessage struct {
+	Type    string   `json:"type"`
+	Percent *float64 `json:"percent"`
+	Phase   string   `json:"phase"`
+	Status  string   `json:"status"`
+	Done    int      `json:"done"`
+	Total   int      `json:"total"`
+}
+
+// ParseLine recognizes vcr render output, ffmpeg progress lines and skill
+// protocol progress messages.
+func ParseLine(line string) (Sample, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Sample{}, false
+	}
+
+	if strings.HasPrefix(line, "{") {
+		var msg skillMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.Type != "progress" || msg.Percent == nil {
+			return Sample{}, false
+		}
+		phase := Phase(strings.ToLower(strings.TrimSpace(msg.Phase)))
+		if phase == "" {
+			phase = PhaseRender
+		}
+		return Sample{Phase: phase, Fraction: clamp(*msg.Percent), Done: msg.Done, Total: msg.Total, Status: msg.Status}, true
+	}
+
+	if m := renderedFramePattern.FindStringSubmatch(line); m != nil {
+		done, _ := strconv.Atoi(m[1])
+		total, _ := strconv.Atoi(m[2])
+		return Sample{Phase: PhaseRender, Fraction: fraction(done, total), Done: done, Total: total}, true
+	}
+	if m := buildPattern.FindStringSubmatch(line); m != nil {
+		total, _ := strconv.Atoi(m[1])
+		return Sample{Phase: PhaseRender, Fraction: 0, Total: total, Status: "rendering"}, true
+	}
+	if m := ffmpegFramePattern.FindStringSubmatch(line); m != nil {
+		done, _ := strconv.Atoi(m[1])
+		return Sample{Phase: PhaseMux, Fraction: -1, Done: done, Status: "encoding"}, true
+	}
+	if strings.HasPrefix(line, "Wrote ") {
+		return Sample{Phase: PhaseMux, Fraction: 1, Status: "written"}, true
+	}
+	return Sample{}, false
+}
+
+// Tracker folds phase samples into a single monotonic overall fraction.
+// Reaching a later phase marks every earlier phase complete.
+type Tracker struct {
+	mu         sync.Mutex
+	weights    []Weight
+	alternates [][]Weight
+	fractions  []float64
+	current    int
+	total      int
+	overall    float64
+}
+
+// NewTracker weighs phases with weights. Until the bar first moves, a
+// sample for a phase weights lacks switches to the first alternate that
+// has it, e.g. AgentWeights when a command tape turns out to run a skill.
+func NewTracker(weights []Weight, alternates ...[]Weight) *Tracker {
+	if len(weights) == 0 {
+		weights = RenderWeights
+	}
+	return &Tracker{weights: weights, alternates: alternates, fractions: make([]float64, len(weights))}
+}
+
+func (t *Tracker) Observe(s Sample) Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.indexOf(s.Phase)
+	if idx < 0 && t.overall == 0 {
+		for _, alt := range t.alternates {
+			if i := phaseIndex(alt, s.Phase); i >= 0 {
+				t.weights, t.alternates = alt, nil
+				t.fractions = make([]float64, len(alt))
+				t.current, idx = 0, i
+				break
+			}
+		}
+	}
+	if idx < 0 {
+		idx = t.current
+	}
+	if idx > t.current {
+		for i := t.current; i < idx; i++ {
+			t.fractions[i] = 1
+		}
+		t.current = idx
+	}
+
+	if s.Total > 0 {
+		t.total = s.Total
+	}
+	frac := s.Fraction
+	if frac < 0 && t.total > 0 {
+		frac = fraction(s.Done, t.total)
+	}
+	if frac > t.fractions[idx] {
+		t.fractions[idx] = frac
+	}
+
+	var sum, weightSum float64
+	for i, w := range t.weights {
+		sum += w.Weight * t.fractions[i]
+		weightSum += w.Weight
+	}
+	if weightSum > 0 && sum/weightSum > t.overall {
+		t.overall = sum / weightSum
+	}
+
+	total := s.Total
+	if total == 0 {
+		total = t.total
+	}
+	return Snapshot{
+		Phase:         t.weights[idx].Phase,
+		PhaseFraction: t.fractions[idx],
+		Overall:       t.overall,
+		Done:          s.Done,
+		Total:         total,
+		Status:        s.Status,
+	}
+}
+
+func (t *Tracker) indexOf(phase Phase) int {
+	return phaseIndex(t.weights, phase)
+}
+
+func phaseIndex(weights []Weight, phase Phase) int {
+	for i, w := range weights {
+		if w.Phase == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+func fraction(done, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return clamp(float64(done) / float64(total))
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package progress
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		line  string
+		phase Phase
+		done  int
+		total int
+	}{
+		{line: "rendered frame 31/120", phase: PhaseRender, done: 31, total: 120},
+		{line: "[VCR] Build: 1920x1080, 30 fps, 90 frames", phase: PhaseRender, total: 90},
+		{line: "frame=  240 fps= 60 q=-0.0 size=  1024kB", phase: PhaseMux, done: 240},
+		{line: `{"type":"progress","percent":0.5,"phase":"llm","status":"Thinking"}`, phase: PhaseLLM},
+	}
+	for _, tc := range cases {
+		s, ok := ParseLine(tc.line)
+		if !ok {
+			t.Fatalf("expected %q to parse", tc.line)
+		}
+		if s.Phase != tc.phase || s.Done != tc.done || s.Total != tc.total {
+			t.Fatalf("%q: unexpected sample %+v", tc.line, s)
+		}
+	}
+
+	if _, ok := ParseLine("[VCR] Backend: wgpu (metal)"); ok {
+		t.Fatalf("did not expect backend line to parse as progress")
+	}
+	if _, ok := ParseLine(`{"type":"status","status":"Reading DB"}`); ok {
+		t.Fatalf("did not expect status message to parse as progress")
+	}
+}
+
+func TestTrackerWeightsPhasesMonotonically(t *testing.T) {
+	t.Parallel()
+
+	tr := NewTracker(AgentWeights)
+
+	snap := tr.Observe(Sample{Phase: PhaseLLM, Fraction: 0.5})
+	assertNear(t, snap.Overall, 0.20)
+
+	snap = tr.Observe(Sample{Phase: PhaseRender, Fraction: 0, Total: 100})
+	assertNear(t, snap.Overall, 0.40)
+
+	snap = tr.Observe(Sample{Phase: PhaseRender, Fraction: 0.5, Done: 50, Total: 100})
+	assertNear(t, snap.Overall, 0.65)
+
+	// A stale lower sample never moves the bar backwards.
+	snap = tr.Observe(Sample{Phase: PhaseRender, Fraction: 0.2})
+	assertNear(t, snap.Overall, 0.65)
+
+	snap = tr.Observe(Sample{Phase: PhaseMux, Fraction: -1, Done: 50})
+	assertNear(t, snap.Overall, 0.95)
+	if snap.Total != 100 {
+		t.Fatalf("expected mux to inherit render total, got %d", snap.Total)
+	}
+
+	snap = tr.Observe(Sample{Phase: PhaseMux, Fraction: 1})
+	assertNear(t, snap.Overall, 1)
+}
+
+func TestTrackerSwitchesToAlternateWeights(t *testing.T) {
+	t.Parallel()
+
+	tr := NewTracker(RenderWeights, AgentWeights)
+	snap := tr.Observe(Sample{Phase: PhaseContext, Fraction: 1})
+	assertNear(t, snap.Overall, 0.05)
+	if snap.Phase != PhaseContext {
+		t.Fatalf("expected context phase, got %s", snap.Phase)
+	}
+	snap = tr.Observe(Sample{Phase: PhaseLLM, Fraction: 1})
+	assertNear(t, snap.Overall, 0.35)
+
+	// Once the bar has moved, unknown phases stay in the current one.
+	tr = NewTracker(RenderWeights, AgentWeights)
+	tr.Observe(Sample{Phase: PhaseRender, Fraction: 0.5})
+	snap = tr.Observe(Sample{Phase: PhaseLLM, Fraction: 1})
+	if snap.Phase != PhaseRender {
+		t.Fatalf("expected render phase to stick, got %s", snap.Phase)
+	}
+}
+
+func assertNear(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("expected %.4f got %.4f", want, got)
+	}
+}
@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+const FileName = "queue.json"
+
+type Job struct {
+	TapeID     string        `json:"tape_id"`
+	Action     runner.Action `json:"action"`
+	DryRun     bool          `json:"dry_run,omitempty"`
+	EnqueuedAt time.Time     `json:"enqueued_at"`
+}
+
+// InFlight is the job that was running when the queue was last saved, with
+// enough of its record to mark it aborted if the deck never saw it finish.
+type InFlight struct {
+	Job
+	RecordPath string            `json:"record_path"`
+	Record     *runner.RunRecord `json:"record,omitempty"`
+}
+
+type State struct {
+	InFlight *InFlight `json:"in_flight,omitempty"`
+	Pending  []Job     `json:"pending,omitempty"`
+}
+
+func (st *State) Empty() bool {
+	return st == nil || (st.InFlight == nil && len(st.Pending) == 0)
+}
+
+func Path(runsDir string) string {
+	return filepath.Join(runsDir, FileName)
+}
+
+func Load(path string) (*State, error) {
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read queue: %w", err)
+	}
+	var st State
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return nil, fmt.Errorf("parse queue: %w", err)
+	}
+	return &st, nil
+}
+
+// Save persists st, removing the file entirely once nothing is pending.
+func Save(path string, st *State) error {
+	if st.Empty() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear queue: %w", err)
+		}
+		return nil
+	}
+	buf, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal queue: %w", err)
+	}
+	if err := atomicfile.Write(path, append(buf, '\n')); err != nil {
+		return fmt.Errorf("write queue: %w", err)
+	}
+	return nil
+}
+
+// Recover marks an interrupted in-flight run as aborted and returns the jobs
+// that can be resumed: the interrupted job first, then everything pending.
+// A run whose record already exists finished normally and is not resumed.
+func Recover(st *State) ([]Job, error) {
+	if st.Empty() {
+		return nil, nil
+	}
+
+	var jobs []Job
+	if st.InFlight != nil {
+		if _, err := os.Stat(st.InFlight.RecordPath); errors.Is(err, os.ErrNotExist) {
+			if err := Interrupt(st); err != nil {
+				return nil, err
+			}
+		}
+		st.InFlight = nil
+	}
+	jobs = append(jobs, st.Pending...)
+	st.Pending = nil
+	return jobs, nil
+}
+
+// Interrupt is used when the deck exits with a run still active: the run is
+// recorded as aborted and its job moves to the front of the pending list so
+// it is offered for resume on the next launch.
+func Interrupt(st *State) error {
+	if st == nil || st.InFlight == nil {
+		return nil
+	}
+	record := st.InFlight.Record
+	if record == nil {
+		record = &runner.RunRecord{TapeID: st.InFlight.TapeID, Action: st.InFlight.Action}
+	}
+	record.ExitCode = -1
+	record.Status = runner.StatusAborted
+	if err := runner.WriteRunRecord(st.InFlight.RecordPath, record); err != nil {
+		return fmt.Errorf("mark aborted run: %w", err)
+	}
+	st.Pending = append([]Job{st.InFlight.Job}, st.Pending...)
+	st.InFlight = nil
+	return nil
+}
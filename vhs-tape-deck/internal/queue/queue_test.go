@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestSaveLoadAndClear(t *testing.T) {
+	t.Parallel()
+
+	path := Path(t.TempDir())
+	st := &State{Pending: []Job{{TapeID: "alpha", Action: runner.ActionPrimary, EnqueuedAt: time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)}}}
+	if err := Save(path, st); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Pending) != 1 || loaded.Pending[0].TapeID != "alpha" {
+		t.Fatalf("unexpected queue: %+v", loaded)
+	}
+
+	if err := Save(path, &State{}); err != nil {
+		t.Fatalf("Save empty: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected empty queue to remove file, stat err=%v", err)
+	}
+}
+
+func TestRecoverMarksInterruptedRunAborted(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	recordPath := filepath.Join(tmp, "records", "run1.json")
+	st := &State{
+		InFlight: &InFlight{
+			Job:        Job{TapeID: "alpha", Action: runner.ActionPrimary},
+			RecordPath: recordPath,
+			Record:     &runner.RunRecord{RunID: "run1", TapeID: "alpha", Action: runner.ActionPrimary},
+		},
+		Pending: []Job{{TapeID: "beta", Action: runner.ActionPreview}},
+	}
+
+	jobs, err := Recover(st)
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].TapeID != "alpha" || jobs[1].TapeID != "beta" {
+		t.Fatalf("unexpected resumable jobs: %+v", jobs)
+	}
+	if !st.Empty() {
+		t.Fatalf("expected state to be drained: %+v", st)
+	}
+
+	buf, err := os.ReadFile(recordPath)
+	if err != nil {
+		t.Fatalf("read record: %v", err)
+	}
+	var record runner.RunRecord
+	if err := json.Unmarshal(buf, &record); err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+	if record.Status != runner.StatusAborted || record.RunID != "run1" {
+		t.Fatalf("unexpected aborted record: %+v", record)
+	}
+}
+
+func TestRecoverSkipsFinishedInFlight(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	recordPath := filepath.Join(tmp, "run1.json")
+	if err := runner.WriteRunRecord(recordPath, &runner.RunRecord{RunID: "run1", Status: runner.StatusSuccess}); err != nil {
+		t.Fatalf("WriteRunRecord: %v", err)
+	}
+
+	jobs, err := Recover(&State{InFlight: &InFlight{Job: Job{TapeID: "alpha"}, RecordPath: recordPath}})
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("expected finished run not to be resumed: %+v", jobs)
+	}
+}
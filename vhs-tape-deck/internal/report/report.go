@@ -0,0 +1,271 @@
+// Package report turns run records into review pages: a text summary for
+// the terminal and a self-contained HTML gallery for clients.
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+const (
+	thumbWidth = 480
+	// maxEmbed caps artifacts embedded as-is, such as share GIFs.
+	maxEmbed = 4 << 20
+	// maxManifest caps the manifest text shown per run.
+	maxManifest = 64 << 10
+)
+
+// Options selects the runs in a report. Zero times leave that end open;
+// Until is exclusive.
+type Options struct {
+	Since  time.Time
+	Until  time.Time
+	TapeID string
+}
+
+// Entry is one run in a report.
+type Entry struct {
+	Record   runner.RunRecord
+	Name     string
+	Notes    string
+	Status   runner.RunStatus
+	Manifest string
+	Media    string
+	Images   []Image
+}
+
+// Image is an embedded picture, as a data: URL.
+type Image struct {
+	Label string
+	Src   template.URL
+}
+
+type Report struct {
+	Title       string
+	GeneratedAt time.Time
+	Options     Options
+	Entries     []Entry
+}
+
+// Select returns the non-dry runs that match opts, newest first.
+func Select(records []runner.RunRecord, opts Options) []runner.RunRecord {
+	var out []runner.RunRecord
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if r.DryRun || (opts.TapeID != "" && r.TapeID != opts.TapeID) {
+			continue
+		}
+		if (!opts.Since.IsZero() && r.Timestamp.Before(opts.Since)) || (!opts.Until.IsZero() && !r.Timestamp.Before(opts.Until)) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// StatusOf is the record's status, derived from its exit code for records
+// written before statuses were recorded.
+func StatusOf(r runner.RunRecord) runner.RunStatus {
+	if r.Status != "" {
+		return r.Status
+	}
+	if r.ExitCode == 0 {
+		return runner.StatusSuccess
+	}
+	return runner.StatusFailed
+}
+
+// Build collects the entries for records, reading manifests and making
+// thumbnails with ffmpeg when it is installed. Missing files are skipped.
+func Build(ctx context.Context, cfg *config.Config, records []runner.RunRecord, opts Options) *Report {
+	rep := &Report{Title: "Render review: " + filepath.Base(cfg.ProjectRoot), GeneratedAt: time.Now(), Options: opts}
+	ffmpeg, _ := exec.LookPath("ffmpeg")
+	for _, r := range records {
+		e := Entry{Record: r, Name: r.TapeName, Status: StatusOf(r)}
+		for _, t := range cfg.Tapes {
+			if t.ID == r.TapeID {
+				e.Notes = t.Notes
+			}
+		}
+		if e.Name == "" {
+			e.Name = r.TapeID
+		}
+		if buf, err := os.ReadFile(r.ManifestPath); err == nil {
+			if len(buf) > maxManifest {
+				buf = append(buf[:maxManifest], "\n…"...)
+			}
+			e.Manifest = string(buf)
+		}
+		for _, path := range r.OutputPaths {
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			at := 0.0
+			if info, err := probe.Run(ctx, "", path); err == nil {
+				if e.Media == "" {
+					e.Media = info.Summary()
+				}
+				at = info.Duration / 2
+			}
+			if ffmpeg == "" {
+				continue
+			}
+			if png, err := Thumbnail(ctx, ffmpeg, path, at); err == nil {
+				e.Images = append(e.Images, Image{Label: filepath.Base(path), Src: dataURL("image/png", png)})
+			}
+		}
+		for _, path := range r.Artifacts {
+			mime := map[string]string{".gif": "image/gif", ".webp": "image/webp", ".png": "image/png"}[strings.ToLower(filepath.Ext(path))]
+			info, err := os.Stat(path)
+			if mime == "" || err != nil || info.Size() > maxEmbed {
+				continue
+			}
+			if buf, err := os.ReadFile(path); err == nil {
+				e.Images = append(e.Images, Image{Label: filepath.Base(path), Src: dataURL(mime, buf)})
+			}
+		}
+		rep.Entries = append(rep.Entries, e)
+	}
+	return rep
+}
+
+// Thumbnail renders the frame at seconds into path as a PNG scaled to
+// thumbWidth.
+func Thumbnail(ctx context.Context, ffmpeg, path string, at float64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if at > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", at))
+	}
+	args = append(args, "-i", path, "-frames:v", "1", "-vf", fmt.Sprintf("scale='min(%d,iw)':-2", thumbWidth), "-f", "image2pipe", "-c:v", "png", "-")
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpeg, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("thumbnail %s: %w: %s", filepath.Base(path), err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("thumbnail %s: no frame", filepath.Base(path))
+	}
+	return stdout.Bytes(), nil
+}
+
+func dataURL(mime string, data []byte) template.URL {
+	return template.URL("data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
+
+// WriteText prints one line per run.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTAPE\tACTION\tSTATUS\tDURATION\tOUTPUT")
+	for _, e := range r.Entries {
+		output := "-"
+		if len(e.Record.OutputPaths) > 0 {
+			output = e.Record.OutputPaths[0]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Record.Timestamp.Local().Format("2006-01-02 15:04"), e.Record.TapeID, e.Record.Action, e.Status, duration(e.Record.DurationMS), output)
+	}
+	return tw.Flush()
+}
+
+// WriteHTML writes the report as one HTML file with its images inlined, so
+// it can be mailed or dropped in a chat without a server.
+func (r *Report) WriteHTML(w io.Writer) error {
+	return page.Execute(w, r)
+}
+
+func duration(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": duration,
+	"when":     func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+	"day":      func(t time.Time) string { return t.Local().Format("2006-01-02") },
+	"join":     strings.Join,
+	"web": func(u string) bool {
+		return strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font: 14px/1.45 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #111; color: #ddd; }
+header { padding: 24px 32px; border-bottom: 1px solid #333; }
+h1 { margin: 0 0 4px; font-size: 22px; }
+.sub { color: #888; }
+main { display: grid; grid-template-columns: repeat(auto-fill, minmax(360px, 1fr)); gap: 20px; padding: 24px 32px; }
+article { background: #1b1b1b; border: 1px solid #2c2c2c; border-radius: 8px; overflow: hidden; }
+figure { margin: 0; background: repeating-conic-gradient(#222 0 25%, #2a2a2a 0 50%) 0 0 / 16px 16px; }
+figure img { display: block; width: 100%; }
+figcaption { font-size: 12px; color: #999; padding: 4px 12px; background: #181818; }
+.body { padding: 12px 16px; }
+h2 { margin: 0; font-size: 16px; }
+.badge { display: inline-block; font-size: 11px; padding: 1px 8px; border-radius: 9px; margin-left: 6px; vertical-align: 2px; }
+.success { background: #1f4d2b; color: #9fe0af; }
+.failed, .aborted { background: #5a1f1f; color: #f0a5a5; }
+.canceled { background: #4a4020; color: #e8d58e; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: 2px 12px; margin: 10px 0; font-size: 13px; }
+dt { color: #888; }
+dd { margin: 0; word-break: break-all; }
+a { color: #8ab4f8; }
+details { margin-top: 8px; }
+summary { cursor: pointer; color: #aaa; }
+pre { background: #0c0c0c; padding: 10px; overflow: auto; max-height: 360px; font-size: 12px; }
+.notes { color: #bbb; margin: 6px 0 0; }
+.empty { padding: 48px 32px; color: #888; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<div class="sub">{{len .Entries}} run(s){{with .Options.TapeID}} of {{.}}{{end}}{{if not .Options.Since.IsZero}} since {{day .Options.Since}}{{end}}{{if not .Options.Until.IsZero}} before {{day .Options.Until}}{{end}} · generated {{when .GeneratedAt}}</div>
+</header>
+{{if not .Entries}}<p class="empty">No runs in this range.</p>{{end}}
+<main>
+{{range .Entries}}<article>
+{{range .Images}}<figure><img src="{{.Src}}" alt="{{.Label}}" loading="lazy"><figcaption>{{.Label}}</figcaption></figure>
+{{end}}<div class="body">
+<h2>{{.Name}}<span class="badge {{.Status}}">{{.Status}}</span></h2>
+{{with .Notes}}<p class="notes">{{.}}</p>{{end}}
+<dl>
+<dt>Run</dt><dd>{{.Record.RunID}}</dd>
+<dt>Started</dt><dd>{{when .Record.Timestamp}}</dd>
+<dt>Action</dt><dd>{{.Record.Action}}{{with .Record.Trigger}} ({{.}}){{end}}</dd>
+<dt>Duration</dt><dd>{{duration .Record.DurationMS}}</dd>
+{{if ne .Record.ExitCode 0}}<dt>Exit code</dt><dd>{{.Record.ExitCode}}</dd>
+{{end}}{{with .Media}}<dt>Media</dt><dd>{{.}}</dd>
+{{end}}{{range .Record.OutputPaths}}<dt>Output</dt><dd>{{.}}</dd>
+{{end}}{{range .Record.Uploads}}{{with .URL}}<dt>Uploaded</dt><dd>{{if web .}}<a href="{{.}}">{{.}}</a>{{else}}{{.}}{{end}}</dd>
+{{end}}{{end}}{{range .Record.Golden}}<dt>Frame {{.Frame}}</dt><dd>{{if .Passed}}pass{{else}}fail{{end}} ({{printf "%.4f" .Score}}){{with .Error}} {{.}}{{end}}</dd>
+{{end}}</dl>
+<details><summary>Command</summary><pre>{{join .Record.Command " "}}</pre></details>
+{{with .Manifest}}<details><summary>Manifest (as of this report)</summary><pre>{{.}}</pre></details>{{end}}
+</div>
+</article>
+{{end}}</main>
+</body>
+</html>
+`))
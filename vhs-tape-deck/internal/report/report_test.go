@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestSelectFiltersByRangeAndTape(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	records := []runner.RunRecord{
+		{RunID: "a1", TapeID: "a", Timestamp: day(1)},
+		{RunID: "a2", TapeID: "a", Timestamp: day(2)},
+		{RunID: "b2", TapeID: "b", Timestamp: day(2)},
+		{RunID: "a2-dry", TapeID: "a", Timestamp: day(2), DryRun: true},
+		{RunID: "a3", TapeID: "a", Timestamp: day(3)},
+	}
+
+	got := Select(records, Options{Since: day(2), Until: day(3)})
+	if len(got) != 2 || got[0].RunID != "b2" || got[1].RunID != "a2" {
+		t.Fatalf("unexpected range selection: %+v", got)
+	}
+	got = Select(records, Options{TapeID: "a"})
+	if len(got) != 3 || got[0].RunID != "a3" {
+		t.Fatalf("expected a's runs newest first, got %+v", got)
+	}
+}
+
+func TestWriteHTMLIsSelfContained(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	manifest := filepath.Join(tmp, "promo.yaml")
+	if err := os.WriteFile(manifest, []byte("version: 1\n# <script>alert(1)</script>\n"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	gif := filepath.Join(tmp, "promo_small.gif")
+	if err := os.WriteFile(gif, []byte("GIF89a"), 0o644); err != nil {
+		t.Fatalf("write gif: %v", err)
+	}
+	cfg := &config.Config{ProjectRoot: tmp, Tapes: []config.Tape{{ID: "promo", Notes: "Client cut"}}}
+	records := []runner.RunRecord{{
+		RunID:        "20261016_120000_promo_001",
+		TapeID:       "promo",
+		ManifestPath: manifest,
+		Timestamp:    time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		ExitCode:     0,
+		DurationMS:   4200,
+		Command:      []string{"vcr", "render", manifest},
+		Artifacts:    []string{gif},
+		Uploads: []runner.UploadResult{
+			{Path: "out.mov", URL: "https://cdn.example/promo.mov"},
+			{Path: "out.mov", URL: "s3://renders/promo.mov"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := Build(context.Background(), cfg, records, Options{}).WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"Client cut",
+		`class="badge success"`,
+		"data:image/gif;base64,R0lGODlh",
+		`<a href="https://cdn.example/promo.mov">`,
+		"<dd>s3://renders/promo.mov</dd>",
+		"&lt;script&gt;",
+		"4.2s",
+	} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("page is missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Fatal("manifest content was not escaped")
+	}
+}
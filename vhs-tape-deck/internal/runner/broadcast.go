@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/events"
+)
+
+// replayEvents is how many recent events a late subscriber receives first.
+const replayEvents = 1024
+
+// followerQueue is how many events may wait for a subscriber other than the
+// caller of Start before its log and progress events are dropped.
+const followerQueue = 1024
+
+// broadcast publishes a run's events on a bus registered under its run id
+// until the run finishes. It returns the channel the run writes to and the
+// caller's channel, which sees every event. The caller's channel is not
+// queued on the bus: a caller that reads slowly slows the run down, as it
+// would reading the run's channel directly.
+func (r *Runner) broadcast(plan *CommandPlan) (chan<- Event, <-chan Event) {
+	bus := events.New[Event](replayEvents, followerQueue, func(e Event) bool {
+		return e.Type == EventLog || e.Type == EventProgress
+	})
+	logged, stopLog := bus.Subscribe()
+	go r.logRun(plan, logged, stopLog)
+
+	r.mu.Lock()
+	r.active[plan.RunID] = bus
+	r.mu.Unlock()
+
+	in := make(chan Event, 128)
+	out := make(chan Event, 128)
+	go func() {
+		defer close(out)
+		for event := range in {
+			bus.Publish(event)
+			out <- event
+		}
+		bus.Close()
+		r.mu.Lock()
+		delete(r.active, plan.RunID)
+		r.mu.Unlock()
+	}()
+	return in, out
+}
+
+// Subscribe follows a run that is still going, starting with its most
+// recent events. ok is false once the run has finished. The channel closes
+// after EventFinished, or when cancel is called. A subscriber that falls
+// behind misses log and progress lines, never other events.
+func (r *Runner) Subscribe(runID string) (<-chan Event, func(), bool) {
+	r.mu.Lock()
+	bus, ok := r.active[runID]
+	r.mu.Unlock()
+	if !ok {
+		return nil, func() {}, false
+	}
+	ch, cancel := bus.Subscribe()
+	return ch, cancel, true
+}
+
+// logRun logs stalls and the final outcome of a run.
+func (r *Runner) logRun(plan *CommandPlan, in <-chan Event, stop func()) {
+	defer stop()
+	for event := range in {
+		switch event.Type {
+		case EventStalled:
+			r.log.Warn("run stalled", "run_id", plan.RunID, "detail", event.Message)
+		case EventFinished:
+			level := slog.LevelInfo
+			if event.ExitCode != 0 {
+				level = slog.LevelError
+			}
+			r.log.Log(context.Background(), level, "run finished", "run_id", plan.RunID, "exit_code", event.ExitCode, "message", event.Message)
+			if event.RecordErr != nil {
+				r.log.Error("write run record", "run_id", plan.RunID, "path", plan.RecordPath, "err", event.RecordErr)
+			}
+		}
+	}
+}
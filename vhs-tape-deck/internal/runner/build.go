@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+)
+
+// BuildStep is the vcr rebuild a plan runs before rendering.
+type BuildStep struct {
+	Command []string
+	Dir     string
+	Watch   []string
+	// StampPath stores the source fingerprint of the last successful build.
+	StampPath string
+}
+
+// BuildRecord is the build step's entry in a run record.
+type BuildRecord struct {
+	Command    []string `json:"command"`
+	Skipped    bool     `json:"skipped,omitempty"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMS int64    `json:"duration_ms,omitempty"`
+}
+
+func BuildStampPath(runsDir string) string {
+	return filepath.Join(runsDir, "build_stamp")
+}
+
+// SourceFingerprint hashes the path, size and modification time of every
+// file under paths. Directories named .git or target are skipped so build
+// output does not count as a source change.
+func SourceFingerprint(paths []string) (string, error) {
+	var lines []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (d.Name() == ".git" || d.Name() == "target") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			lines = append(lines, fmt.Sprintf("%s %d %d", path, info.Size(), info.ModTime().UnixNano()))
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("scan build.watch: %w", err)
+		}
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runBuild rebuilds vcr when the watched sources changed, streaming compiler
+// output as [build] log lines.
+func (r *Runner) runBuild(ctx context.Context, step *BuildStep, record *RunRecord, events chan<- Event) error {
+	record.Build = &BuildRecord{Command: step.Command, ExitCode: -1}
+
+	fingerprint := ""
+	if len(step.Watch) > 0 {
+		var err error
+		fingerprint, err = SourceFingerprint(step.Watch)
+		if err != nil {
+			return err
+		}
+		if stamp, err := os.ReadFile(step.StampPath); err == nil && strings.TrimSpace(string(stamp)) == fingerprint {
+			record.Build.Skipped = true
+			record.Build.ExitCode = 0
+			events <- Event{Type: EventLog, Message: "[build] sources unchanged, skipping build"}
+			return nil
+		}
+	}
+
+	events <- Event{Type: EventLog, Message: "[build] $ " + quoteCommand(step.Command...)}
+	events <- Event{Type: EventProgress, Progress: &progress.Snapshot{Phase: progress.PhaseBuild}}
+	r.log.Info("build started", "command", quoteCommand(step.Command...), "dir", step.Dir)
+
+	startedAt := time.Now()
+	wait, err := startStreamed(ctx, step.Command, step.Dir, func(line string) {
+		events <- Event{Type: EventLog, Message: "[build] " + line}
+	})
+	if err != nil {
+		return fmt.Errorf("start build: %w", err)
+	}
+	waitErr := wait()
+	// The build may have replaced vcr; detect its version again.
+	r.forgetFeatures()
+
+	record.Build.DurationMS = time.Since(startedAt).Milliseconds()
+	record.Build.ExitCode = exitCodeFromError(waitErr)
+	if waitErr != nil {
+		r.log.Warn("build failed", "err", waitErr)
+		return fmt.Errorf("build failed: %w", waitErr)
+	}
+	r.log.Info("build finished", "duration_ms", record.Build.DurationMS)
+
+	if fingerprint != "" {
+		// Fingerprint again: the build may have touched watched files.
+		if after, err := SourceFingerprint(step.Watch); err == nil {
+			fingerprint = after
+		}
+		if err := atomicfile.Write(step.StampPath, []byte(fingerprint+"\n")); err != nil {
+			r.log.Warn("write build stamp", "path", step.StampPath, "err", err)
+		}
+	}
+	return nil
+}
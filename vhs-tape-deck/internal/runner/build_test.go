@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func TestSourceFingerprintIgnoresTargetDir(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "main.rs"), "fn main() {}")
+	before, err := SourceFingerprint([]string{src})
+	if err != nil {
+		t.Fatalf("SourceFingerprint: %v", err)
+	}
+
+	writeFile(t, filepath.Join(src, "target", "debug", "vcr"), "binary")
+	if got, _ := SourceFingerprint([]string{src}); got != before {
+		t.Fatalf("target/ output changed the fingerprint")
+	}
+
+	writeFile(t, filepath.Join(src, "main.rs"), "fn main() { run() }")
+	if got, _ := SourceFingerprint([]string{src}); got == before {
+		t.Fatalf("expected a source edit to change the fingerprint")
+	}
+}
+
+func TestExecuteBuildsOnlyWhenSourcesChange(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	writeFile(t, filepath.Join(src, "lib.rs"), "v1")
+	builds := filepath.Join(tmp, "builds.txt")
+
+	run := func(buildScript string) (Event, *RunRecord, []string) {
+		plan := &CommandPlan{
+			RunID:      "build",
+			Binary:     "sh",
+			Args:       []string{"-c", "echo rendered"},
+			CWD:        tmp,
+			OutputDir:  filepath.Join(tmp, "out"),
+			RecordPath: filepath.Join(tmp, "records", "build.json"),
+			Build: &BuildStep{
+				Command:   []string{"sh", "-c", buildScript},
+				Dir:       tmp,
+				Watch:     []string{src},
+				StampPath: BuildStampPath(tmp),
+			},
+		}
+		record := &RunRecord{RunID: plan.RunID, ExitCode: -1}
+		events := make(chan Event, 128)
+		go New(nil).execute(context.Background(), plan, record, events)
+
+		var finished Event
+		var logs []string
+		for event := range events {
+			switch event.Type {
+			case EventLog:
+				logs = append(logs, event.Message)
+			case EventFinished:
+				finished = event
+			}
+		}
+		return finished, record, logs
+	}
+
+	build := "echo compiling; echo built >> " + shellQuote(builds)
+	finished, record, logs := run(build)
+	if finished.ExitCode != 0 || record.Build == nil || record.Build.Skipped {
+		t.Fatalf("expected a build, got exit=%d build=%+v", finished.ExitCode, record.Build)
+	}
+	if !containsLine(logs, "[build] compiling") || !containsLine(logs, "[out] rendered") {
+		t.Fatalf("expected build then render output, got %v", logs)
+	}
+
+	_, record, _ = run(build)
+	if !record.Build.Skipped {
+		t.Fatalf("expected unchanged sources to skip the build")
+	}
+
+	writeFile(t, filepath.Join(src, "lib.rs"), "v2 changed")
+	run(build)
+	buf, err := os.ReadFile(builds)
+	if err != nil {
+		t.Fatalf("read builds: %v", err)
+	}
+	if n := strings.Count(string(buf), "built"); n != 2 {
+		t.Fatalf("expected 2 builds, got %d", n)
+	}
+
+	writeFile(t, filepath.Join(src, "lib.rs"), "v3 broken!")
+	finished, record, logs = run("echo 'error[E0425]'; exit 3")
+	if finished.ExitCode != 3 || record.Status != StatusFailed || !strings.Contains(finished.Message, "build failed") {
+		t.Fatalf("expected build failure, got exit=%d status=%s msg=%q", finished.ExitCode, record.Status, finished.Message)
+	}
+	if containsLine(logs, "[out] rendered") {
+		t.Fatalf("render ran after a failed build")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStartChecksMinVersionAfterBuild(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	if err := os.MkdirAll(cfg.ProjectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	fake := func(v string) string {
+		return "#!/bin/sh\n[ \"$1\" = --version ] && echo 'vcr " + v + "'\nexit 0\n"
+	}
+	cfg.VCRBinary = filepath.Join(t.TempDir(), "vcr")
+	writeFile(t, cfg.VCRBinary, fake("0.1.0"))
+	if err := os.Chmod(cfg.VCRBinary, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	cfg.VCRMinVersion = "0.2.0"
+	// The build installs a vcr that meets the pin.
+	updated := filepath.Join(t.TempDir(), "vcr-new")
+	writeFile(t, updated, fake("0.2.1"))
+	cfg.Build = config.Build{Command: []string{"cp", updated, cfg.VCRBinary}}
+
+	r := New(nil)
+	if v := r.DetectFeatures(context.Background(), cfg).Version; v != "0.1.0" {
+		t.Fatalf("expected the outdated version to be cached first, got %q", v)
+	}
+	events, err := r.Start(context.Background(), Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if err != nil {
+		t.Fatalf("Start refused a run whose build updates vcr: %v", err)
+	}
+	var finished Event
+	for event := range events {
+		if event.Type == EventFinished {
+			finished = event
+		}
+	}
+	if finished.Record == nil || finished.Record.Build == nil || finished.Record.Build.Skipped {
+		t.Fatalf("expected the build to run, got %+v", finished)
+	}
+	if finished.ExitCode != 0 {
+		t.Fatalf("expected the rebuilt vcr to pass the pin, got %d: %s", finished.ExitCode, finished.Message)
+	}
+	if v := r.DetectFeatures(context.Background(), cfg).Version; v != "0.2.1" {
+		t.Fatalf("expected the rebuilt version after the build, got %q", v)
+	}
+
+	// A build that leaves vcr outdated fails the run after building.
+	writeFile(t, cfg.VCRBinary, fake("0.1.0"))
+	cfg.Build = config.Build{Command: []string{"true"}}
+	events, err = r.Start(context.Background(), Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for event := range events {
+		if event.Type == EventFinished {
+			finished = event
+		}
+	}
+	if finished.ExitCode == 0 || !strings.Contains(finished.Message, "pinned 0.2.0") || finished.Hint == "" {
+		t.Fatalf("expected an outdated vcr to fail after the build, got %d: %s", finished.ExitCode, finished.Message)
+	}
+}
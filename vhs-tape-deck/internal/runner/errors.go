@@ -0,0 +1,27 @@
+package runner
+
+import "errors"
+
+// Sentinels for failures the user can fix. They are returned wrapped with
+// details and a hint (see internal/hint); test for them with errors.Is.
+var (
+	ErrManifestMissing       = errors.New("manifest not found")
+	ErrBinaryNotFound        = errors.New("vcr binary not found")
+	ErrUnsupportedSubcommand = errors.New("vcr has no render-frame subcommand")
+	ErrVCROutdated           = errors.New("vcr is older than vcr_min_version")
+	ErrNoPreview             = errors.New("no preview configured")
+	ErrNoGolden              = errors.New("no golden frames configured")
+	ErrNoAlpha               = errors.New("output has no alpha channel")
+	ErrProbeMissing          = errors.New("ffprobe is not installed")
+)
+
+const (
+	hintManifestMissing = "check the tape's manifest path; relative paths resolve from project_root"
+	hintBinaryNotFound  = "install vcr or set vcr_binary in the config to its absolute path"
+	hintUnsupported     = "update vcr, or set preview.args to a subcommand this vcr supports"
+	hintOutdated        = "update vcr or lower vcr_min_version in the config"
+	hintNoPreview       = "set preview.enabled: true on the tape"
+	hintNoGolden        = "add golden.frames to the tape"
+	hintNoAlpha         = "export with an alpha-capable codec such as ProRes 4444, or drop requires_alpha"
+	hintProbeMissing    = "install ffmpeg (which provides ffprobe) or drop requires_alpha"
+)
@@ -0,0 +1,59 @@
+//go:build windows
+
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var procGenerateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+// configureProcess puts the child in a new process group so it can receive
+// CTRL_BREAK without the deck itself being interrupted.
+func configureProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func interruptProcess(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	ok, _, err := procGenerateConsoleCtrlEvent.Call(syscall.CTRL_BREAK_EVENT, uintptr(cmd.Process.Pid))
+	if ok == 0 {
+		return fmt.Errorf("send ctrl-break: %w", err)
+	}
+	return nil
+}
+
+// killProcessTree uses taskkill /T so grandchildren (ffmpeg) die with vcr.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	out, err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).CombinedOutput()
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("taskkill: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// processAlive reports whether pid exists and has not exited.
+func processAlive(pid int) bool {
+	const queryLimitedInformation = 0x1000
+	const stillActive = 259
+	h, err := syscall.OpenProcess(queryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
@@ -4,10 +4,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+)
+
+type RunStatus string
+
+const (
+	StatusSuccess  RunStatus = "success"
+	StatusFailed   RunStatus = "failed"
+	StatusCanceled RunStatus = "canceled"
+	// StatusAborted marks runs that were in flight when the deck exited.
+	StatusAborted RunStatus = "aborted"
 )
 
 type RunRecord struct {
@@ -20,28 +34,149 @@ type RunRecord struct {
 	CWD          string            `json:"cwd"`
 	EnvOverrides map[string]string `json:"env_overrides"`
 	ExitCode     int               `json:"exit_code"`
+	Status       RunStatus         `json:"status,omitempty"`
 	OutputPaths  []string          `json:"output_paths"`
 	Action       Action            `json:"action"`
 	DryRun       bool              `json:"dry_run"`
+	DurationMS   int64             `json:"duration_ms,omitempty"`
+	// Artifacts are files derived from the outputs after the run, such as
+	// share GIFs.
+	Artifacts []string `json:"artifacts,omitempty"`
+	// Build is set when the config has a build step.
+	Build *BuildRecord `json:"build,omitempty"`
+	// Trigger is set for runs nobody started by hand, e.g. "schedule:nightly".
+	Trigger string `json:"trigger,omitempty"`
+	// Golden holds the frame comparisons of a verify run.
+	Golden []GoldenResult `json:"golden,omitempty"`
+	// Uploads lists where each output was uploaded after the run.
+	Uploads []UploadResult `json:"uploads,omitempty"`
+}
+
+func RecordPath(runsDir, runID string) string {
+	return filepath.Join(runsDir, "records", runID+".json")
 }
 
 func WriteRunRecord(path string, record *RunRecord) error {
 	if record == nil {
 		return fmt.Errorf("nil run record")
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("mkdir record dir: %w", err)
-	}
 	buf, err := json.MarshalIndent(record, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal record: %w", err)
 	}
-	if err := os.WriteFile(path, append(buf, '\n'), 0o644); err != nil {
+	if err := atomicfile.Write(path, append(buf, '\n')); err != nil {
 		return fmt.Errorf("write record: %w", err)
 	}
 	return nil
 }
 
+func ReadRunRecord(path string) (*RunRecord, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read record: %w", err)
+	}
+	var record RunRecord
+	if err := json.Unmarshal(buf, &record); err != nil {
+		return nil, fmt.Errorf("parse record %s: %w", filepath.Base(path), err)
+	}
+	return &record, nil
+}
+
+// History is what LoadHistory found under <runsDir>/records.
+type History struct {
+	// Records are the readable records, oldest first.
+	Records []RunRecord
+	// Quarantined are unreadable records that were moved to
+	// QuarantineDir, by their new path.
+	Quarantined []string
+}
+
+func QuarantineDir(runsDir string) string {
+	return filepath.Join(runsDir, "records", "quarantine")
+}
+
+// LoadHistory reads every record under <runsDir>/records. A record that
+// cannot be parsed (for example one truncated by a crash before records
+// were written atomically) is moved to QuarantineDir so it is reported once
+// instead of being skipped on every load; it stays there for inspection.
+func LoadHistory(runsDir string) (History, error) {
+	paths, err := filepath.Glob(filepath.Join(runsDir, "records", "*.json"))
+	if err != nil {
+		return History{}, fmt.Errorf("list records: %w", err)
+	}
+	h := History{Records: make([]RunRecord, 0, len(paths))}
+	for _, path := range paths {
+		record, err := ReadRunRecord(path)
+		if err == nil {
+			h.Records = append(h.Records, *record)
+			continue
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if moved, qerr := quarantine(runsDir, path); qerr == nil {
+			h.Quarantined = append(h.Quarantined, moved)
+		}
+	}
+	sort.SliceStable(h.Records, func(i, j int) bool {
+		return h.Records[i].Timestamp.Before(h.Records[j].Timestamp)
+	})
+	return h, nil
+}
+
+// LatestRecord returns the newest readable record without loading the
+// rest: run ids start with their timestamp, so names sort by start time.
+// It returns nil when there are no records.
+func LatestRecord(runsDir string) (*RunRecord, error) {
+	paths, err := filepath.Glob(filepath.Join(runsDir, "records", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	sort.Strings(paths)
+	for i := len(paths) - 1; i >= 0; i-- {
+		if record, err := ReadRunRecord(paths[i]); err == nil {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// LoadRunRecords returns the readable records of LoadHistory.
+func LoadRunRecords(runsDir string) ([]RunRecord, error) {
+	h, err := LoadHistory(runsDir)
+	return h.Records, err
+}
+
+func quarantine(runsDir, path string) (string, error) {
+	dir := QuarantineDir(runsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(dst); err == nil {
+		dst = filepath.Join(dir, fmt.Sprintf("%s.%d", filepath.Base(path), time.Now().UnixNano()))
+	}
+	if err := os.Rename(path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// AddArtifact appends artifact to the record at path.
+func AddArtifact(path, artifact string) error {
+	record, err := ReadRunRecord(path)
+	if err != nil {
+		return err
+	}
+	for _, existing := range record.Artifacts {
+		if existing == artifact {
+			return nil
+		}
+	}
+	record.Artifacts = append(record.Artifacts, artifact)
+	return WriteRunRecord(path, record)
+}
+
 func exitCodeFromError(err error) int {
 	if err == nil {
 		return 0
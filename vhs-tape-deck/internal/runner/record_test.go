@@ -48,4 +48,61 @@ func TestWriteRunRecord(t *testing.T) {
 	if decoded.ExitCode != 0 {
 		t.Fatalf("unexpected exit code: %d", decoded.ExitCode)
 	}
+
+	record.ExitCode = 2
+	if err := WriteRunRecord(recordPath, record); err != nil {
+		t.Fatalf("rewrite record: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(recordPath))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected only the record in its dir, got %v (err=%v)", entries, err)
+	}
+}
+
+func TestLoadRunRecordsAndAddArtifact(t *testing.T) {
+	t.Parallel()
+
+	runsDir := t.TempDir()
+	base := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"late", "early"} {
+		record := &RunRecord{RunID: id, TapeID: "alpha", Timestamp: base.Add(time.Duration(1-i) * time.Minute)}
+		if err := WriteRunRecord(RecordPath(runsDir, id), record); err != nil {
+			t.Fatalf("WriteRunRecord: %v", err)
+		}
+	}
+	if err := os.WriteFile(RecordPath(runsDir, "broken"), []byte("{"), 0o644); err != nil {
+		t.Fatalf("write broken record: %v", err)
+	}
+
+	h, err := LoadHistory(runsDir)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	records := h.Records
+	if len(records) != 2 || records[0].RunID != "early" || records[1].RunID != "late" {
+		t.Fatalf("expected records oldest first, got %+v", records)
+	}
+	if len(h.Quarantined) != 1 || filepath.Dir(h.Quarantined[0]) != QuarantineDir(runsDir) {
+		t.Fatalf("expected the broken record to be quarantined, got %v", h.Quarantined)
+	}
+	if _, err := os.Stat(RecordPath(runsDir, "broken")); !os.IsNotExist(err) {
+		t.Fatalf("broken record still in records dir: %v", err)
+	}
+	if h, _ := LoadHistory(runsDir); len(h.Quarantined) != 0 || len(h.Records) != 2 {
+		t.Fatalf("second load should be clean, got %+v", h)
+	}
+
+	path := RecordPath(runsDir, "late")
+	for i := 0; i < 2; i++ {
+		if err := AddArtifact(path, "/tmp/late_medium.gif"); err != nil {
+			t.Fatalf("AddArtifact: %v", err)
+		}
+	}
+	record, err := ReadRunRecord(path)
+	if err != nil {
+		t.Fatalf("ReadRunRecord: %v", err)
+	}
+	if len(record.Artifacts) != 1 || record.Artifacts[0] != "/tmp/late_medium.gif" {
+		t.Fatalf("unexpected artifacts: %v", record.Artifacts)
+	}
 }
@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRunIDAttempts bounds the search for a free run id.
+const maxRunIDAttempts = 1000
+
+func claimsDir(runsDir string) string {
+	return filepath.Join(runsDir, "claims")
+}
+
+// reserveRunID returns the next run id for name that no other run owns.
+// The in-memory counter only covers this process, so each id is also
+// claimed by exclusively creating <runs_dir>/claims/<id>; two tape-deck
+// processes (or serve and the CLI) that pick the same id in the same second
+// get different ones. Ids that already have a record, or for which taken
+// reports true, are skipped as well. The claim is removed by releaseRunID
+// once the record is written.
+func (r *Runner) reserveRunID(runsDir, name string, ts time.Time, taken func(id string) bool) (string, string, error) {
+	return claimID(claimsDir(runsDir), name, func(int) string { return r.nextRunID(name, ts) }, func(id string) bool {
+		if _, err := os.Stat(RecordPath(runsDir, id)); err == nil {
+			return true
+		}
+		return taken != nil && taken(id)
+	})
+}
+
+// ReserveID claims "<ts>_<name>_NNN" for a pipeline run, batch or bench
+// report, taking the lowest NNN with no claim under <runs_dir>/claims/<kind>
+// for which taken reports false. Release the claim with ReleaseID once the
+// id's record exists, so taken covers it from then on.
+func ReserveID(runsDir, kind, name string, ts time.Time, taken func(id string) bool) (string, string, error) {
+	prefix := ts.Format("20060102_150405") + "_" + sanitizeID(name)
+	return claimID(filepath.Join(claimsDir(runsDir), kind), name, func(n int) string {
+		return fmt.Sprintf("%s_%03d", prefix, n+1)
+	}, taken)
+}
+
+// ReleaseID drops a claim made by ReserveID.
+func ReleaseID(claim string) {
+	releaseRunID(claim)
+}
+
+func claimID(dir, name string, next func(attempt int) string, taken func(id string) bool) (string, string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("create claims dir: %w", err)
+	}
+	for attempt := 0; attempt < maxRunIDAttempts; attempt++ {
+		id := next(attempt)
+		if taken != nil && taken(id) {
+			continue
+		}
+		claim := filepath.Join(dir, id)
+		f, err := os.OpenFile(claim, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("claim run id: %w", err)
+		}
+		fmt.Fprintf(f, "%d\n", os.Getpid())
+		if err := f.Close(); err != nil {
+			return "", "", fmt.Errorf("claim run id: %w", err)
+		}
+		return id, claim, nil
+	}
+	return "", "", fmt.Errorf("no free run id for %q after %d attempts", name, maxRunIDAttempts)
+}
+
+// releaseRunID drops a claim made by reserveRunID. A claim left behind by a
+// crash only retires its id.
+func releaseRunID(claim string) {
+	if claim != "" {
+		_ = os.Remove(claim)
+	}
+}
+
+// ActiveRunIDs lists the run ids claimed by a process that is still
+// running, oldest first. Claims left behind by a crash are skipped.
+func ActiveRunIDs(runsDir string) ([]string, error) {
+	entries, err := os.ReadDir(claimsDir(runsDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list claims: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(claimsDir(runsDir), e.Name()))
+		if err != nil {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(buf)))
+		if err != nil || !processAlive(pid) {
+			continue
+		}
+		ids = append(ids, e.Name())
+	}
+	return ids, nil
+}
+
+// ParseRunID splits a run id into the tape (or pipeline) it belongs to and
+// the time the run started, in local time.
+func ParseRunID(id string) (string, time.Time, bool) {
+	const tsLen = len("20060102_150405")
+	if len(id) < tsLen+2 || id[tsLen] != '_' {
+		return "", time.Time{}, false
+	}
+	started, err := time.ParseInLocation("20060102_150405", id[:tsLen], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	name := id[tsLen+1:]
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		name = name[:i]
+	}
+	return name, started, true
+}
+
+// ReleaseClaim gives up the run id of a plan that will not be executed.
+func ReleaseClaim(plan *CommandPlan) {
+	releaseRunID(plan.ClaimPath)
+	plan.ClaimPath = ""
+}
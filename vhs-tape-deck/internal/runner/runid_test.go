@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildPlanRunIDsDoNotCollideAcrossRunners(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	now := func() time.Time { return time.Date(2026, 2, 20, 12, 30, 1, 0, time.UTC) }
+	// Two runners stand in for two processes with their own counters.
+	a, b := New(now), New(now)
+
+	first, _, err := a.BuildPlan(Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if err != nil {
+		t.Fatalf("BuildPlan a: %v", err)
+	}
+	second, _, err := b.BuildPlan(Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if err != nil {
+		t.Fatalf("BuildPlan b: %v", err)
+	}
+	if first.RunID == second.RunID || !strings.HasSuffix(second.RunID, "_002") {
+		t.Fatalf("expected the second process to skip the claimed id, got %s and %s", first.RunID, second.RunID)
+	}
+
+	// An existing record or output also retires an id once its claim is gone.
+	releaseRunID(first.ClaimPath)
+	releaseRunID(second.ClaimPath)
+	if err := WriteRunRecord(RecordPath(cfg.RunsDir, "20260220_123001_alpha_003"), &RunRecord{}); err != nil {
+		t.Fatalf("write record: %v", err)
+	}
+	if err := os.MkdirAll(cfg.Tapes[0].OutputDir, 0o755); err != nil {
+		t.Fatalf("mkdir output: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Tapes[0].OutputDir, "20260220_123001_alpha_001.mov"), nil, 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+	third, _, err := New(now).BuildPlan(Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if err != nil {
+		t.Fatalf("BuildPlan c: %v", err)
+	}
+	if third.RunID != "20260220_123001_alpha_002" {
+		t.Fatalf("expected the first free id, got %s", third.RunID)
+	}
+	if _, err := os.Stat(third.ClaimPath); err != nil {
+		t.Fatalf("claim not created: %v", err)
+	}
+}
+
+func TestActiveRunIDsSkipsDeadClaims(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if ids, err := ActiveRunIDs(dir); err != nil || len(ids) != 0 {
+		t.Fatalf("expected no active runs without a claims dir, got %v (%v)", ids, err)
+	}
+	if err := os.MkdirAll(claimsDir(dir), 0o755); err != nil {
+		t.Fatalf("mkdir claims: %v", err)
+	}
+	claims := map[string]string{
+		"20260301_115500_alpha_001": strconv.Itoa(os.Getpid()),
+		"20260301_115501_beta_001":  "not a pid",
+		"20260301_115502_gamma_001": "2147483647",
+	}
+	for id, pid := range claims {
+		if err := os.WriteFile(filepath.Join(claimsDir(dir), id), []byte(pid+"\n"), 0o644); err != nil {
+			t.Fatalf("write claim: %v", err)
+		}
+	}
+	ids, err := ActiveRunIDs(dir)
+	if err != nil || len(ids) != 1 || ids[0] != "20260301_115500_alpha_001" {
+		t.Fatalf("expected only the live claim, got %v (%v)", ids, err)
+	}
+
+	name, started, ok := ParseRunID("20260301_115500_promo-spot_012")
+	if !ok || name != "promo-spot" || !started.Equal(time.Date(2026, 3, 1, 11, 55, 0, 0, time.Local)) {
+		t.Fatalf("unexpected parse: %q %v %v", name, started, ok)
+	}
+	if _, _, ok := ParseRunID("promo"); ok {
+		t.Fatalf("expected a malformed id to fail")
+	}
+}
+
+func TestReserveIDSkipsClaimedAndTakenIDs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ts := time.Date(2026, 2, 20, 12, 30, 1, 0, time.UTC)
+	first, claim, err := ReserveID(dir, "pipelines", "release", ts, nil)
+	if err != nil || first != "20260220_123001_release_001" {
+		t.Fatalf("unexpected first id %q (%v)", first, err)
+	}
+	second, _, err := ReserveID(dir, "pipelines", "release", ts, nil)
+	if err != nil || second != "20260220_123001_release_002" {
+		t.Fatalf("expected the claimed id to be skipped, got %q (%v)", second, err)
+	}
+	ReleaseID(claim)
+	third, _, err := ReserveID(dir, "pipelines", "release", ts, func(id string) bool { return id == first })
+	if err != nil || third != "20260220_123001_release_003" {
+		t.Fatalf("expected taken ids to be skipped, got %q (%v)", third, err)
+	}
+	if ids, err := ActiveRunIDs(dir); err != nil || len(ids) != 0 {
+		t.Fatalf("pipeline claims should not count as active runs, got %v (%v)", ids, err)
+	}
+}
@@ -6,15 +6,27 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/events"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/trace"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
 )
 
 type Action string
@@ -22,6 +34,11 @@ type Action string
 const (
 	ActionPrimary Action = "primary"
 	ActionPreview Action = "preview"
+	// ActionCommand marks runs of arbitrary commands, such as pipeline steps.
+	ActionCommand Action = "command"
+	// ActionVerify renders a tape's golden frames and compares them with
+	// their references.
+	ActionVerify Action = "verify"
 )
 
 type EventType string
@@ -29,16 +46,21 @@ type EventType string
 const (
 	EventStarted  EventType = "started"
 	EventLog      EventType = "log"
+	EventStalled  EventType = "stalled"
+	EventProgress EventType = "progress"
 	EventFinished EventType = "finished"
 )
 
 type Event struct {
-	Type      EventType
-	Message   string
+	Type    EventType
+	Message string
+	// Hint tells the user how to fix a failed run, when the runner knows.
+	Hint      string
 	Record    *RunRecord
 	Plan      *CommandPlan
 	ExitCode  int
 	RecordErr error
+	Progress  *progress.Snapshot
 }
 
 type Request struct {
@@ -46,6 +68,12 @@ type Request struct {
 	Tape   config.Tape
 	Action Action
 	DryRun bool
+	// Trigger says what started the run when it was not a person, e.g.
+	// "schedule:nightly".
+	Trigger string
+	// SkipUpload leaves out the tape's upload step, e.g. for bench renders
+	// that are measured and thrown away.
+	SkipUpload bool
 }
 
 type FeatureInfo struct {
@@ -53,6 +81,29 @@ type FeatureInfo struct {
 	HasRenderFrame   bool
 	HelpSnippet      string
 	DetectionFailure string
+	// Version is what `vcr --version` reported, empty when it could not be
+	// parsed.
+	Version string
+}
+
+// CheckMinVersion reports an error when the detected vcr is older than min.
+// An unknown version passes so a binary with unusual output is not locked out.
+func (f FeatureInfo) CheckMinVersion(min string) error {
+	if min == "" || f.Version == "" {
+		return nil
+	}
+	want, err := version.Parse(min)
+	if err != nil {
+		return fmt.Errorf("vcr_min_version: %w", err)
+	}
+	have, err := version.Parse(f.Version)
+	if err != nil {
+		return nil
+	}
+	if have.Compare(want) < 0 {
+		return hint.Wrap(fmt.Errorf("%w: vcr %s, pinned %s", ErrVCROutdated, f.Version, min), hintOutdated)
+	}
+	return nil
 }
 
 type CommandPlan struct {
@@ -68,16 +119,31 @@ type CommandPlan struct {
 	Action       Action
 	DryRun       bool
 	RecordPath   string
+	// ClaimPath holds the run id until the record is written.
+	ClaimPath  string
+	StallAfter time.Duration
+	KillAfter  time.Duration
+	// LineBuffer is the longest output line, in bytes, emitted whole.
+	LineBuffer   int
+	RequireAlpha bool
+	Build        *BuildStep
+	// MinVersion is vcr_min_version when it must be checked after Build,
+	// so a rebuild can bring an outdated vcr up to date first.
+	MinVersion string
+	Upload     *UploadStep
 }
 
 type Runner struct {
 	nowFn func() time.Time
+	log   *slog.Logger
+	probe func(ctx context.Context, path string) (probe.Info, error)
 
 	mu       sync.Mutex
 	counter  map[string]int
 	feature  FeatureInfo
 	checked  bool
 	checkErr string
+	active   map[string]*events.Bus[Event]
 }
 
 func New(nowFn func() time.Time) *Runner {
@@ -86,11 +152,39 @@ func New(nowFn func() time.Time) *Runner {
 	}
 	return &Runner{
 		nowFn:   nowFn,
+		log:     logging.Discard(),
 		counter: map[string]int{},
+		active:  map[string]*events.Bus[Event]{},
+		probe: func(ctx context.Context, path string) (probe.Info, error) {
+			return probe.Run(ctx, "", path)
+		},
 	}
 }
 
+func (r *Runner) SetLogger(logger *slog.Logger) {
+	r.log = logging.Component(logger, "runner")
+}
+
+// cachedFeatures returns what DetectFeatures found, without running vcr.
+func (r *Runner) cachedFeatures() (FeatureInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.feature, r.checked
+}
+
+// forgetFeatures makes the next DetectFeatures run vcr again, e.g. after
+// a build replaced the binary.
+func (r *Runner) forgetFeatures() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checked = false
+}
+
 func (r *Runner) DetectFeatures(ctx context.Context, cfg *config.Config) FeatureInfo {
+	return r.detectFeatures(ctx, cfg.VCRBinary, cfg.ProjectRoot)
+}
+
+func (r *Runner) detectFeatures(ctx context.Context, binary, dir string) FeatureInfo {
 	r.mu.Lock()
 	if r.checked {
 		defer r.mu.Unlock()
@@ -101,8 +195,8 @@ func (r *Runner) DetectFeatures(ctx context.Context, cfg *config.Config) Feature
 	helpCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(helpCtx, cfg.VCRBinary, "--help")
-	cmd.Dir = cfg.ProjectRoot
+	cmd := exec.CommandContext(helpCtx, binary, "--help")
+	cmd.Dir = dir
 	out, err := cmd.CombinedOutput()
 	help := string(out)
 
@@ -114,6 +208,18 @@ func (r *Runner) DetectFeatures(ctx context.Context, cfg *config.Config) Feature
 	}
 	if err != nil {
 		feature.DetectionFailure = err.Error()
+		r.log.Warn("feature detection failed", "binary", binary, "err", err)
+	}
+
+	versionCmd := exec.CommandContext(helpCtx, binary, "--version")
+	versionCmd.Dir = dir
+	if out, err := versionCmd.CombinedOutput(); err == nil {
+		if v, ok := version.Find(string(out)); ok {
+			feature.Version = v.String()
+		}
+	}
+	if feature.Version == "" {
+		r.log.Warn("could not determine vcr version", "binary", binary)
 	}
 
 	r.mu.Lock()
@@ -124,15 +230,49 @@ func (r *Runner) DetectFeatures(ctx context.Context, cfg *config.Config) Feature
 	return feature
 }
 
+// CheckManifest validates a manifest with `vcr check` without rendering and
+// returns the combined output.
+func (r *Runner) CheckManifest(ctx context.Context, cfg *config.Config, manifestPath string) (string, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(checkCtx, cfg.VCRBinary, "check", manifestPath)
+	cmd.Dir = cfg.ProjectRoot
+	cmd.Env = mergeEnv(os.Environ(), cfg.Env)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.log.Warn("manifest check failed", "manifest", manifestPath, "err", err)
+	}
+	return strings.TrimSpace(string(out)), err
+}
+
 func (r *Runner) Start(ctx context.Context, req Request) (<-chan Event, error) {
+	if req.Action == ActionPreview || req.Action == ActionVerify {
+		if f, ok := r.cachedFeatures(); ok && !f.HasRenderFrame && !hasSubcommand(req.Tape.Preview.Args) {
+			return nil, hint.Wrap(fmt.Errorf("tape %q: %w", req.Tape.ID, ErrUnsupportedSubcommand), hintUnsupported)
+		}
+	}
+	if req.Action == ActionVerify {
+		return r.startVerify(ctx, req)
+	}
 	plan, record, err := r.BuildPlan(req)
 	if err != nil {
 		return nil, err
 	}
+	if req.Config.VCRMinVersion != "" && !req.DryRun {
+		if plan.Build != nil {
+			plan.MinVersion = req.Config.VCRMinVersion
+		} else if err := r.DetectFeatures(ctx, req.Config).CheckMinVersion(req.Config.VCRMinVersion); err != nil {
+			releaseRunID(plan.ClaimPath)
+			return nil, err
+		}
+	}
 
-	events := make(chan Event, 128)
-	go r.execute(ctx, plan, record, events)
-	return events, nil
+	r.log.Info("run started", "run_id", plan.RunID, "tape", req.Tape.ID, "action", req.Action, "dry_run", req.DryRun, "command", quoteCommand(append([]string{plan.Binary}, plan.Args...)...))
+
+	in, out := r.broadcast(plan)
+	go r.execute(ctx, plan, record, in)
+	return out, nil
 }
 
 func (r *Runner) BuildPlan(req Request) (*CommandPlan, *RunRecord, error) {
@@ -143,12 +283,9 @@ func (r *Runner) BuildPlan(req Request) (*CommandPlan, *RunRecord, error) {
 		return nil, nil, errors.New("missing tape")
 	}
 	if req.Action == ActionPreview && !req.Tape.Preview.Enabled {
-		return nil, nil, fmt.Errorf("tape %q has no preview configured", req.Tape.ID)
+		return nil, nil, hint.Wrap(fmt.Errorf("tape %q: %w", req.Tape.ID, ErrNoPreview), hintNoPreview)
 	}
 
-	ts := r.nowFn()
-	runID := r.nextRunID(req.Tape.ID, ts)
-
 	manifestPath, err := config.ResolveManifestPath(req.Config.ProjectRoot, req.Tape.Manifest)
 	if err != nil {
 		return nil, nil, fmt.Errorf("resolve manifest path: %w", err)
@@ -157,13 +294,28 @@ func (r *Runner) BuildPlan(req Request) (*CommandPlan, *RunRecord, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("resolve output dir: %w", err)
 	}
+	if _, _, err := buildArgs(req.Tape, req.Action, manifestPath, outputDir, "", req.Config.OutputFlag); err != nil {
+		return nil, nil, err
+	}
 
-	args, outputPaths, err := buildArgs(req.Tape, req.Action, manifestPath, outputDir, runID, req.Config.OutputFlag)
+	ts := r.nowFn()
+	// An id whose generated output already exists belongs to an earlier
+	// run, even if that run left no record.
+	runID, claimPath, err := r.reserveRunID(req.Config.RunsDir, req.Tape.ID, ts, func(id string) bool {
+		_, outputs, _ := buildArgs(req.Tape, req.Action, manifestPath, outputDir, id, req.Config.OutputFlag)
+		for _, path := range outputs {
+			if _, err := os.Stat(path); err == nil {
+				return true
+			}
+		}
+		return false
+	})
 	if err != nil {
 		return nil, nil, err
 	}
+	args, outputPaths, _ := buildArgs(req.Tape, req.Action, manifestPath, outputDir, runID, req.Config.OutputFlag)
 
-	recordPath := filepath.Join(req.Config.RunsDir, "records", runID+".json")
+	recordPath := RecordPath(req.Config.RunsDir, runID)
 	plan := &CommandPlan{
 		RunID:        runID,
 		Timestamp:    ts,
@@ -177,6 +329,26 @@ func (r *Runner) BuildPlan(req Request) (*CommandPlan, *RunRecord, error) {
 		Action:       req.Action,
 		DryRun:       req.DryRun,
 		RecordPath:   recordPath,
+		ClaimPath:    claimPath,
+		StallAfter:   req.Config.Watchdog.StallAfter(),
+		KillAfter:    time.Duration(req.Config.Watchdog.KillSeconds) * time.Second,
+		LineBuffer:   req.Config.Logs.LineBufferKB * 1024,
+		RequireAlpha: req.Tape.RequiresAlpha,
+	}
+	if u := req.Tape.Upload; u.Enabled() && req.Action == ActionPrimary && !req.SkipUpload {
+		plan.Upload = &UploadStep{
+			Command: append([]string(nil), u.Command...),
+			URL:     u.URL,
+			TapeID:  req.Tape.ID,
+		}
+	}
+	if b := req.Config.Build; b.Enabled() {
+		plan.Build = &BuildStep{
+			Command:   append([]string(nil), b.Command...),
+			Dir:       b.Dir,
+			Watch:     append([]string(nil), b.Watch...),
+			StampPath: BuildStampPath(req.Config.RunsDir),
+		}
 	}
 
 	record := &RunRecord{
@@ -192,11 +364,81 @@ func (r *Runner) BuildPlan(req Request) (*CommandPlan, *RunRecord, error) {
 		OutputPaths:  append([]string(nil), outputPaths...),
 		Action:       req.Action,
 		DryRun:       req.DryRun,
+		Trigger:      req.Trigger,
 	}
 
 	return plan, record, nil
 }
 
+// CommandRequest runs an arbitrary command with the same cancellation,
+// watchdog and run record handling as a render.
+type CommandRequest struct {
+	Config *config.Config
+	// Name stands in for the tape id in the run id and record.
+	Name    string
+	Command []string
+	Env     map[string]string
+	DryRun  bool
+	Trigger string
+}
+
+func (r *Runner) StartCommand(ctx context.Context, req CommandRequest) (<-chan Event, error) {
+	if req.Config == nil {
+		return nil, errors.New("missing config")
+	}
+	if len(req.Command) == 0 {
+		return nil, errors.New("missing command")
+	}
+
+	ts := r.nowFn()
+	runID, claimPath, err := r.reserveRunID(req.Config.RunsDir, req.Name, ts, nil)
+	if err != nil {
+		return nil, err
+	}
+	env := cloneMap(req.Config.Env)
+	if env == nil {
+		env = map[string]string{}
+	}
+	for k, v := range req.Env {
+		env[k] = v
+	}
+	plan := &CommandPlan{
+		RunID:        runID,
+		Timestamp:    ts,
+		Binary:       req.Command[0],
+		Args:         append([]string(nil), req.Command[1:]...),
+		CWD:          req.Config.ProjectRoot,
+		EnvOverrides: env,
+		OutputDir:    req.Config.ProjectRoot,
+		Action:       ActionCommand,
+		DryRun:       req.DryRun,
+		RecordPath:   RecordPath(req.Config.RunsDir, runID),
+		ClaimPath:    claimPath,
+		StallAfter:   req.Config.Watchdog.StallAfter(),
+		KillAfter:    time.Duration(req.Config.Watchdog.KillSeconds) * time.Second,
+		LineBuffer:   req.Config.Logs.LineBufferKB * 1024,
+	}
+	record := &RunRecord{
+		Timestamp:    ts,
+		RunID:        runID,
+		TapeID:       req.Name,
+		TapeName:     req.Name,
+		Command:      append([]string(nil), req.Command...),
+		CWD:          plan.CWD,
+		EnvOverrides: cloneMap(env),
+		ExitCode:     -1,
+		OutputPaths:  []string{},
+		Action:       ActionCommand,
+		DryRun:       req.DryRun,
+		Trigger:      req.Trigger,
+	}
+
+	r.log.Info("command started", "run_id", runID, "name", req.Name, "dry_run", req.DryRun, "command", quoteCommand(req.Command...))
+	in, out := r.broadcast(plan)
+	go r.execute(ctx, plan, record, in)
+	return out, nil
+}
+
 func buildArgs(tape config.Tape, action Action, manifestPath, outputDir, runID, outputFlag string) ([]string, []string, error) {
 	var args []string
 	var extra []string
@@ -311,17 +553,22 @@ func hasOutputFlag(args []string, outputFlag string) bool {
 
 func (r *Runner) execute(ctx context.Context, plan *CommandPlan, record *RunRecord, events chan<- Event) {
 	defer close(events)
+	defer releaseRunID(plan.ClaimPath)
+	ctx, span := startRunSpan(ctx, plan, record)
+	defer endRunSpan(span, record)
 
-	events <- Event{Type: EventStarted, Message: shellQuote(append([]string{plan.Binary}, plan.Args...)...), Plan: plan, Record: record}
+	events <- Event{Type: EventStarted, Message: quoteCommand(append([]string{plan.Binary}, plan.Args...)...), Plan: plan, Record: record}
 
 	if err := os.MkdirAll(plan.OutputDir, 0o755); err != nil {
 		record.ExitCode = 1
+		record.Status = StatusFailed
 		recordErr := WriteRunRecord(plan.RecordPath, record)
 		events <- Event{Type: EventFinished, Message: fmt.Sprintf("create output dir: %v", err), ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
 		return
 	}
 	if err := os.MkdirAll(filepath.Dir(plan.RecordPath), 0o755); err != nil {
 		record.ExitCode = 1
+		record.Status = StatusFailed
 		recordErr := WriteRunRecord(plan.RecordPath, record)
 		events <- Event{Type: EventFinished, Message: fmt.Sprintf("create record dir: %v", err), ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
 		return
@@ -329,77 +576,380 @@ func (r *Runner) execute(ctx context.Context, plan *CommandPlan, record *RunReco
 
 	if plan.DryRun {
 		record.ExitCode = 0
+		record.Status = StatusSuccess
 		recordErr := WriteRunRecord(plan.RecordPath, record)
+		if plan.Build != nil {
+			events <- Event{Type: EventLog, Message: "[dry-run] build not executed: " + quoteCommand(plan.Build.Command...)}
+		}
 		events <- Event{Type: EventLog, Message: "[dry-run] command not executed"}
+		if plan.Upload != nil {
+			events <- Event{Type: EventLog, Message: "[dry-run] upload not executed: " + quoteCommand(plan.Upload.Command...)}
+		}
 		events <- Event{Type: EventFinished, Message: "dry run complete", ExitCode: 0, Record: record, RecordErr: recordErr}
 		return
 	}
 
-	cmd := exec.CommandContext(ctx, plan.Binary, plan.Args...)
+	if plan.Build != nil {
+		buildCtx, buildSpan := trace.Start(ctx, "build")
+		err := r.runBuild(buildCtx, plan.Build, record, events)
+		endSpan(buildSpan, err)
+		if err != nil {
+			record.ExitCode = 1
+			if record.Build.ExitCode > 0 {
+				record.ExitCode = record.Build.ExitCode
+			}
+			record.Status = StatusFailed
+			msg := err.Error()
+			if errors.Is(ctx.Err(), context.Canceled) {
+				record.Status = StatusCanceled
+				msg = "run canceled during build"
+			}
+			recordErr := WriteRunRecord(plan.RecordPath, record)
+			events <- Event{Type: EventFinished, Message: msg, ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
+			return
+		}
+	}
+	if plan.MinVersion != "" {
+		if err := r.detectFeatures(ctx, plan.Binary, plan.CWD).CheckMinVersion(plan.MinVersion); err != nil {
+			record.ExitCode = 1
+			record.Status = StatusFailed
+			recordErr := WriteRunRecord(plan.RecordPath, record)
+			events <- Event{Type: EventFinished, Message: err.Error(), Hint: hint.Of(err), ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
+			return
+		}
+	}
+
+	runCtx, killRun := context.WithCancel(ctx)
+	defer killRun()
+
+	cmd := exec.CommandContext(runCtx, plan.Binary, plan.Args...)
 	cmd.Dir = plan.CWD
 	cmd.Env = mergeEnv(os.Environ(), plan.EnvOverrides)
+	configureProcess(cmd)
 
-	stdout, err := cmd.StdoutPipe()
+	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		record.ExitCode = 1
+		record.Status = StatusFailed
 		recordErr := WriteRunRecord(plan.RecordPath, record)
-		events <- Event{Type: EventFinished, Message: fmt.Sprintf("stdout pipe: %v", err), ExitCode: 1, Record: record, RecordErr: recordErr}
+		events <- Event{Type: EventFinished, Message: fmt.Sprintf("stdin pipe: %v", err), ExitCode: 1, Record: record, RecordErr: recordErr}
 		return
 	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		record.ExitCode = 1
-		recordErr := WriteRunRecord(plan.RecordPath, record)
-		events <- Event{Type: EventFinished, Message: fmt.Sprintf("stderr pipe: %v", err), ExitCode: 1, Record: record, RecordErr: recordErr}
-		return
+	var exited atomic.Bool
+	cmd.Cancel = func() error {
+		err := requestCancel(cmd, stdin)
+		time.AfterFunc(CancelGrace, func() {
+			if !exited.Load() {
+				_ = killProcessTree(cmd)
+			}
+		})
+		return err
 	}
+	cmd.WaitDelay = CancelGrace
+
+	// exec copies output into these pipes and Wait returns only once the
+	// copies finish (bounded by WaitDelay), so no trailing lines are lost.
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
 
+	_, execSpan := trace.Start(ctx, "exec", trace.String("binary", plan.Binary))
+	startedAt := time.Now()
 	if err := cmd.Start(); err != nil {
+		endSpan(execSpan, err)
+		stdoutW.Close()
+		stderrW.Close()
 		record.ExitCode = exitCodeFromError(err)
+		record.Status = StatusFailed
+		if plan.Action != ActionCommand && (errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist)) {
+			err = hint.Wrap(fmt.Errorf("%w: %s", ErrBinaryNotFound, plan.Binary), hintBinaryNotFound)
+		}
 		recordErr := WriteRunRecord(plan.RecordPath, record)
-		events <- Event{Type: EventFinished, Message: fmt.Sprintf("start command: %v", err), ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
+		events <- Event{Type: EventFinished, Message: fmt.Sprintf("start command: %v", err), Hint: hint.Of(err), ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
 		return
 	}
 
+	activity := newActivityClock()
+	watchdogDone := make(chan struct{})
+	var stalledKill atomic.Bool
+	var watchdogWG sync.WaitGroup
+	watchdogWG.Add(1)
+	go func() {
+		defer watchdogWG.Done()
+		watch(plan, activity, events, watchdogDone, func() {
+			stalledKill.Store(true)
+			killRun()
+		})
+	}()
+
+	tracker := progress.NewTracker(progress.RenderWeights, progress.AgentWeights)
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		scanPipe("out", stdout, events)
+		scanPipe("out", stdout, plan.LineBuffer, events, activity, tracker)
 	}()
 	go func() {
 		defer wg.Done()
-		scanPipe("err", stderr, events)
+		scanPipe("err", stderr, plan.LineBuffer, events, activity, tracker)
 	}()
 
 	waitErr := cmd.Wait()
+	exited.Store(true)
+	stdoutW.Close()
+	stderrW.Close()
 	wg.Wait()
+	stdin.Close()
+	close(watchdogDone)
+	watchdogWG.Wait()
+
+	execSpan.Set(trace.Int("exit_code", exitCodeFromError(waitErr)))
+	endSpan(execSpan, waitErr)
 
 	exitCode := exitCodeFromError(waitErr)
 	record.ExitCode = exitCode
-	recordErr := WriteRunRecord(plan.RecordPath, record)
+	record.DurationMS = time.Since(startedAt).Milliseconds()
+	record.Status = StatusSuccess
 
 	msg := "run complete"
+	msgHint := ""
 	if waitErr != nil {
-		if errors.Is(ctx.Err(), context.Canceled) {
+		record.Status = StatusFailed
+		if stalledKill.Load() {
+			msg = fmt.Sprintf("run killed: no output for %s", plan.KillAfter)
+		} else if errors.Is(ctx.Err(), context.Canceled) {
 			msg = "run canceled"
+			record.Status = StatusCanceled
 		} else {
 			msg = waitErr.Error()
+			// Full commands in primary_args may not read the manifest, so
+			// a missing one only explains a failure after the fact.
+			if plan.ManifestPath != "" {
+				if _, err := os.Stat(plan.ManifestPath); errors.Is(err, fs.ErrNotExist) {
+					msg = fmt.Sprintf("%s (%v: %s)", msg, ErrManifestMissing, plan.ManifestPath)
+					msgHint = hintManifestMissing
+				}
+			}
+		}
+	}
+	if waitErr == nil && plan.RequireAlpha {
+		_, alphaSpan := trace.Start(ctx, "verify_alpha")
+		err := r.verifyAlpha(ctx, plan.OutputPaths)
+		endSpan(alphaSpan, err)
+		if err != nil {
+			exitCode = 1
+			record.ExitCode = exitCode
+			record.Status = StatusFailed
+			msg = err.Error()
+			msgHint = hint.Of(err)
+		} else {
+			events <- Event{Type: EventLog, Message: "[alpha] output has an alpha channel"}
+		}
+	}
+	if record.Status == StatusSuccess && plan.Upload != nil {
+		uploadCtx, uploadSpan := trace.Start(ctx, "upload")
+		err := r.runUpload(uploadCtx, plan.Upload, plan, record, events)
+		endSpan(uploadSpan, err)
+		if err != nil {
+			exitCode = 1
+			record.ExitCode = exitCode
+			record.Status = StatusFailed
+			msg = err.Error()
+			if errors.Is(ctx.Err(), context.Canceled) {
+				record.Status = StatusCanceled
+				msg = "run canceled during upload"
+			}
+		}
+	}
+	recordErr := WriteRunRecord(plan.RecordPath, record)
+
+	events <- Event{Type: EventFinished, Message: msg, Hint: msgHint, ExitCode: exitCode, Record: record, RecordErr: recordErr}
+}
+
+// startRunSpan opens the span covering a run, under the pipeline step or
+// schedule span in ctx when there is one.
+func startRunSpan(ctx context.Context, plan *CommandPlan, record *RunRecord) (context.Context, *trace.Span) {
+	attrs := []trace.Attr{
+		trace.String("run_id", plan.RunID),
+		trace.String("tape", record.TapeID),
+		trace.String("action", string(plan.Action)),
+		trace.Bool("dry_run", plan.DryRun),
+	}
+	if record.Trigger != "" {
+		attrs = append(attrs, trace.String("trigger", record.Trigger))
+	}
+	return trace.Start(ctx, "run "+string(plan.Action), attrs...)
+}
+
+func endRunSpan(span *trace.Span, record *RunRecord) {
+	span.Set(trace.String("status", string(record.Status)), trace.Int("exit_code", record.ExitCode))
+	if record.Status == StatusFailed || record.Status == StatusAborted {
+		span.Fail(fmt.Sprintf("run %s with exit code %d", record.Status, record.ExitCode))
+	}
+	span.End()
+}
+
+func endSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.Fail(err.Error())
+	}
+	span.End()
+}
+
+// verifyAlpha checks every output of a requires_alpha tape with ffprobe.
+func (r *Runner) verifyAlpha(ctx context.Context, outputs []string) error {
+	if len(outputs) == 0 {
+		return errors.New("requires_alpha: no output path to verify (custom output args?)")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for _, path := range outputs {
+		info, err := r.probe(ctx, path)
+		if errors.Is(err, probe.ErrNotInstalled) {
+			return hint.Wrap(fmt.Errorf("requires_alpha: %w, cannot verify the alpha channel", ErrProbeMissing), hintProbeMissing)
+		}
+		if err != nil {
+			return fmt.Errorf("requires_alpha: %w", err)
+		}
+		if !info.HasAlpha {
+			return hint.Wrap(fmt.Errorf("requires_alpha: %s: %w (codec %s, pix_fmt %s)", filepath.Base(path), ErrNoAlpha, info.Codec, info.PixFmt), hintNoAlpha)
 		}
 	}
+	return nil
+}
 
-	events <- Event{Type: EventFinished, Message: msg, ExitCode: exitCode, Record: record, RecordErr: recordErr}
+// CancelGrace is how long a canceled run may take to shut down after the
+// cancel request before its process tree is killed.
+var CancelGrace = 5 * time.Second
+
+// requestCancel asks the child to stop: it writes a "cancel" line to stdin
+// and closes it (the skill protocol signal), then interrupts the process
+// group. If the child ignores both, exec's WaitDelay kills it.
+func requestCancel(cmd *exec.Cmd, stdin io.WriteCloser) error {
+	_, _ = io.WriteString(stdin, "cancel\n")
+	_ = stdin.Close()
+	if err := interruptProcess(cmd); err != nil {
+		return killProcessTree(cmd)
+	}
+	return nil
 }
 
-func scanPipe(stream string, r io.Reader, events chan<- Event) {
-	scanner := bufio.NewScanner(r)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-	for scanner.Scan() {
-		events <- Event{Type: EventLog, Message: fmt.Sprintf("[%s] %s", stream, scanner.Text())}
+// watch reports a stall once per silent period and invokes kill when the
+// silence exceeds plan.KillAfter. It returns when done is closed.
+func watch(plan *CommandPlan, activity *activityClock, events chan<- Event, done <-chan struct{}, kill func()) {
+	if plan.StallAfter <= 0 && plan.KillAfter <= 0 {
+		return
 	}
-	if err := scanner.Err(); err != nil {
-		events <- Event{Type: EventLog, Message: fmt.Sprintf("[%s] scan error: %v", stream, err)}
+
+	ticker := time.NewTicker(watchdogInterval(plan))
+	defer ticker.Stop()
+
+	reported := false
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		idle, fresh := activity.idle()
+		if fresh {
+			reported = false
+		}
+		if plan.KillAfter > 0 && idle >= plan.KillAfter {
+			events <- Event{Type: EventStalled, Message: fmt.Sprintf("no output for %s, killing process", plan.KillAfter)}
+			kill()
+			return
+		}
+		if plan.StallAfter > 0 && idle >= plan.StallAfter && !reported {
+			reported = true
+			events <- Event{Type: EventStalled, Message: fmt.Sprintf("no output for %s", idle.Truncate(time.Second))}
+		}
+	}
+}
+
+func watchdogInterval(plan *CommandPlan) time.Duration {
+	shortest := plan.StallAfter
+	if shortest <= 0 || (plan.KillAfter > 0 && plan.KillAfter < shortest) {
+		shortest = plan.KillAfter
+	}
+	interval := shortest / 4
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	return interval
+}
+
+type activityClock struct {
+	mu      sync.Mutex
+	last    time.Time
+	touched bool
+}
+
+func newActivityClock() *activityClock {
+	return &activityClock{last: time.Now()}
+}
+
+func (c *activityClock) touch() {
+	c.mu.Lock()
+	c.last = time.Now()
+	c.touched = true
+	c.mu.Unlock()
+}
+
+// idle returns how long the process has been silent and whether any output
+// arrived since the previous call.
+func (c *activityClock) idle() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fresh := c.touched
+	c.touched = false
+	return time.Since(c.last), fresh
+}
+
+// DefaultLineBuffer is the longest log line emitted whole when a plan does
+// not set LineBuffer.
+const DefaultLineBuffer = config.DefaultLineBufferKB * 1024
+
+// scanPipe emits one log event per line. A line longer than lineBuffer is
+// emitted in chunks of that size, each but the last ending in "…", so
+// megabytes of output on one line cannot exhaust memory or be dropped.
+// Chunked lines are not parsed for progress.
+func scanPipe(stream string, r io.Reader, lineBuffer int, events chan<- Event, activity *activityClock, tracker *progress.Tracker) {
+	if lineBuffer <= 0 {
+		lineBuffer = DefaultLineBuffer
+	}
+	reader := bufio.NewReaderSize(r, lineBuffer)
+	chunked := false
+	for {
+		chunk, more, err := reader.ReadLine()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				events <- Event{Type: EventLog, Message: fmt.Sprintf("[%s] read error: %v", stream, err)}
+				_, _ = io.Copy(io.Discard, r)
+			}
+			return
+		}
+		activity.touch()
+		line := string(chunk)
+		if more {
+			events <- Event{Type: EventLog, Message: fmt.Sprintf("[%s] %s…", stream, line)}
+			chunked = true
+			continue
+		}
+		events <- Event{Type: EventLog, Message: fmt.Sprintf("[%s] %s", stream, line)}
+		if chunked {
+			chunked = false
+			continue
+		}
+		if sample, ok := progress.ParseLine(line); ok {
+			snap := tracker.Observe(sample)
+			events <- Event{Type: EventProgress, Progress: &snap}
+		}
 	}
 }
 
@@ -423,6 +973,15 @@ func sanitizeID(v string) string {
 	return v
 }
 
+// quoteCommand renders a command line for display and logs using the quoting
+// conventions of the host shell.
+func quoteCommand(parts ...string) string {
+	if runtime.GOOS == "windows" {
+		return windowsQuote(parts...)
+	}
+	return shellQuote(parts...)
+}
+
 func shellQuote(parts ...string) string {
 	q := make([]string, 0, len(parts))
 	for _, p := range parts {
@@ -431,7 +990,7 @@ func shellQuote(parts ...string) string {
 			continue
 		}
 		if strings.IndexFunc(p, func(r rune) bool {
-			return r == ' ' || r == '\t' || r == '\n' || r == '\'' || r == '"'
+			return !isShellSafe(r)
 		}) >= 0 {
 			replaced := strings.ReplaceAll(p, "'", "'\\''")
 			q = append(q, "'"+replaced+"'")
@@ -442,23 +1001,103 @@ func shellQuote(parts ...string) string {
 	return strings.Join(q, " ")
 }
 
+func isShellSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_@%+=:,./-", r)
+}
+
+// windowsQuote follows the CommandLineToArgvW rules: arguments with spaces or
+// quotes are wrapped in double quotes, embedded quotes are escaped, and
+// backslashes are doubled only where they precede a quote.
+func windowsQuote(parts ...string) string {
+	q := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			q = append(q, `""`)
+			continue
+		}
+		if !strings.ContainsAny(p, " \t\n\"") {
+			q = append(q, p)
+			continue
+		}
+		var b strings.Builder
+		b.WriteByte('"')
+		slashes := 0
+		for _, r := range p {
+			switch r {
+			case '\\':
+				slashes++
+				continue
+			case '"':
+				b.WriteString(strings.Repeat(`\`, slashes*2+1))
+				b.WriteRune(r)
+			default:
+				b.WriteString(strings.Repeat(`\`, slashes))
+				b.WriteRune(r)
+			}
+			slashes = 0
+		}
+		b.WriteString(strings.Repeat(`\`, slashes*2))
+		b.WriteByte('"')
+		q = append(q, b.String())
+	}
+	return strings.Join(q, " ")
+}
+
 func mergeEnv(base []string, overrides map[string]string) []string {
 	if len(overrides) == 0 {
 		return base
 	}
-	kv := make(map[string]string, len(base)+len(overrides))
+	return mergeEnvFor(runtime.GOOS, base, overrides)
+}
+
+// mergeEnvFor overlays overrides on base. Windows variable names are
+// case-insensitive and may start with '=' (per-drive cwd entries), so they
+// are matched accordingly there.
+func mergeEnvFor(goos string, base []string, overrides map[string]string) []string {
+	keyOf := func(k string) string { return k }
+	if goos == "windows" {
+		keyOf = strings.ToUpper
+	}
+
+	out := make([]string, 0, len(base)+len(overrides))
+	index := make(map[string]int, len(base)+len(overrides))
 	for _, entry := range base {
-		if idx := strings.Index(entry, "="); idx >= 0 {
-			kv[entry[:idx]] = entry[idx+1:]
+		idx := strings.Index(entry, "=")
+		if goos == "windows" && idx == 0 {
+			if next := strings.Index(entry[1:], "="); next >= 0 {
+				idx = next + 1
+			}
 		}
-	}
-	for key, value := range overrides {
-		kv[key] = value
+		if idx <= 0 {
+			out = append(out, entry)
+			continue
+		}
+		key := keyOf(entry[:idx])
+		if i, ok := index[key]; ok {
+			out[i] = entry
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, entry)
 	}
 
-	out := make([]string, 0, len(kv))
-	for key, value := range kv {
-		out = append(out, key+"="+value)
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := key + "=" + overrides[key]
+		if i, ok := index[keyOf(key)]; ok {
+			out[i] = entry
+			continue
+		}
+		index[keyOf(key)] = len(out)
+		out = append(out, entry)
 	}
 	return out
 }
@@ -1,12 +1,19 @@
 package runner
 
 import (
+	"context"
+	"errors"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
-	"vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
 )
 
 func TestBuildPlanPrimaryDefaults(t *testing.T) {
@@ -188,6 +195,44 @@ func TestBuildPlanRunIDCounterIncrements(t *testing.T) {
 	}
 }
 
+func TestStartRefusesVCROlderThanMinVersion(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	if err := os.MkdirAll(cfg.ProjectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	cfg.VCRBinary = filepath.Join(t.TempDir(), "vcr")
+	if err := os.WriteFile(cfg.VCRBinary, []byte("#!/bin/sh\necho 'vcr 0.1.2'\n"), 0o755); err != nil {
+		t.Fatalf("write fake vcr: %v", err)
+	}
+	cfg.VCRMinVersion = "0.2.0"
+
+	r := New(nil)
+	_, err := r.Start(context.Background(), Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary})
+	if !errors.Is(err, ErrVCROutdated) || !strings.Contains(err.Error(), "pinned 0.2.0") || hint.Of(err) == "" {
+		t.Fatalf("expected min version refusal, got %v", err)
+	}
+
+	events, err := r.Start(context.Background(), Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run should not be refused: %v", err)
+	}
+	for range events {
+	}
+
+	if err := (FeatureInfo{Version: "0.2.1"}).CheckMinVersion("0.2.0"); err != nil {
+		t.Fatalf("newer vcr refused: %v", err)
+	}
+	if err := (FeatureInfo{}).CheckMinVersion("0.2.0"); err != nil {
+		t.Fatalf("unknown version refused: %v", err)
+	}
+}
+
 func testConfig(t *testing.T) *config.Config {
 	t.Helper()
 	tmp := t.TempDir()
@@ -252,3 +297,324 @@ func countMatches(items []string, target string) int {
 	}
 	return count
 }
+
+func TestExecuteWatchdogKillsSilentProcess(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	plan := &CommandPlan{
+		RunID:      "stall",
+		Binary:     "sh",
+		Args:       []string{"-c", "echo start; sleep 5"},
+		CWD:        tmp,
+		OutputDir:  filepath.Join(tmp, "out"),
+		RecordPath: filepath.Join(tmp, "records", "stall.json"),
+		StallAfter: 50 * time.Millisecond,
+		KillAfter:  300 * time.Millisecond,
+	}
+	record := &RunRecord{RunID: plan.RunID, ExitCode: -1}
+
+	events := make(chan Event, 128)
+	start := time.Now()
+	go New(nil).execute(context.Background(), plan, record, events)
+
+	var stalled int
+	var finished Event
+	for event := range events {
+		switch event.Type {
+		case EventStalled:
+			stalled++
+		case EventFinished:
+			finished = event
+		}
+	}
+
+	if stalled == 0 {
+		t.Fatalf("expected at least one stalled event")
+	}
+	if finished.ExitCode == 0 || !strings.Contains(finished.Message, "no output") {
+		t.Fatalf("expected watchdog kill, got exit=%d msg=%q", finished.ExitCode, finished.Message)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("watchdog did not kill process promptly: %s", elapsed)
+	}
+}
+
+func TestExecuteCancelInterruptsGracefully(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh and POSIX signals")
+	}
+
+	tmp := t.TempDir()
+	plan := &CommandPlan{
+		RunID:      "cancel",
+		Binary:     "sh",
+		Args:       []string{"-c", `trap 'echo child canceled; exit 130' INT; echo ready; while true; do sleep 0.05; done`},
+		CWD:        tmp,
+		OutputDir:  filepath.Join(tmp, "out"),
+		RecordPath: filepath.Join(tmp, "records", "cancel.json"),
+	}
+	record := &RunRecord{RunID: plan.RunID, ExitCode: -1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 128)
+	go New(nil).execute(ctx, plan, record, events)
+
+	var logs []string
+	var finished Event
+	for event := range events {
+		switch event.Type {
+		case EventLog:
+			logs = append(logs, event.Message)
+			if strings.HasSuffix(event.Message, "ready") {
+				cancel()
+			}
+		case EventFinished:
+			finished = event
+		}
+	}
+
+	if !contains(logs, "[out] child canceled") {
+		t.Fatalf("expected child to handle interrupt, logs: %v", logs)
+	}
+	if finished.Message != "run canceled" || record.Status != StatusCanceled {
+		t.Fatalf("unexpected finish: msg=%q status=%s", finished.Message, record.Status)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	got := shellQuote("vcr", "render", "my tape.yaml", "it's", "", "$HOME")
+	want := `vcr render 'my tape.yaml' 'it'\''s' '' '$HOME'`
+	if got != want {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+}
+
+func TestWindowsQuote(t *testing.T) {
+	t.Parallel()
+
+	got := windowsQuote(`C:\Program Files\vcr.exe`, "render", `say "hi"`, `C:\out dir\`, "")
+	want := `"C:\Program Files\vcr.exe" render "say \"hi\"" "C:\out dir\\" ""`
+	if got != want {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+}
+
+func TestMergeEnvWindowsIsCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	base := []string{"=C:=C:\\deck", "Path=C:\\Windows", "TEMP=C:\\tmp"}
+	got := mergeEnvFor("windows", base, map[string]string{"PATH": `C:\vcr`, "VCR_MODE": "1"})
+	want := []string{"=C:=C:\\deck", `PATH=C:\vcr`, "TEMP=C:\\tmp", "VCR_MODE=1"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %v got %v", want, got)
+	}
+
+	got = mergeEnvFor("linux", []string{"Path=a"}, map[string]string{"PATH": "b"})
+	if len(got) != 2 {
+		t.Fatalf("expected case-sensitive merge on POSIX, got %v", got)
+	}
+}
+
+func TestExecuteRequiresAlphaFailsWithoutAlpha(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	for _, tc := range []struct {
+		pixFmt string
+		exit   int
+	}{
+		{pixFmt: "yuv420p", exit: 1},
+		{pixFmt: "yuva444p12le", exit: 0},
+	} {
+		tmp := t.TempDir()
+		output := filepath.Join(tmp, "out", "tape.mov")
+		plan := &CommandPlan{
+			RunID:        "alpha",
+			Binary:       "sh",
+			Args:         []string{"-c", "touch " + shellQuote(output)},
+			CWD:          tmp,
+			OutputDir:    filepath.Dir(output),
+			OutputPaths:  []string{output},
+			RecordPath:   filepath.Join(tmp, "records", "alpha.json"),
+			RequireAlpha: true,
+		}
+		record := &RunRecord{RunID: plan.RunID, ExitCode: -1}
+
+		r := New(nil)
+		r.probe = func(_ context.Context, path string) (probe.Info, error) {
+			if path != output {
+				t.Errorf("probed unexpected path %s", path)
+			}
+			return probe.Info{Codec: "prores", PixFmt: tc.pixFmt, HasAlpha: probe.PixFmtHasAlpha(tc.pixFmt)}, nil
+		}
+		events := make(chan Event, 128)
+		go r.execute(context.Background(), plan, record, events)
+
+		var finished Event
+		for event := range events {
+			if event.Type == EventFinished {
+				finished = event
+			}
+		}
+		if finished.ExitCode != tc.exit {
+			t.Fatalf("%s: expected exit %d, got %d (%s)", tc.pixFmt, tc.exit, finished.ExitCode, finished.Message)
+		}
+		if tc.exit != 0 && (record.Status != StatusFailed || !strings.Contains(finished.Message, "no alpha channel")) {
+			t.Fatalf("%s: expected alpha failure, got status=%s msg=%q", tc.pixFmt, record.Status, finished.Message)
+		}
+	}
+}
+
+func TestScanPipeChunksOverlongLines(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 80)
+	input := "short\n" + long + "\nrendered frame 1/2\n"
+	events := make(chan Event, 32)
+	scanPipe("out", strings.NewReader(input), 32, events, newActivityClock(), progress.NewTracker(progress.RenderWeights))
+	close(events)
+
+	var logs []string
+	progressEvents := 0
+	for event := range events {
+		switch event.Type {
+		case EventLog:
+			logs = append(logs, event.Message)
+		case EventProgress:
+			progressEvents++
+		}
+	}
+	want := []string{
+		"[out] short",
+		"[out] " + long[:32] + "…",
+		"[out] " + long[32:64] + "…",
+		"[out] " + long[64:],
+		"[out] rendered frame 1/2",
+	}
+	if strings.Join(logs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected log lines:\n%s", strings.Join(logs, "\n"))
+	}
+	if progressEvents != 1 {
+		t.Fatalf("expected one progress event, got %d", progressEvents)
+	}
+}
+
+func TestSubscribeFollowsActiveRun(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	cfg := &config.Config{ProjectRoot: tmp, RunsDir: filepath.Join(tmp, "runs")}
+	release := filepath.Join(tmp, "release")
+	r := New(nil)
+	events, err := r.StartCommand(context.Background(), CommandRequest{
+		Config:  cfg,
+		Name:    "wait",
+		Command: []string{"sh", "-c", "echo ready; while [ ! -e " + shellQuote(release) + " ]; do sleep 0.05; done; echo done"},
+	})
+	if err != nil {
+		t.Fatalf("StartCommand: %v", err)
+	}
+
+	var runID string
+	for event := range events {
+		if event.Type == EventStarted {
+			runID = event.Plan.RunID
+		}
+		if event.Type == EventLog && event.Message == "[out] ready" {
+			break
+		}
+	}
+	late, cancel, ok := r.Subscribe(runID)
+	if !ok {
+		t.Fatalf("run %s not active", runID)
+	}
+	defer cancel()
+	if err := os.WriteFile(release, nil, 0o644); err != nil {
+		t.Fatalf("write release: %v", err)
+	}
+	go func() {
+		for range events {
+		}
+	}()
+
+	var got []EventType
+	for event := range late {
+		got = append(got, event.Type)
+	}
+	if len(got) == 0 || got[0] != EventStarted || got[len(got)-1] != EventFinished {
+		t.Fatalf("expected replay from start through finish, got %v", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, _, ok := r.Subscribe(runID); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run %s still active after finishing", runID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExecuteFailuresCarryHints(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	for _, tc := range []struct {
+		name   string
+		binary string
+		args   []string
+		want   error
+		hint   string
+	}{
+		{name: "missing binary", binary: filepath.Join(tmp, "no-such-vcr"), want: ErrBinaryNotFound, hint: hintBinaryNotFound},
+		{name: "missing manifest", binary: "sh", args: []string{"-c", "exit 2"}, want: ErrManifestMissing, hint: hintManifestMissing},
+	} {
+		plan := &CommandPlan{
+			RunID:        "alpha",
+			Binary:       tc.binary,
+			Args:         tc.args,
+			CWD:          tmp,
+			ManifestPath: filepath.Join(tmp, "manifests", "missing.yaml"),
+			OutputDir:    filepath.Join(tmp, "out"),
+			Action:       ActionPrimary,
+			RecordPath:   filepath.Join(tmp, "records", "alpha.json"),
+		}
+		events := make(chan Event, 128)
+		go New(nil).execute(context.Background(), plan, &RunRecord{RunID: plan.RunID, ExitCode: -1}, events)
+
+		var finished Event
+		for event := range events {
+			if event.Type == EventFinished {
+				finished = event
+			}
+		}
+		if finished.ExitCode == 0 || !strings.Contains(finished.Message, tc.want.Error()) {
+			t.Fatalf("%s: unexpected finish: exit=%d msg=%q", tc.name, finished.ExitCode, finished.Message)
+		}
+		if finished.Hint != tc.hint {
+			t.Fatalf("%s: unexpected hint %q", tc.name, finished.Hint)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import "time"
+
+// TapeStats aggregates the run records of one tape. Dry runs are ignored.
+type TapeStats struct {
+	Runs        int
+	Successes   int
+	TimedRuns   int
+	RenderTime  time.Duration
+	LastOutput  string
+	LastRun     time.Time
+	LastSuccess time.Time
+}
+
+func (s *TapeStats) Add(r RunRecord) {
+	if r.DryRun {
+		return
+	}
+	s.Runs++
+	if r.ExitCode == 0 && r.Status != StatusAborted && r.Status != StatusCanceled {
+		s.Successes++
+		if r.DurationMS > 0 {
+			s.TimedRuns++
+			s.RenderTime += time.Duration(r.DurationMS) * time.Millisecond
+		}
+		if len(r.OutputPaths) > 0 && !r.Timestamp.Before(s.LastSuccess) {
+			s.LastOutput = r.OutputPaths[0]
+			s.LastSuccess = r.Timestamp
+		}
+	}
+	if r.Timestamp.After(s.LastRun) {
+		s.LastRun = r.Timestamp
+	}
+}
+
+func (s TapeStats) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Runs)
+}
+
+// AvgRenderTime averages successful runs that recorded a duration.
+func (s TapeStats) AvgRenderTime() time.Duration {
+	if s.TimedRuns == 0 {
+		return 0
+	}
+	return s.RenderTime / time.Duration(s.TimedRuns)
+}
+
+func Summarize(records []RunRecord) map[string]*TapeStats {
+	out := map[string]*TapeStats{}
+	for _, r := range records {
+		s, ok := out[r.TapeID]
+		if !ok {
+			s = &TapeStats{}
+			out[r.TapeID] = s
+		}
+		s.Add(r)
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	records := []RunRecord{
+		{TapeID: "alpha", Timestamp: base.Add(3 * time.Minute), ExitCode: 1, Status: StatusFailed},
+		{TapeID: "alpha", Timestamp: base, ExitCode: 0, Status: StatusSuccess, DurationMS: 2000, OutputPaths: []string{"a1.mov"}},
+		{TapeID: "alpha", Timestamp: base.Add(time.Minute), ExitCode: 0, DurationMS: 4000, OutputPaths: []string{"a2.mov"}},
+		{TapeID: "alpha", Timestamp: base.Add(2 * time.Minute), ExitCode: 0, Status: StatusCanceled},
+		{TapeID: "alpha", Timestamp: base.Add(4 * time.Minute), DryRun: true},
+		{TapeID: "beta", Timestamp: base, ExitCode: 0},
+	}
+
+	stats := Summarize(records)
+	alpha := stats["alpha"]
+	if alpha.Runs != 4 || alpha.Successes != 2 {
+		t.Fatalf("unexpected counts: %+v", alpha)
+	}
+	if alpha.SuccessRate() != 0.5 {
+		t.Fatalf("unexpected success rate: %v", alpha.SuccessRate())
+	}
+	if alpha.AvgRenderTime() != 3*time.Second {
+		t.Fatalf("unexpected average: %s", alpha.AvgRenderTime())
+	}
+	if alpha.LastOutput != "a2.mov" || !alpha.LastRun.Equal(base.Add(3*time.Minute)) {
+		t.Fatalf("unexpected last output/run: %+v", alpha)
+	}
+	if stats["beta"].AvgRenderTime() != 0 {
+		t.Fatalf("expected no timing for beta")
+	}
+}
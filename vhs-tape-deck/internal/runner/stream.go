@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// startStreamed starts command in dir with stdout and stderr merged and
+// passes each output line to line. Cancelling ctx kills the process tree.
+// The returned wait blocks until the process exits and every line has been
+// passed on; it returns the process's exit error.
+func startStreamed(ctx context.Context, command []string, dir string, line func(string)) (func() error, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	configureProcess(cmd)
+	cmd.Cancel = func() error { return killProcessTree(cmd) }
+	cmd.WaitDelay = CancelGrace
+
+	out, outW := io.Pipe()
+	cmd.Stdout = outW
+	cmd.Stderr = outW
+	if err := cmd.Start(); err != nil {
+		outW.Close()
+		return nil, err
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(out)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line(scanner.Text())
+		}
+		_, _ = io.Copy(io.Discard, out)
+	}()
+	return func() error {
+		waitErr := cmd.Wait()
+		outW.Close()
+		wg.Wait()
+		return waitErr
+	}, nil
+}
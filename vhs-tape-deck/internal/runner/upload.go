@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+)
+
+// UploadStep is the upload a plan runs after a successful render.
+type UploadStep struct {
+	Command []string
+	URL     string
+	TapeID  string
+}
+
+// UploadResult is one output's entry in a run record.
+type UploadResult struct {
+	Path       string `json:"path"`
+	URL        string `json:"url,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// runUpload runs the upload command for each output in turn, streaming its
+// output as [upload] log lines. It stops at the first failure.
+func (r *Runner) runUpload(ctx context.Context, step *UploadStep, plan *CommandPlan, record *RunRecord, events chan<- Event) error {
+	if len(plan.OutputPaths) == 0 {
+		return errors.New("upload: no output path to upload (custom output args?)")
+	}
+	total := len(plan.OutputPaths)
+	for i, path := range plan.OutputPaths {
+		events <- Event{Type: EventProgress, Progress: &progress.Snapshot{
+			Phase: progress.PhaseUpload, PhaseFraction: float64(i) / float64(total), Overall: 1, Done: i, Total: total,
+		}}
+		vars := map[string]string{
+			"output": path,
+			"name":   filepath.Base(path),
+			"tape":   step.TapeID,
+			"run_id": plan.RunID,
+		}
+		result, err := r.uploadOne(ctx, step, plan.CWD, vars, events)
+		result.Path = path
+		if err != nil {
+			result.Error = err.Error()
+		}
+		record.Uploads = append(record.Uploads, result)
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", filepath.Base(path), err)
+		}
+		if result.URL != "" {
+			events <- Event{Type: EventLog, Message: "[upload] " + filepath.Base(path) + " -> " + result.URL}
+		}
+	}
+	events <- Event{Type: EventProgress, Progress: &progress.Snapshot{
+		Phase: progress.PhaseUpload, PhaseFraction: 1, Overall: 1, Done: total, Total: total,
+	}}
+	return nil
+}
+
+func (r *Runner) uploadOne(ctx context.Context, step *UploadStep, dir string, vars map[string]string, events chan<- Event) (UploadResult, error) {
+	command := make([]string, len(step.Command))
+	for i, arg := range step.Command {
+		command[i] = config.ExpandUpload(arg, vars)
+	}
+	events <- Event{Type: EventLog, Message: "[upload] $ " + quoteCommand(command...)}
+	r.log.Info("upload started", "output", vars["output"], "command", quoteCommand(command...))
+
+	var result UploadResult
+	var printed string
+	startedAt := time.Now()
+	wait, err := startStreamed(ctx, command, dir, func(line string) {
+		if remoteURL(line) {
+			printed = strings.TrimSpace(line)
+		}
+		events <- Event{Type: EventLog, Message: "[upload] " + line}
+	})
+	if err != nil {
+		return result, fmt.Errorf("start upload: %w", err)
+	}
+	waitErr := wait()
+
+	result.DurationMS = time.Since(startedAt).Milliseconds()
+	if waitErr != nil {
+		r.log.Warn("upload failed", "output", vars["output"], "err", waitErr)
+		return result, waitErr
+	}
+	result.URL = printed
+	if step.URL != "" {
+		result.URL = config.ExpandUpload(step.URL, vars)
+	}
+	r.log.Info("upload finished", "output", vars["output"], "url", result.URL, "duration_ms", result.DurationMS)
+	return result, nil
+}
+
+// remoteURL reports whether line is a bare URL such as https://host/x or
+// s3://bucket/key.
+func remoteURL(line string) bool {
+	line = strings.TrimSpace(line)
+	if strings.ContainsAny(line, " \t") {
+		return false
+	}
+	u, err := url.Parse(line)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestExecuteUploadsOutputsAfterSuccess(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tmp := t.TempDir()
+	output := filepath.Join(tmp, "out", "alpha.mov")
+
+	run := func(upload *UploadStep) (Event, *RunRecord) {
+		plan := &CommandPlan{
+			RunID:       "alpha_1",
+			Binary:      "sh",
+			Args:        []string{"-c", "echo rendered > " + shellQuote(output)},
+			CWD:         tmp,
+			OutputDir:   filepath.Dir(output),
+			OutputPaths: []string{output},
+			RecordPath:  filepath.Join(tmp, "records", "alpha_1.json"),
+			Upload:      upload,
+		}
+		record := &RunRecord{RunID: plan.RunID, ExitCode: -1}
+		events := make(chan Event, 128)
+		go New(nil).execute(context.Background(), plan, record, events)
+		var finished Event
+		for event := range events {
+			if event.Type == EventFinished {
+				finished = event
+			}
+		}
+		return finished, record
+	}
+
+	finished, record := run(&UploadStep{
+		TapeID:  "alpha",
+		Command: []string{"sh", "-c", `cp "$1" "$2" && echo copying && echo "https://cdn.example/$3/$(basename "$2")"`, "upload", "{{output}}", filepath.Join(tmp, "remote-{{name}}"), "{{tape}}"},
+	})
+	if finished.ExitCode != 0 || record.Status != StatusSuccess {
+		t.Fatalf("expected success, got exit=%d status=%s: %s", finished.ExitCode, record.Status, finished.Message)
+	}
+	if len(record.Uploads) != 1 || record.Uploads[0].URL != "https://cdn.example/alpha/remote-alpha.mov" {
+		t.Fatalf("unexpected uploads: %+v", record.Uploads)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "remote-alpha.mov")); err != nil {
+		t.Fatalf("upload command did not run: %v", err)
+	}
+
+	_, record = run(&UploadStep{TapeID: "alpha", Command: []string{"true"}, URL: "s3://renders/{{run_id}}/{{name}}"})
+	if len(record.Uploads) != 1 || record.Uploads[0].URL != "s3://renders/alpha_1/alpha.mov" {
+		t.Fatalf("expected the url template, got %+v", record.Uploads)
+	}
+
+	finished, record = run(&UploadStep{TapeID: "alpha", Command: []string{"sh", "-c", "echo denied >&2; exit 3"}})
+	if finished.ExitCode == 0 || record.Status != StatusFailed {
+		t.Fatalf("expected a failed upload to fail the run, got exit=%d status=%s", finished.ExitCode, record.Status)
+	}
+	if len(record.Uploads) != 1 || record.Uploads[0].Error == "" {
+		t.Fatalf("expected the upload error in the record, got %+v", record.Uploads)
+	}
+}
+
+func TestRemoteURL(t *testing.T) {
+	t.Parallel()
+
+	for line, want := range map[string]bool{
+		"https://cdn.example/a.mov":            true,
+		"  s3://bucket/key.mov ":               true,
+		"upload: ./a.mov to s3://bucket/a.mov": false,
+		"/local/path.mov":                      false,
+		"100%":                                 false,
+	} {
+		if got := remoteURL(line); got != want {
+			t.Fatalf("remoteURL(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestBuildPlanSkipUpload(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	tape := cfg.Tapes[0]
+	tape.Upload.Command = []string{"true"}
+	for _, skip := range []bool{false, true} {
+		plan, _, err := New(nil).BuildPlan(Request{Config: cfg, Tape: tape, Action: ActionPrimary, SkipUpload: skip})
+		if err != nil {
+			t.Fatalf("BuildPlan: %v", err)
+		}
+		ReleaseClaim(plan)
+		if (plan.Upload == nil) != skip {
+			t.Fatalf("SkipUpload=%v: unexpected upload %+v", skip, plan.Upload)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/golden"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+)
+
+// GoldenResult is one golden frame's entry in a verify run record.
+type GoldenResult struct {
+	Frame     int    `json:"frame"`
+	Reference string `json:"reference"`
+	Output    string `json:"output,omitempty"`
+	// Diff marks the changed pixels; it is written even when the frame
+	// passes.
+	Diff   string  `json:"diff,omitempty"`
+	Score  float64 `json:"score"`
+	Passed bool    `json:"passed"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// verifyFrame is a golden frame with its reference resolved and the plan
+// that renders it.
+type verifyFrame struct {
+	plan      *CommandPlan
+	frame     int
+	reference string
+}
+
+// startVerify renders every golden frame of the tape in turn and compares
+// each one with its reference. Frame renders and diffs go to
+// <output_dir>/<run_id>_verify; the run record lists the results.
+func (r *Runner) startVerify(ctx context.Context, req Request) (<-chan Event, error) {
+	if req.Config == nil {
+		return nil, errors.New("missing config")
+	}
+	if strings.TrimSpace(req.Tape.ID) == "" {
+		return nil, errors.New("missing tape")
+	}
+	if !req.Tape.Golden.Enabled() {
+		return nil, hint.Wrap(fmt.Errorf("tape %q: %w", req.Tape.ID, ErrNoGolden), hintNoGolden)
+	}
+
+	manifestPath, err := config.ResolveManifestPath(req.Config.ProjectRoot, req.Tape.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("resolve manifest path: %w", err)
+	}
+	tapeDir, err := config.ResolvePath(req.Tape.OutputDir, req.Config.ProjectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("resolve output dir: %w", err)
+	}
+	references := make([]string, len(req.Tape.Golden.Frames))
+	for i, f := range req.Tape.Golden.Frames {
+		if references[i], err = config.ResolvePath(f.Reference, req.Config.ProjectRoot); err != nil {
+			return nil, fmt.Errorf("resolve golden reference for frame %d: %w", f.Frame, err)
+		}
+	}
+
+	ts := r.nowFn()
+	runID, claimPath, err := r.reserveRunID(req.Config.RunsDir, req.Tape.ID, ts, func(id string) bool {
+		_, err := os.Stat(filepath.Join(tapeDir, id+"_verify"))
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	outputDir := filepath.Join(tapeDir, runID+"_verify")
+
+	plan := &CommandPlan{
+		RunID:        runID,
+		Timestamp:    ts,
+		Binary:       req.Config.VCRBinary,
+		CWD:          req.Config.ProjectRoot,
+		EnvOverrides: cloneMap(req.Config.Env),
+		ManifestPath: manifestPath,
+		OutputDir:    outputDir,
+		Action:       ActionVerify,
+		DryRun:       req.DryRun,
+		RecordPath:   RecordPath(req.Config.RunsDir, runID),
+		ClaimPath:    claimPath,
+		StallAfter:   req.Config.Watchdog.StallAfter(),
+		KillAfter:    time.Duration(req.Config.Watchdog.KillSeconds) * time.Second,
+		LineBuffer:   req.Config.Logs.LineBufferKB * 1024,
+	}
+
+	frames := make([]verifyFrame, 0, len(req.Tape.Golden.Frames))
+	for i, f := range req.Tape.Golden.Frames {
+		reference := references[i]
+		output := filepath.Join(outputDir, fmt.Sprintf("frame_%04d.png", f.Frame))
+		framePlan := *plan
+		framePlan.Args = verifyArgs(req.Tape, manifestPath, f.Frame, output, req.Config.OutputFlag)
+		framePlan.OutputPaths = []string{output}
+		framePlan.RecordPath = filepath.Join(outputDir, fmt.Sprintf("frame_%04d.json", f.Frame))
+		framePlan.ClaimPath = ""
+		frames = append(frames, verifyFrame{plan: &framePlan, frame: f.Frame, reference: reference})
+		plan.OutputPaths = append(plan.OutputPaths, output)
+	}
+	plan.Args = frames[0].plan.Args
+	// The build runs once, ahead of the first frame.
+	if b := req.Config.Build; b.Enabled() {
+		frames[0].plan.Build = &BuildStep{
+			Command:   append([]string(nil), b.Command...),
+			Dir:       b.Dir,
+			Watch:     append([]string(nil), b.Watch...),
+			StampPath: BuildStampPath(req.Config.RunsDir),
+		}
+		plan.Build = frames[0].plan.Build
+	}
+
+	if req.Config.VCRMinVersion != "" && !req.DryRun {
+		if plan.Build != nil {
+			frames[0].plan.MinVersion = req.Config.VCRMinVersion
+		} else if err := r.DetectFeatures(ctx, req.Config).CheckMinVersion(req.Config.VCRMinVersion); err != nil {
+			releaseRunID(claimPath)
+			return nil, err
+		}
+	}
+
+	record := &RunRecord{
+		Timestamp:    ts,
+		RunID:        runID,
+		TapeID:       req.Tape.ID,
+		TapeName:     req.Tape.Name,
+		ManifestPath: manifestPath,
+		Command:      append([]string{req.Config.VCRBinary}, plan.Args...),
+		CWD:          req.Config.ProjectRoot,
+		EnvOverrides: cloneMap(req.Config.Env),
+		ExitCode:     -1,
+		OutputPaths:  append([]string(nil), plan.OutputPaths...),
+		Action:       ActionVerify,
+		DryRun:       req.DryRun,
+		Trigger:      req.Trigger,
+	}
+
+	r.log.Info("verify started", "run_id", runID, "tape", req.Tape.ID, "frames", len(frames), "dry_run", req.DryRun)
+	in, out := r.broadcast(plan)
+	go r.verify(ctx, plan, frames, req.Tape.Golden.Limit(), record, in)
+	return out, nil
+}
+
+func (r *Runner) verify(ctx context.Context, plan *CommandPlan, frames []verifyFrame, threshold float64, record *RunRecord, events chan<- Event) {
+	defer close(events)
+	defer releaseRunID(plan.ClaimPath)
+	ctx, span := startRunSpan(ctx, plan, record)
+	defer endRunSpan(span, record)
+
+	events <- Event{Type: EventStarted, Message: quoteCommand(append([]string{plan.Binary}, plan.Args...)...), Plan: plan, Record: record}
+
+	startedAt := time.Now()
+	record.ExitCode = 0
+	record.Status = StatusSuccess
+	failed := 0
+	msg, msgHint := "", ""
+	for i, f := range frames {
+		frameRecord := &RunRecord{
+			Timestamp:    record.Timestamp,
+			RunID:        fmt.Sprintf("%s_frame_%04d", record.RunID, f.frame),
+			TapeID:       record.TapeID,
+			TapeName:     record.TapeName,
+			ManifestPath: record.ManifestPath,
+			Command:      append([]string{f.plan.Binary}, f.plan.Args...),
+			CWD:          f.plan.CWD,
+			EnvOverrides: cloneMap(f.plan.EnvOverrides),
+			ExitCode:     -1,
+			OutputPaths:  append([]string(nil), f.plan.OutputPaths...),
+			Action:       ActionPreview,
+			DryRun:       f.plan.DryRun,
+			Trigger:      record.Trigger,
+		}
+		inner := make(chan Event, 128)
+		go r.execute(ctx, f.plan, frameRecord, inner)
+
+		var finished Event
+		for event := range inner {
+			switch event.Type {
+			case EventStarted:
+				events <- Event{Type: EventLog, Message: fmt.Sprintf("[verify] frame %d: $ %s", f.frame, event.Message)}
+			case EventProgress:
+				snap := *event.Progress
+				snap.Overall = (float64(i) + snap.Overall) / float64(len(frames))
+				events <- Event{Type: EventProgress, Progress: &snap}
+			case EventFinished:
+				finished = event
+			default:
+				events <- event
+			}
+		}
+		if frameRecord.Build != nil {
+			record.Build = frameRecord.Build
+		}
+		if finished.ExitCode != 0 {
+			record.ExitCode = finished.ExitCode
+			record.Status = frameRecord.Status
+			msg = fmt.Sprintf("frame %d: %s", f.frame, finished.Message)
+			msgHint = finished.Hint
+			record.Golden = append(record.Golden, GoldenResult{Frame: f.frame, Reference: f.reference, Error: finished.Message})
+			break
+		}
+
+		if plan.DryRun {
+			events <- Event{Type: EventLog, Message: fmt.Sprintf("[dry-run] frame %d not compared with %s", f.frame, f.reference)}
+		} else {
+			result := compareFrame(f, threshold)
+			record.Golden = append(record.Golden, result)
+			if result.Diff != "" {
+				record.Artifacts = append(record.Artifacts, result.Diff)
+			}
+			if !result.Passed {
+				failed++
+			}
+			events <- Event{Type: EventLog, Message: "[verify] " + describeGolden(result, threshold)}
+		}
+		done := float64(i+1) / float64(len(frames))
+		events <- Event{Type: EventProgress, Progress: &progress.Snapshot{Phase: progress.PhaseRender, PhaseFraction: 1, Overall: done, Done: i + 1, Total: len(frames)}}
+	}
+	record.DurationMS = time.Since(startedAt).Milliseconds()
+
+	switch {
+	case msg != "":
+	case failed > 0:
+		record.ExitCode = 1
+		record.Status = StatusFailed
+		msg = fmt.Sprintf("verify failed: %d of %d frames did not match golden", failed, len(frames))
+	case plan.DryRun:
+		msg = "dry run complete"
+	default:
+		msg = fmt.Sprintf("verify passed: %d frames match golden", len(frames))
+	}
+	recordErr := WriteRunRecord(plan.RecordPath, record)
+	events <- Event{Type: EventFinished, Message: msg, Hint: msgHint, ExitCode: record.ExitCode, Record: record, RecordErr: recordErr}
+}
+
+func compareFrame(f verifyFrame, threshold float64) GoldenResult {
+	result := GoldenResult{Frame: f.frame, Reference: f.reference, Output: f.plan.OutputPaths[0]}
+	d, err := golden.Compare(f.reference, result.Output)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Score = d.Score
+	result.Passed = d.Score <= threshold
+	diffPath := strings.TrimSuffix(result.Output, ".png") + "_diff.png"
+	if err := d.WritePNG(diffPath); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Diff = diffPath
+	return result
+}
+
+func describeGolden(result GoldenResult, threshold float64) string {
+	if result.Error != "" {
+		return fmt.Sprintf("frame %d: FAIL: %s", result.Frame, result.Error)
+	}
+	verdict := "pass"
+	if !result.Passed {
+		verdict = "FAIL"
+	}
+	return fmt.Sprintf("frame %d: %s, %.3f%% of pixels differ (threshold %.3f%%), diff %s", result.Frame, verdict, result.Score*100, threshold*100, result.Diff)
+}
+
+// verifyArgs renders one golden frame with render-frame and the tape's
+// preview args, minus any frame or output flags they set.
+func verifyArgs(tape config.Tape, manifestPath string, frame int, outputPath, outputFlag string) []string {
+	outputFlag = strings.TrimSpace(outputFlag)
+	if outputFlag == "" {
+		outputFlag = "--output"
+	}
+	drop := []string{"--frame", outputFlag}
+	if outputFlag == "--output" {
+		drop = append(drop, "-o")
+	}
+
+	args := []string{"render-frame", manifestPath}
+	if !hasSubcommand(tape.Preview.Args) {
+		args = append(args, withoutFlags(tape.Preview.Args, drop)...)
+	}
+	return append(args, "--frame", strconv.Itoa(frame), outputFlag, outputPath)
+}
+
+func withoutFlags(args, flags []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		dropped := false
+		for _, flag := range flags {
+			if args[i] == flag {
+				i++
+				dropped = true
+				break
+			}
+			if strings.HasPrefix(args[i], flag+"=") {
+				dropped = true
+				break
+			}
+		}
+		if !dropped {
+			out = append(out, args[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+func TestStartVerifyComparesGoldenFrames(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cfg := testConfig(t)
+	if err := os.MkdirAll(cfg.ProjectRoot, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	blue := writeSolidPNG(t, filepath.Join(cfg.ProjectRoot, "golden", "blue.png"), color.NRGBA{B: 255, A: 255})
+	writeSolidPNG(t, filepath.Join(cfg.ProjectRoot, "golden", "red.png"), color.NRGBA{R: 255, A: 255})
+
+	// The fake vcr copies blue.png to whatever --output names.
+	cfg.VCRBinary = filepath.Join(t.TempDir(), "vcr")
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = --output ] && out=$2; shift; done\ncp " + shellQuote(blue) + " \"$out\"\n"
+	if err := os.WriteFile(cfg.VCRBinary, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake vcr: %v", err)
+	}
+	tape := cfg.Tapes[0]
+	tape.Preview.Args = []string{"--scale", "1", "--frame", "3"}
+	threshold := 0.01
+	tape.Golden = config.Golden{Threshold: &threshold, Frames: []config.GoldenFrame{
+		{Frame: 0, Reference: "golden/blue.png"},
+		{Frame: 10, Reference: "golden/red.png"},
+	}}
+
+	events, err := New(nil).Start(context.Background(), Request{Config: cfg, Tape: tape, Action: ActionVerify})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	var finished Event
+	for event := range events {
+		if event.Type == EventFinished {
+			finished = event
+		}
+	}
+
+	record := finished.Record
+	if finished.ExitCode != 1 || record.Status != StatusFailed {
+		t.Fatalf("expected failed verify, got exit %d: %s", finished.ExitCode, finished.Message)
+	}
+	if len(record.Golden) != 2 || !record.Golden[0].Passed || record.Golden[1].Passed || record.Golden[1].Score != 1 {
+		t.Fatalf("unexpected golden results: %+v", record.Golden)
+	}
+	if countFrameSpecs(record.Command) != 1 || !contains(record.Command, "--scale") {
+		t.Fatalf("unexpected frame command: %v", record.Command)
+	}
+	for _, diff := range record.Artifacts {
+		if _, err := os.Stat(diff); err != nil {
+			t.Fatalf("diff image missing: %v", err)
+		}
+	}
+	if _, err := ReadRunRecord(RecordPath(cfg.RunsDir, record.RunID)); err != nil {
+		t.Fatalf("verify record not written: %v", err)
+	}
+}
+
+func writeSolidPNG(t *testing.T, path string, c color.NRGBA) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create png: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return path
+}
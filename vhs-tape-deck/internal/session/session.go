@@ -0,0 +1,68 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/atomicfile"
+)
+
+const FileName = "session.json"
+
+// State is the UI state persisted between launches so an interrupted or
+// crashed session can be picked up again.
+type State struct {
+	SavedAt        time.Time `json:"saved_at"`
+	SelectedTapeID string    `json:"selected_tape_id,omitempty"`
+	InsertedTapeID string    `json:"inserted_tape_id,omitempty"`
+	DryRun         bool      `json:"dry_run,omitempty"`
+	ShowDisabled   bool      `json:"show_disabled,omitempty"`
+	ShelfSort      string    `json:"shelf_sort,omitempty"`
+	Crashed        bool      `json:"crashed,omitempty"`
+	// LastSeenVersion is the deck version that last ran, used to decide
+	// whether to show the what's-new overlay.
+	LastSeenVersion string `json:"last_seen_version,omitempty"`
+}
+
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load returns the saved state, or nil when no session has been saved yet.
+func Load(path string) (*State, error) {
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session: %w", err)
+	}
+	var st State
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return nil, fmt.Errorf("parse session: %w", err)
+	}
+	return &st, nil
+}
+
+func Save(path string, st State) error {
+	buf, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+	if err := atomicfile.Write(path, append(buf, '\n')); err != nil {
+		return fmt.Errorf("write session: %w", err)
+	}
+	return nil
+}
+
+// Restorable reports whether st holds anything worth offering to restore.
+func (st *State) Restorable() bool {
+	if st == nil {
+		return false
+	}
+	return st.InsertedTapeID != "" || st.Crashed
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := Path(filepath.Join(t.TempDir(), "deck"))
+	st := State{
+		SavedAt:        time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC),
+		SelectedTapeID: "neon-title",
+		InsertedTapeID: "neon-title",
+		DryRun:         true,
+		Crashed:        true,
+	}
+	if err := Save(path, st); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *loaded != st {
+		t.Fatalf("expected %+v got %+v", st, *loaded)
+	}
+	if !loaded.Restorable() {
+		t.Fatalf("expected inserted tape session to be restorable")
+	}
+}
+
+func TestLoadMissingIsNil(t *testing.T) {
+	t.Parallel()
+
+	st, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if st != nil || st.Restorable() {
+		t.Fatalf("expected nil state, got %+v", st)
+	}
+}
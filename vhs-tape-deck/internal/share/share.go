@@ -0,0 +1,159 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatGIF  Format = "gif"
+	FormatWebP Format = "webp"
+)
+
+type Preset struct {
+	Name  string
+	Width int
+	FPS   int
+}
+
+var Presets = map[string]Preset{
+	"small":  {Name: "small", Width: 320, FPS: 12},
+	"medium": {Name: "medium", Width: 480, FPS: 15},
+	"large":  {Name: "large", Width: 720, FPS: 24},
+}
+
+const DefaultPreset = "medium"
+
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return Presets[names[i]].Width < Presets[names[j]].Width })
+	return names
+}
+
+func ParseFormat(v string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(v))) {
+	case "", FormatGIF:
+		return FormatGIF, nil
+	case FormatWebP:
+		return FormatWebP, nil
+	default:
+		return "", fmt.Errorf("unknown format %q (valid: gif, webp)", v)
+	}
+}
+
+type Options struct {
+	Input  string
+	Output string
+	Format Format
+	Preset Preset
+	// Start and Duration select a time range of the input; zero values mean
+	// the beginning and the rest of the clip.
+	Start    time.Duration
+	Duration time.Duration
+}
+
+// DefaultOutput places the converted file next to the input, e.g.
+// renders/run_001.mov -> renders/run_001_medium.gif.
+func DefaultOutput(input string, format Format, preset Preset) string {
+	stem := strings.TrimSuffix(input, filepath.Ext(input))
+	return fmt.Sprintf("%s_%s.%s", stem, preset.Name, format)
+}
+
+// Args builds the ffmpeg arguments. GIFs use a two-pass palette for small,
+// clean files; progress is written to stdout as key=value lines.
+func Args(opts Options) []string {
+	args := []string{"-y", "-hide_banner", "-loglevel", "error", "-nostats", "-progress", "pipe:1"}
+	if opts.Start > 0 {
+		args = append(args, "-ss", seconds(opts.Start))
+	}
+	if opts.Duration > 0 {
+		args = append(args, "-t", seconds(opts.Duration))
+	}
+	args = append(args, "-i", opts.Input)
+
+	scale := fmt.Sprintf("fps=%d,scale=%d:-1:flags=lanczos", opts.Preset.FPS, opts.Preset.Width)
+	switch opts.Format {
+	case FormatWebP:
+		args = append(args, "-vf", scale, "-c:v", "libwebp", "-quality", "75", "-compression_level", "6", "-loop", "0", "-an")
+	default:
+		args = append(args, "-vf", scale+",split[a][b];[a]palettegen=stats_mode=diff[p];[b][p]paletteuse=dither=bayer:bayer_scale=5", "-loop", "0")
+	}
+	return append(args, opts.Output)
+}
+
+// Convert runs ffmpeg and reports progress in [0,1] when the length of the
+// converted range is known (expected > 0).
+func Convert(ctx context.Context, ffmpeg string, opts Options, expected time.Duration, onProgress func(float64)) error {
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	if opts.Input == "" || opts.Output == "" {
+		return errors.New("input and output are required")
+	}
+	if _, err := os.Stat(opts.Input); err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+	if opts.Duration > 0 {
+		expected = opts.Duration
+	} else if expected > 0 {
+		expected -= opts.Start
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpeg, Args(opts)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || onProgress == nil {
+			continue
+		}
+		switch key {
+		case "out_time_us":
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err == nil && expected > 0 {
+				onProgress(min(1, float64(time.Duration(us)*time.Microsecond)/float64(expected)))
+			}
+		case "progress":
+			if value == "end" {
+				onProgress(1)
+			}
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			lines := strings.Split(msg, "\n")
+			return fmt.Errorf("ffmpeg: %w: %s", err, lines[len(lines)-1])
+		}
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	return nil
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
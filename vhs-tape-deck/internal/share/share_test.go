@@ -0,0 +1,84 @@
+package share
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArgsGIFWithRange(t *testing.T) {
+	t.Parallel()
+
+	args := strings.Join(Args(Options{
+		Input:    "in.mov",
+		Output:   "in_small.gif",
+		Format:   FormatGIF,
+		Preset:   Presets["small"],
+		Start:    1500 * time.Millisecond,
+		Duration: 3 * time.Second,
+	}), " ")
+	for _, want := range []string{"-ss 1.5 -t 3 -i in.mov", "fps=12,scale=320:-1", "palettegen", "-loop 0 in_small.gif"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected %q in %s", want, args)
+		}
+	}
+}
+
+func TestArgsWebP(t *testing.T) {
+	t.Parallel()
+
+	args := strings.Join(Args(Options{Input: "in.mov", Output: "in.webp", Format: FormatWebP, Preset: Presets["large"]}), " ")
+	if strings.Contains(args, "-ss") || strings.Contains(args, "palettegen") {
+		t.Fatalf("unexpected args: %s", args)
+	}
+	if !strings.Contains(args, "-c:v libwebp") || !strings.Contains(args, "fps=24,scale=720:-1") {
+		t.Fatalf("expected webp encoder args: %s", args)
+	}
+}
+
+func TestDefaultOutputAndPresets(t *testing.T) {
+	t.Parallel()
+
+	got := DefaultOutput(filepath.Join("renders", "run_001.mov"), FormatWebP, Presets["medium"])
+	if want := filepath.Join("renders", "run_001_medium.webp"); got != want {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+	if names := strings.Join(PresetNames(), ","); names != "small,medium,large" {
+		t.Fatalf("unexpected preset order: %s", names)
+	}
+	if _, err := ParseFormat("apng"); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+}
+
+func TestConvertReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.mov")
+	if err := os.WriteFile(input, []byte("mov"), 0o644); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	fake := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\nprintf 'frame=10\\nout_time_us=1000000\\nprogress=continue\\nout_time_us=2000000\\nprogress=end\\n'\n"
+	if err := os.WriteFile(fake, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake ffmpeg: %v", err)
+	}
+
+	var seen []float64
+	opts := Options{Input: input, Output: filepath.Join(dir, "out.gif"), Format: FormatGIF, Preset: Presets["small"]}
+	if err := Convert(context.Background(), fake, opts, 4*time.Second, func(f float64) { seen = append(seen, f) }); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 0.25 || seen[1] != 0.5 || seen[2] != 1 {
+		t.Fatalf("unexpected progress: %v", seen)
+	}
+}
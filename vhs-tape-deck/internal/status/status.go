@@ -0,0 +1,178 @@
+// Package status sums up the deck's current or latest run in one line for
+// tmux status bars and shell prompts.
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/anim"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+// DefaultFormat prints e.g. "✓ promo success 3m".
+const DefaultFormat = "{{.Glyph}} {{.Tape}} {{.Status}} {{.Age}}"
+
+// StatusRunning and StatusNone extend the record statuses for a run in
+// progress and for a deck that has never run anything.
+const (
+	StatusRunning = "running"
+	StatusNone    = "none"
+)
+
+// Summary is the run shown in the status line: the newest run still in
+// progress in any tape-deck process, or else the newest finished run.
+type Summary struct {
+	Status   string
+	Tape     string
+	Action   string
+	RunID    string
+	Output   string
+	ExitCode int
+	Started  time.Time
+	// Ended is zero while the run is in progress.
+	Ended time.Time
+	// Running counts runs in progress; Queued counts jobs waiting in the
+	// deck's render queue.
+	Running int
+	Queued  int
+
+	now time.Time
+}
+
+// Read builds the summary from the run claims, records and render queue
+// under runsDir.
+func Read(runsDir string, now time.Time) (Summary, error) {
+	s := Summary{Status: StatusNone, Tape: "-", now: now}
+	if st, err := queue.Load(queue.Path(runsDir)); err == nil {
+		s.Queued = len(st.Pending)
+	}
+	active, err := runner.ActiveRunIDs(runsDir)
+	if err != nil {
+		return s, err
+	}
+	s.Running = len(active)
+	if n := len(active); n > 0 {
+		id := active[n-1]
+		s.Status, s.RunID = StatusRunning, id
+		if tape, started, ok := runner.ParseRunID(id); ok {
+			s.Tape, s.Started = tape, started
+		}
+		return s, nil
+	}
+
+	record, err := runner.LatestRecord(runsDir)
+	if err != nil || record == nil {
+		return s, err
+	}
+	s.Status = string(record.Status)
+	if record.Status == "" {
+		s.Status = string(runner.StatusSuccess)
+		if record.ExitCode != 0 {
+			s.Status = string(runner.StatusFailed)
+		}
+	}
+	s.Tape, s.Action, s.RunID, s.ExitCode = record.TapeID, string(record.Action), record.RunID, record.ExitCode
+	s.Started = record.Timestamp
+	s.Ended = record.Timestamp.Add(time.Duration(record.DurationMS) * time.Millisecond)
+	if len(record.OutputPaths) > 0 {
+		s.Output = record.OutputPaths[0]
+	}
+	return s, nil
+}
+
+// Glyph is the shelf's unicode status glyph, readable without color.
+func (s Summary) Glyph() string {
+	state := anim.StateIdle
+	switch s.Status {
+	case StatusRunning:
+		state = anim.StateRunning
+	case string(runner.StatusSuccess):
+		state = anim.StateSuccess
+	case string(runner.StatusFailed), string(runner.StatusAborted):
+		state = anim.StateFailed
+	}
+	return anim.StatusGlyph(state, anim.GlyphsUnicode)
+}
+
+// Age is how long ago the run finished, or how long it has been running.
+func (s Summary) Age() string {
+	switch {
+	case s.Started.IsZero():
+		return "-"
+	case s.Ended.IsZero():
+		return compact(s.now.Sub(s.Started))
+	}
+	return compact(s.now.Sub(s.Ended))
+}
+
+// Elapsed is the run's duration so far.
+func (s Summary) Elapsed() string {
+	switch {
+	case s.Started.IsZero():
+		return "-"
+	case s.Ended.IsZero():
+		return compact(s.now.Sub(s.Started))
+	}
+	return compact(s.Ended.Sub(s.Started))
+}
+
+// MarshalJSON encodes the summary for `status --json`, with the glyph and
+// ages included and unknown times left out.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Status   string     `json:"status"`
+		Glyph    string     `json:"glyph"`
+		Tape     string     `json:"tape"`
+		Action   string     `json:"action,omitempty"`
+		RunID    string     `json:"run_id,omitempty"`
+		Output   string     `json:"output,omitempty"`
+		ExitCode int        `json:"exit_code"`
+		Started  *time.Time `json:"started,omitempty"`
+		Ended    *time.Time `json:"ended,omitempty"`
+		Age      string     `json:"age"`
+		Elapsed  string     `json:"elapsed"`
+		Running  int        `json:"running"`
+		Queued   int        `json:"queued"`
+	}{
+		Status: s.Status, Glyph: s.Glyph(), Tape: s.Tape, Action: s.Action, RunID: s.RunID, Output: s.Output,
+		ExitCode: s.ExitCode, Age: s.Age(), Elapsed: s.Elapsed(), Running: s.Running, Queued: s.Queued,
+	}
+	if !s.Started.IsZero() {
+		out.Started = &s.Started
+	}
+	if !s.Ended.IsZero() {
+		out.Ended = &s.Ended
+	}
+	return json.Marshal(out)
+}
+
+// compact prints a duration in its largest whole unit: 45s, 3m, 5h, 2d.
+func compact(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(0, int(d.Seconds())))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// Write prints s with format, a text/template over Summary, and a newline.
+func Write(w io.Writer, s Summary, format string) error {
+	tmpl, err := template.New("status").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return fmt.Errorf("parse format: %w", err)
+	}
+	if err := tmpl.Execute(w, s); err != nil {
+		return fmt.Errorf("format status: %w", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
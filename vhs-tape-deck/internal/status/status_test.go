@@ -0,0 +1,95 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+func TestReadLatestAndRunning(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	s, err := Read(dir, now)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, s, DefaultFormat); err != nil || buf.String() != "○ - none -\n" {
+		t.Fatalf("unexpected empty status %q (%v)", buf.String(), err)
+	}
+
+	for _, r := range []runner.RunRecord{
+		{RunID: "20260301_100000_alpha_001", TapeID: "alpha", Timestamp: now.Add(-2 * time.Hour), Status: runner.StatusSuccess, DurationMS: 60_000},
+		{RunID: "20260301_110000_promo_001", TapeID: "promo", Timestamp: now.Add(-time.Hour), Status: runner.StatusFailed, ExitCode: 3, DurationMS: 90_000, OutputPaths: []string{"/out/promo.mov"}},
+	} {
+		if err := runner.WriteRunRecord(runner.RecordPath(dir, r.RunID), &r); err != nil {
+			t.Fatalf("write record: %v", err)
+		}
+	}
+	if err := queue.Save(queue.Path(dir), &queue.State{Pending: []queue.Job{{TapeID: "alpha"}, {TapeID: "promo"}}}); err != nil {
+		t.Fatalf("save queue: %v", err)
+	}
+	s, err = Read(dir, now)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	buf.Reset()
+	if err := Write(&buf, s, DefaultFormat+" {{.Elapsed}} q={{.Queued}} {{.ExitCode}} {{.Output}}"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "✗ promo failed 58m 1m q=2 3 /out/promo.mov\n" {
+		t.Fatalf("unexpected status %q", got)
+	}
+
+	claims := filepath.Join(dir, "claims")
+	if err := os.MkdirAll(claims, 0o755); err != nil {
+		t.Fatalf("mkdir claims: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(claims, "20260301_115500_alpha_002"), []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
+		t.Fatalf("write claim: %v", err)
+	}
+	s, err = Read(dir, now)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	buf.Reset()
+	if err := Write(&buf, s, "{{.Glyph}} {{.Tape}} {{.Status}} {{.Age}} {{.Running}}"); err != nil || buf.String() != "▶ alpha running 5m 1\n" {
+		t.Fatalf("unexpected running status %q (%v)", buf.String(), err)
+	}
+
+	if err := Write(&buf, s, "{{.Nope}}"); err == nil {
+		t.Fatalf("expected an error for an unknown field")
+	}
+}
+
+func TestSummaryJSON(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	buf, err := json.Marshal(Summary{Status: StatusNone, Tape: "-", now: now})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got := string(buf); got != `{"status":"none","glyph":"○","tape":"-","exit_code":0,"age":"-","elapsed":"-","running":0,"queued":0}` {
+		t.Fatalf("unexpected empty summary %s", got)
+	}
+
+	s := Summary{Status: StatusRunning, Tape: "alpha", RunID: "20260301_115500_alpha_002", Started: now.Add(-5 * time.Minute), Running: 1, now: now}
+	var got map[string]any
+	buf, _ = json.Marshal(s)
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got["glyph"] != "▶" || got["age"] != "5m" || got["started"] != "2026-03-01T11:55:00Z" || got["ended"] != nil {
+		t.Fatalf("unexpected running summary %s", buf)
+	}
+}
@@ -0,0 +1,60 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
+	if e, err := FromEnv(); e != nil || err != nil {
+		t.Fatalf("expected no exporter without an endpoint, got %v %v", e, err)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-team=render%20farm, api-key=abc")
+	t.Setenv("OTEL_SERVICE_NAME", "station-2")
+	e, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+	if e.url != "http://collector:4318/v1/traces" || e.service != "station-2" {
+		t.Fatalf("unexpected exporter: %s %s", e.url, e.service)
+	}
+	if e.headers["x-team"] != "render farm" || e.headers["api-key"] != "abc" {
+		t.Fatalf("unexpected headers: %v", e.headers)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	if _, err := FromEnv(); err == nil || !strings.Contains(err.Error(), "http/json") {
+		t.Fatalf("expected a protocol error, got %v", err)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if e, err := FromEnv(); e != nil || err != nil {
+		t.Fatalf("expected no exporter when disabled, got %v %v", e, err)
+	}
+}
+
+func TestShutdownReportsExportErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	e := NewExporter(Options{URL: server.URL})
+	e.enqueue(&Span{exporter: e, traceID: newID(16), spanID: newID(8), name: "run primary"})
+	err := e.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "bad payload") {
+		t.Fatalf("expected the collector's error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Package trace records spans for runs, pipelines and schedules and sends
+// them to an OpenTelemetry collector over OTLP/HTTP with JSON bodies.
+// Without an installed exporter, spans are nil and cost nothing.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var active atomic.Pointer[Exporter]
+
+// Install makes e receive every span that ends from now on; nil turns
+// tracing off.
+func Install(e *Exporter) {
+	active.Store(e)
+}
+
+// Attr is a span attribute. Value is a string, int64, float64 or bool.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attr    { return Attr{key, value} }
+func Int(key string, value int) Attr   { return Attr{key, int64(value)} }
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// Span is one timed operation. A nil Span ignores every call.
+type Span struct {
+	exporter *Exporter
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	start    time.Time
+
+	mu     sync.Mutex
+	end    time.Time
+	attrs  []Attr
+	failed bool
+	reason string
+}
+
+type spanKey struct{}
+
+// Start begins a span under the one in ctx, if any, and returns a context
+// carrying it for child spans.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	e := active.Load()
+	if e == nil {
+		return ctx, nil
+	}
+	s := &Span{exporter: e, spanID: newID(8), name: name, start: time.Now(), attrs: attrs}
+	if parent, _ := ctx.Value(spanKey{}).(*Span); parent != nil {
+		s.traceID, s.parentID = parent.traceID, parent.spanID
+	} else {
+		s.traceID = newID(16)
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Set adds attributes to the span.
+func (s *Span) Set(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// Fail marks the span as an error with reason.
+func (s *Span) Fail(reason string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed, s.reason = true, reason
+}
+
+// End finishes the span and queues it for export. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.exporter.enqueue(s)
+}
+
+// TraceID is the span's trace as 32 hex digits, or "" for a nil span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.traceID
+}
+
+func newID(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Tests in this file install the global exporter, so they do not run in
+// parallel.
+
+func TestSpansExportWithParents(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		got  []otlpRequest
+		auth string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		got = append(got, req)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	e := NewExporter(Options{URL: server.URL + "/v1/traces", Headers: map[string]string{"Authorization": "Bearer x"}})
+	Install(e)
+	ctx, parent := Start(context.Background(), "pipeline", String("pipeline", "nightly"))
+	_, child := Start(ctx, "run primary", String("run_id", "20250301_101500_alpha_001"), Int("exit_code", 3), Bool("dry_run", false))
+	child.Fail("run failed with exit code 3")
+	child.End()
+	child.End()
+	parent.End()
+	Install(nil)
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if len(got) != 1 || auth != "Bearer x" {
+		t.Fatalf("expected one export with the auth header, got %d (auth %q)", len(got), auth)
+	}
+	rs := got[0].ResourceSpans[0]
+	if rs.Resource.Attributes[0].Value["stringValue"] != "tape-deck" {
+		t.Fatalf("unexpected resource: %+v", rs.Resource)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %+v", spans)
+	}
+	run, pipe := spans[0], spans[1]
+	if run.TraceID != pipe.TraceID || run.ParentSpanID != pipe.SpanID || pipe.ParentSpanID != "" {
+		t.Fatalf("child not linked to parent: %+v / %+v", run, pipe)
+	}
+	if len(run.TraceID) != 32 || len(run.SpanID) != 16 {
+		t.Fatalf("unexpected id lengths: %q %q", run.TraceID, run.SpanID)
+	}
+	if run.Status.Code != statusError || pipe.Status.Code != statusOK {
+		t.Fatalf("unexpected statuses: %+v / %+v", run.Status, pipe.Status)
+	}
+	attrs := map[string]map[string]any{}
+	for _, a := range run.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	if attrs["run_id"]["stringValue"] != "20250301_101500_alpha_001" || attrs["exit_code"]["intValue"] != "3" || attrs["dry_run"]["boolValue"] != false {
+		t.Fatalf("unexpected attributes: %v", attrs)
+	}
+}
+
+func TestSpansAreNilWithoutExporter(t *testing.T) {
+	ctx := context.Background()
+	got, span := Start(ctx, "run primary")
+	if span != nil || got != ctx {
+		t.Fatalf("expected a nil span and the same context")
+	}
+	span.Set(String("k", "v"))
+	span.Fail("x")
+	span.End()
+	if span.TraceID() != "" {
+		t.Fatalf("nil span has a trace id")
+	}
+}
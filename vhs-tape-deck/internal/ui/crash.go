@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+)
+
+const crashEventLines = 50
+
+type crashMsg struct {
+	value any
+	stack []byte
+}
+
+// crashGuard wraps the UI model so a panic in Update, View or a command ends
+// the program cleanly (restoring the terminal) instead of tearing it down.
+type crashGuard struct {
+	inner *model
+	value any
+	stack []byte
+	// send delivers a message to the running program; a View panic uses it
+	// to wake Update so the program quits.
+	send func(tea.Msg)
+}
+
+func (g *crashGuard) Init() tea.Cmd {
+	return guardCmd(g.inner.Init())
+}
+
+func (g *crashGuard) Update(msg tea.Msg) (_ tea.Model, cmd tea.Cmd) {
+	if crash, ok := msg.(crashMsg); ok {
+		return g.fail(crash.value, crash.stack)
+	}
+	if g.crashed() {
+		return g, tea.Quit
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_, cmd = g.fail(r, debug.Stack())
+		}
+	}()
+	_, cmd = g.inner.Update(msg)
+	return g, guardCmd(cmd)
+}
+
+func (g *crashGuard) View() (view string) {
+	if g.value != nil {
+		return ""
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.value = r
+			g.stack = debug.Stack()
+			view = "tape deck crashed, shutting down..."
+			if g.send != nil {
+				// View runs on the event loop, so Send must not block it.
+				go g.send(crashMsg{value: g.value, stack: g.stack})
+			}
+		}
+	}()
+	return g.inner.View()
+}
+
+func (g *crashGuard) fail(value any, stack []byte) (tea.Model, tea.Cmd) {
+	if g.value == nil {
+		g.value = value
+		g.stack = stack
+	}
+	return g, tea.Quit
+}
+
+func (g *crashGuard) crashed() bool {
+	return g.value != nil
+}
+
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = crashMsg{value: r, stack: debug.Stack()}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			guarded := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				guarded[i] = guardCmd(c)
+			}
+			return guarded
+		}
+		return msg
+	}
+}
+
+// writeCrashReport stores the panic, stack and recent log lines next to the
+// tape-deck logs and returns the report path.
+func (g *crashGuard) writeCrashReport(now time.Time) (string, error) {
+	dir, err := logging.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create crash dir: %w", err)
+	}
+
+	recent := g.inner.logs
+	if len(recent) > crashEventLines {
+		recent = recent[len(recent)-crashEventLines:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "tape-deck crash at %s\n\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "panic: %v\n\n", g.value)
+	fmt.Fprintf(&b, "status: %s\ninserted: %s\nrunning: %s\n\n", g.inner.status, g.inner.insertedTapeID, g.inner.runningID)
+	b.WriteString("recent events:\n")
+	for _, line := range recent {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\nstack:\n")
+	b.Write(g.stack)
+
+	path := filepath.Join(dir, "crash-"+now.Format("20060102_150405")+".txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", fmt.Errorf("write crash report: %w", err)
+	}
+	return path, nil
+}
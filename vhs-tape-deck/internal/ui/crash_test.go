@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewPanicQuits(t *testing.T) {
+	t.Parallel()
+
+	sent := make(chan tea.Msg, 1)
+	// A bare model has no translator, so its View panics.
+	g := &crashGuard{inner: &model{}, send: func(msg tea.Msg) { sent <- msg }}
+	if view := g.View(); !strings.Contains(view, "crashed") {
+		t.Fatalf("unexpected view %q", view)
+	}
+	if !g.crashed() {
+		t.Fatalf("View panic was not recorded")
+	}
+
+	var msg tea.Msg
+	select {
+	case msg = <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("no message sent after the View panic")
+	}
+	for _, m := range []tea.Msg{msg, tea.KeyMsg{Type: tea.KeyDown}} {
+		_, cmd := g.Update(m)
+		if cmd == nil {
+			t.Fatalf("Update(%T) after a crash returned no command", m)
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Fatalf("Update(%T) after a crash did not quit", m)
+		}
+	}
+	if g.View() != "" {
+		t.Fatalf("crashed guard should render nothing")
+	}
+}
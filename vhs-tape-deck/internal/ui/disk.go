@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/disk"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+)
+
+type diskMsg struct {
+	used int64
+	err  error
+}
+
+// diskUsageCmd totals the renders under runs_dir and any tape output_dir
+// that lives outside it.
+func diskUsageCmd(cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		var total int64
+		for _, dir := range renderDirs(cfg) {
+			n, err := disk.Usage(dir)
+			if err != nil {
+				return diskMsg{used: total + n, err: err}
+			}
+			total += n
+		}
+		return diskMsg{used: total}
+	}
+}
+
+func renderDirs(cfg *config.Config) []string {
+	dirs := []string{cfg.RunsDir}
+	for _, t := range cfg.Tapes {
+		if t.OutputDir == "" || within(t.OutputDir, dirs) {
+			continue
+		}
+		dirs = append(dirs, t.OutputDir)
+	}
+	return dirs
+}
+
+func within(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowDiskPrompt returns a confirmation for job when its output volume has
+// less free space than disk.min_free_mb, or nil when there is room or the
+// space cannot be measured.
+func (m *model) lowDiskPrompt(job queue.Job, outputDir string) *prompt {
+	floor := m.cfg.Disk.MinFreeBytes()
+	if floor == 0 || job.DryRun {
+		return nil
+	}
+	free, err := disk.Free(outputDir)
+	if err != nil || free >= floor {
+		return nil
+	}
+	return &prompt{
+		title: m.tr.T("prompt.low_disk_title"),
+		body:  m.tr.T("prompt.low_disk", disk.Format(int64(free)), outputDir, m.cfg.Disk.MinFreeMB),
+		yes: func() tea.Cmd {
+			return m.enqueue(job)
+		},
+		no: func() {
+			m.status = m.tr.T("status.low_disk_skipped", job.TapeID)
+		},
+	}
+}
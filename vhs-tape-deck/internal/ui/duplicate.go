@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/anim"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/i18n"
+)
+
+func (m *model) duplicateTape() {
+	if len(m.cfg.Tapes) == 0 {
+		return
+	}
+	if m.cfg.Path == "" {
+		m.status = m.tr.T("status.dup_no_config")
+		return
+	}
+	if m.runEvents != nil || m.pipelineEvents != nil {
+		m.status = m.tr.T("status.dup_busy")
+		return
+	}
+	src := m.cfg.Tapes[m.selected]
+	id := config.NextTapeID(m.cfg, src.ID)
+	manifest, err := config.DuplicateTape(m.cfg.Path, m.cfg.ProjectRoot, config.Duplicate{SourceID: src.ID, ID: id})
+	if err != nil {
+		m.status = m.tr.T("status.dup_failed")
+		m.appendLog("[tape] " + err.Error())
+		return
+	}
+	if err := m.reloadConfig(); err != nil {
+		m.status = m.tr.T("status.reload_failed")
+		m.appendLog("[config] " + err.Error())
+		return
+	}
+	for i, tape := range m.cfg.Tapes {
+		if tape.ID == id {
+			m.selected = i
+		}
+	}
+	m.status = m.tr.T("status.duplicated", src.ID, id)
+	m.appendLog(fmt.Sprintf("[tape] %s -> %s (%s)", src.ID, id, manifest))
+	m.log.Info("tape duplicated", "source", src.ID, "tape", id, "manifest", manifest)
+}
+
+// reloadConfig re-reads the config file into a new Config. Work started
+// earlier keeps the Config it was given, so it is never changed under it.
+func (m *model) reloadConfig() error {
+	cfg, err := config.Load(m.cfg.Path, m.cfg.ProjectRoot)
+	if err != nil {
+		return err
+	}
+	m.cfg = cfg
+	m.glyphs = glyphSet(m.cfg)
+	m.color = cassetteColor(m.cfg)
+	m.packs = map[string]*anim.Pack{}
+	m.tr = i18n.New(i18n.Detect(m.cfg.UI.Locale))
+	m.keys = newKeyMap(m.tr)
+	for _, tape := range m.cfg.Tapes {
+		if _, ok := m.tapeStates[tape.ID]; !ok {
+			m.tapeStates[tape.ID] = anim.StateIdle
+		}
+	}
+	if m.selected >= len(m.cfg.Tapes) {
+		m.selected = len(m.cfg.Tapes) - 1
+	}
+	m.ensureVisibleSelection()
+	return nil
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+type editorDoneMsg struct {
+	tapeID   string
+	manifest string
+	err      error
+}
+
+type manifestCheckMsg struct {
+	tapeID   string
+	manifest string
+	output   string
+	err      error
+}
+
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func (m *model) editManifest() tea.Cmd {
+	if len(m.cfg.Tapes) == 0 {
+		return nil
+	}
+	tape := m.cfg.Tapes[m.selected]
+	manifest, err := config.ResolveManifestPath(m.cfg.ProjectRoot, tape.Manifest)
+	if err != nil {
+		m.status = m.tr.T("status.no_manifest")
+		m.appendLog("[edit] " + err.Error())
+		return nil
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], manifest)...)
+	cmd.Dir = m.cfg.ProjectRoot
+	m.status = m.tr.T("status.editing", tape.ID)
+	m.log.Info("open editor", "tape", tape.ID, "editor", editor[0], "manifest", manifest)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorDoneMsg{tapeID: tape.ID, manifest: manifest, err: err}
+	})
+}
+
+func checkManifestCmd(run *runner.Runner, cfg *config.Config, tapeID, manifest string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := run.CheckManifest(context.Background(), cfg, manifest)
+		return manifestCheckMsg{tapeID: tapeID, manifest: manifest, output: out, err: err}
+	}
+}
+
+func (m *model) handleEditorDone(msg editorDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.status = m.tr.T("status.editor_failed")
+		m.appendLog("[edit] " + msg.err.Error())
+		return nil
+	}
+	m.status = m.tr.T("status.validating")
+	m.appendLog("[edit] saved " + msg.manifest + ", running vcr check")
+	return checkManifestCmd(m.runner, m.cfg, msg.tapeID, msg.manifest)
+}
+
+func (m *model) handleManifestCheck(msg manifestCheckMsg) {
+	for _, line := range strings.Split(msg.output, "\n") {
+		m.appendLog("[check] " + line)
+	}
+	if msg.err != nil {
+		m.status = m.tr.T("status.manifest_invalid")
+		m.appendLog(fmt.Sprintf("[check] %s failed validation: %v", msg.tapeID, msg.err))
+		return
+	}
+
+	m.status = m.tr.T("status.manifest_valid")
+	m.prompt = &prompt{
+		title: m.tr.T("prompt.manifest_title"),
+		body:  m.tr.T("prompt.manifest_passed", msg.tapeID),
+		yes: func() tea.Cmd {
+			if m.insertedTapeID != msg.tapeID {
+				for i, tape := range m.cfg.Tapes {
+					if tape.ID == msg.tapeID {
+						m.selected = i
+					}
+				}
+				return tea.Batch(m.toggleInsert(), m.startRun(runner.ActionPrimary))
+			}
+			return m.startRun(runner.ActionPrimary)
+		},
+	}
+}
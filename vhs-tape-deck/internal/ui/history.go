@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+// loadHistory reads earlier run records so the shelf can sort by recent
+// activity and the newest successful output of every tape can be inspected
+// and shared right away.
+func (m *model) loadHistory() {
+	if m.cfg.RunsDir == "" {
+		return
+	}
+	h, err := runner.LoadHistory(m.cfg.RunsDir)
+	if err != nil {
+		m.log.Warn("load run records", "err", err)
+		return
+	}
+	for _, path := range h.Quarantined {
+		m.log.Warn("unreadable run record quarantined", "path", path)
+		m.appendLog("[history] unreadable record moved to " + path)
+	}
+	for _, record := range h.Records {
+		m.recordRun(record)
+		if record.ExitCode != 0 || record.DryRun || len(record.OutputPaths) == 0 {
+			continue
+		}
+		if _, err := os.Stat(record.OutputPaths[0]); err != nil {
+			continue
+		}
+		m.lastOutputs[record.TapeID] = record.OutputPaths[0]
+		m.lastRecords[record.TapeID] = runner.RecordPath(m.cfg.RunsDir, record.RunID)
+	}
+}
+
+func (m *model) recordRun(record runner.RunRecord) {
+	stats, ok := m.stats[record.TapeID]
+	if !ok {
+		stats = &runner.TapeStats{}
+		m.stats[record.TapeID] = stats
+	}
+	stats.Add(record)
+
+	if record.Status == "" {
+		record.Status = runner.StatusSuccess
+		if record.ExitCode != 0 {
+			record.Status = runner.StatusFailed
+		}
+	}
+	if prev, ok := m.lastRuns[record.TapeID]; ok && prev.Timestamp.After(record.Timestamp) {
+		return
+	}
+	m.lastRuns[record.TapeID] = record
+}
+
+func (m *model) statsMeta(tapeID string) []string {
+	s, ok := m.stats[tapeID]
+	if !ok || s.Runs == 0 {
+		return []string{m.tr.T("meta.no_stats")}
+	}
+	line := m.tr.T("meta.stats", s.Runs, s.SuccessRate()*100)
+	if avg := s.AvgRenderTime(); avg > 0 {
+		line += m.tr.T("meta.stats_avg", avg.Round(100*time.Millisecond))
+	}
+	if s.LastOutput != "" {
+		if size, ok := m.outputSize(s.LastOutput); ok {
+			line += m.tr.T("meta.stats_last", humanBytes(size))
+		}
+	}
+	return []string{line}
+}
+
+// outputSize stats path once; render output files do not change after the
+// run that wrote them.
+func (m *model) outputSize(path string) (int64, bool) {
+	if size, ok := m.outputSizes[path]; ok {
+		return size, size >= 0
+	}
+	size := int64(-1)
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	m.outputSizes[path] = size
+	return size, size >= 0
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -1,32 +1,60 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/i18n"
+)
 
 type keyMap struct {
-	Up      key.Binding
-	Down    key.Binding
-	Insert  key.Binding
-	Play    key.Binding
-	Preview key.Binding
-	Cancel  key.Binding
-	DryRun  key.Binding
-	Logs    key.Binding
-	Help    key.Binding
-	Quit    key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Insert    key.Binding
+	Play      key.Binding
+	Preview   key.Binding
+	Verify    key.Binding
+	Cancel    key.Binding
+	Edit      key.Binding
+	Dup       key.Binding
+	Hidden    key.Binding
+	Sort      key.Binding
+	Share     key.Binding
+	Preset    key.Binding
+	WhatsNew  key.Binding
+	Pipelines key.Binding
+	Snapshot  key.Binding
+	DryRun    key.Binding
+	Logs      key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+	Confirm   key.Binding
+	Dismiss   key.Binding
 }
 
-func newKeyMap() keyMap {
+func newKeyMap(tr *i18n.Catalog) keyMap {
 	return keyMap{
-		Up:      key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "previous tape")),
-		Down:    key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "next tape")),
-		Insert:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "insert/eject")),
-		Play:    key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "play")),
-		Preview: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "preview frame")),
-		Cancel:  key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel run")),
-		DryRun:  key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "toggle dry run")),
-		Logs:    key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "clear logs")),
-		Help:    key.NewBinding(key.WithKeys("h", "?"), key.WithHelp("h/?", "toggle help")),
-		Quit:    key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", tr.T("key.up"))),
+		Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", tr.T("key.down"))),
+		Insert:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", tr.T("key.insert"))),
+		Play:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", tr.T("key.play"))),
+		Preview:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", tr.T("key.preview"))),
+		Verify:    key.NewBinding(key.WithKeys("v"), key.WithHelp("v", tr.T("key.verify"))),
+		Cancel:    key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", tr.T("key.cancel"))),
+		Edit:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", tr.T("key.edit"))),
+		Dup:       key.NewBinding(key.WithKeys("c"), key.WithHelp("c", tr.T("key.dup"))),
+		Hidden:    key.NewBinding(key.WithKeys("."), key.WithHelp(".", tr.T("key.hidden"))),
+		Sort:      key.NewBinding(key.WithKeys("s"), key.WithHelp("s", tr.T("key.sort"))),
+		Share:     key.NewBinding(key.WithKeys("g"), key.WithHelp("g", tr.T("key.share"))),
+		Preset:    key.NewBinding(key.WithKeys("G"), key.WithHelp("G", tr.T("key.preset"))),
+		WhatsNew:  key.NewBinding(key.WithKeys("w"), key.WithHelp("w", tr.T("key.whats_new"))),
+		Pipelines: key.NewBinding(key.WithKeys("P"), key.WithHelp("P", tr.T("key.pipelines"))),
+		Snapshot:  key.NewBinding(key.WithKeys("S"), key.WithHelp("S", tr.T("key.snapshot"))),
+		DryRun:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", tr.T("key.dry_run"))),
+		Logs:      key.NewBinding(key.WithKeys("l"), key.WithHelp("l", tr.T("key.logs"))),
+		Help:      key.NewBinding(key.WithKeys("h", "?"), key.WithHelp("h/?", tr.T("key.help"))),
+		Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", tr.T("key.quit"))),
+		Confirm:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", tr.T("key.confirm"))),
+		Dismiss:   key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", tr.T("key.dismiss"))),
 	}
 }
 
@@ -37,6 +65,7 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Insert, k.Play, k.Cancel},
-		{k.Preview, k.DryRun, k.Logs, k.Help, k.Quit},
+		{k.Preview, k.Verify, k.Edit, k.Share, k.Preset, k.Pipelines, k.DryRun},
+		{k.Dup, k.Hidden, k.Sort, k.Logs, k.Snapshot, k.WhatsNew, k.Help, k.Quit},
 	}
 }
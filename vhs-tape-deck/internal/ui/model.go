@@ -3,6 +3,8 @@ package ui
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -11,31 +13,57 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-
-	"vhs-tape-deck/internal/anim"
-	"vhs-tape-deck/internal/config"
-	"vhs-tape-deck/internal/runner"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/anim"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/changelog"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/disk"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/doctor"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/i18n"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/pipeline"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/session"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/share"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
 )
 
 const (
-	tickRate    = 16
 	maxLogLines = 2500
+	// maxEventBatch caps how many queued run events one message carries, so
+	// a chatty render cannot starve key presses.
+	maxEventBatch = 512
 )
 
 type tickMsg struct{}
 
 type runEventMsg struct {
-	event runner.Event
+	events []runner.Event
 }
 
 type featureMsg struct {
 	info runner.FeatureInfo
 }
 
+type doctorMsg struct {
+	report doctor.Report
+}
+
+type logLineMsg struct {
+	line string
+}
+
 type model struct {
 	cfg      *config.Config
 	runner   *runner.Runner
 	animator anim.CassetteAnimator
+	// packs caches loaded art packs by the tape's aesthetic.art.
+	packs map[string]*anim.Pack
 
 	keys keyMap
 	help help.Model
@@ -46,7 +74,7 @@ type model struct {
 	selected int
 
 	insertedTapeID string
-	insertedAtTick int
+	slot           anim.Slot
 	appState       anim.State
 
 	runEvents <-chan runner.Event
@@ -57,14 +85,58 @@ type model struct {
 	viewport viewport.Model
 
 	showHelp       bool
+	whatsNew       []changelog.Release
+	showDisabled   bool
 	dryRun         bool
+	stalled        bool
 	tickCount      int
+	ticking        bool
+	logsDirty      bool
 	status         string
+	hint           string
 	lastOutputPath string
-
-	feature runner.FeatureInfo
+	diskUsed       int64
+
+	feature  runner.FeatureInfo
+	health   *doctor.Report
+	progress *progress.Snapshot
+	// meter shows log and progress activity of the running tape.
+	meter anim.Meter
+
+	log      *slog.Logger
+	logLines <-chan string
+
+	prompt *prompt
+
+	queuePath string
+	pending   []queue.Job
+	inFlight  *queue.InFlight
+
+	probes      *probe.Cache
+	probed      map[string]probeResult
+	lastOutputs map[string]string
+	lastRecords map[string]string
+	lastRuns    map[string]runner.RunRecord
+	stats       map[string]*runner.TapeStats
+	outputSizes map[string]int64
+	shelfSort   shelfSort
+
+	sharePreset   string
+	shareEvents   <-chan shareMsg
+	shareCancel   context.CancelFunc
+	shareTapeID   string
+	shareProgress *progress.Snapshot
+
+	showPipelines  bool
+	pipelineSel    int
+	pipelineEvents <-chan pipeline.Event
+	pipelineCancel context.CancelFunc
+	lastPipeline   *pipelineRun
 
 	tapeStates map[string]anim.State
+	glyphs     anim.GlyphSet
+	color      bool
+	tr         *i18n.Catalog
 
 	styles styles
 }
@@ -74,6 +146,7 @@ type styles struct {
 	top        lipgloss.Style
 	logs       lipgloss.Style
 	footer     lipgloss.Style
+	warning    lipgloss.Style
 	helpBox    lipgloss.Style
 	helpBg     lipgloss.Style
 	successDot lipgloss.Style
@@ -83,6 +156,7 @@ type styles struct {
 	insertDot  lipgloss.Style
 	selected   lipgloss.Style
 	normal     lipgloss.Style
+	disabled   lipgloss.Style
 }
 
 func newStyles() styles {
@@ -91,6 +165,7 @@ func newStyles() styles {
 		top:        lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("69")).Padding(0, 1),
 		logs:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("241")).Padding(0, 1),
 		footer:     lipgloss.NewStyle().Foreground(lipgloss.Color("249")),
+		warning:    lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true),
 		helpBox:    lipgloss.NewStyle().Border(lipgloss.ThickBorder()).BorderForeground(lipgloss.Color("221")).Background(lipgloss.Color("236")).Padding(1, 2).Width(60),
 		helpBg:     lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("230")),
 		successDot: lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
@@ -100,10 +175,15 @@ func newStyles() styles {
 		insertDot:  lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
 		selected:   lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Bold(true),
 		normal:     lipgloss.NewStyle().Foreground(lipgloss.Color("252")),
+		disabled:   lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 	}
 }
 
-func NewModel(cfg *config.Config, run *runner.Runner) tea.Model {
+func NewModel(cfg *config.Config, run *runner.Runner, opts Options) tea.Model {
+	return newModel(cfg, run, opts)
+}
+
+func newModel(cfg *config.Config, run *runner.Runner, opts Options) *model {
 	vp := viewport.New(20, 10)
 	vp.SetContent("")
 
@@ -115,26 +195,77 @@ func NewModel(cfg *config.Config, run *runner.Runner) tea.Model {
 	hm := help.New()
 	hm.ShowAll = false
 
-	return &model{
-		cfg:        cfg,
-		runner:     run,
-		animator:   anim.NewCassetteAnimator(),
-		keys:       newKeyMap(),
-		help:       hm,
-		viewport:   vp,
-		appState:   anim.StateIdle,
-		status:     "idle",
-		tapeStates: tapeStates,
-		styles:     newStyles(),
+	tr := i18n.New(i18n.Detect(cfg.UI.Locale))
+	m := &model{
+		cfg:         cfg,
+		runner:      run,
+		log:         logging.Component(opts.Logger, "ui"),
+		logLines:    opts.LogLines,
+		animator:    anim.NewCassetteAnimator(),
+		packs:       map[string]*anim.Pack{},
+		keys:        newKeyMap(tr),
+		help:        hm,
+		viewport:    vp,
+		appState:    anim.StateIdle,
+		status:      tr.T("state.idle"),
+		tapeStates:  tapeStates,
+		probed:      map[string]probeResult{},
+		lastOutputs: map[string]string{},
+		lastRecords: map[string]string{},
+		lastRuns:    map[string]runner.RunRecord{},
+		stats:       map[string]*runner.TapeStats{},
+		outputSizes: map[string]int64{},
+		shelfSort:   sortConfig,
+		glyphs:      glyphSet(cfg),
+		color:       cassetteColor(cfg),
+		tr:          tr,
+		sharePreset: share.DefaultPreset,
+		styles:      newStyles(),
+	}
+	cachePath := ""
+	if cfg.RunsDir != "" {
+		m.queuePath = queue.Path(cfg.RunsDir)
+		cachePath = probe.CachePath(cfg.RunsDir)
+	}
+	probes, err := probe.OpenCache(cachePath)
+	if err != nil {
+		m.log.Warn("probe cache unreadable, starting empty", "err", err)
+	}
+	m.probes = probes
+	for _, w := range cfg.Warnings {
+		m.appendLog("[config] " + w)
+	}
+	m.loadHistory()
+	m.ensureVisibleSelection()
+	if len(opts.Resume) > 0 {
+		m.queuePrompt(m.resumePrompt(opts.Resume))
 	}
+	if opts.Prefs != nil {
+		m.applyShelfPrefs(*opts.Prefs)
+		m.whatsNew = whatsNewSince(opts.Prefs.LastSeenVersion)
+	}
+	if opts.Restore != nil {
+		restore := m.restorePrompt(*opts.Restore)
+		restore.then = m.prompt
+		m.prompt = restore
+	}
+	return m
 }
 
 func (m *model) Init() tea.Cmd {
-	return tea.Batch(nextTick(), detectFeatureCmd(m.runner, m.cfg))
+	cmds := []tea.Cmd{detectFeatureCmd(m.runner, m.cfg), doctorCmd(m.cfg), diskUsageCmd(m.cfg), m.probeSelected()}
+	if m.logLines != nil {
+		cmds = append(cmds, waitLogLine(m.logLines))
+	}
+	return tea.Batch(cmds...)
 }
 
-func nextTick() tea.Cmd {
-	return tea.Tick(time.Second/tickRate, func(time.Time) tea.Msg {
+// nextTick schedules the next redraw at ui.tick_rate frames a second.
+func nextTick(rate int) tea.Cmd {
+	if rate <= 0 {
+		rate = config.DefaultTickRate
+	}
+	return tea.Tick(time.Second/time.Duration(rate), func(time.Time) tea.Msg {
 		return tickMsg{}
 	})
 }
@@ -147,17 +278,70 @@ func detectFeatureCmd(run *runner.Runner, cfg *config.Config) tea.Cmd {
 	}
 }
 
+func doctorCmd(cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return doctorMsg{report: doctor.Run(ctx, cfg, doctor.DefaultOptions())}
+	}
+}
+
+// waitRunEvent blocks for the next event, then takes whatever else is
+// already queued so a burst of log lines costs one Update instead of one
+// per line.
 func waitRunEvent(events <-chan runner.Event) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-events
 		if !ok {
 			return nil
 		}
-		return runEventMsg{event: event}
+		batch := []runner.Event{event}
+	drain:
+		for len(batch) < maxEventBatch && batch[len(batch)-1].Type != runner.EventFinished {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, event)
+			default:
+				break drain
+			}
+		}
+		return runEventMsg{events: batch}
 	}
 }
 
+func waitLogLine(lines <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-lines
+		if !ok {
+			return nil
+		}
+		return logLineMsg{line: line}
+	}
+}
+
+// Update ticks only while something moves. Any message that starts an
+// animation, a run or new log output schedules the first tick; the tick
+// loop stops itself once everything has settled.
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if !m.ticking && m.wantsTick() {
+		m.ticking = true
+		cmd = tea.Batch(cmd, nextTick(m.cfg.UI.TickRate))
+	}
+	return next, cmd
+}
+
+// wantsTick reports whether the next frame differs from this one without
+// any other message arriving: a run is animating the cassette and meter,
+// the tape is moving in the slot, or log lines are waiting to be drawn.
+func (m *model) wantsTick() bool {
+	return m.runEvents != nil || m.slot.Animating(m.tickCount) || m.logsDirty
+}
+
+func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -166,49 +350,71 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		m.tickCount++
-		return m, nextTick()
+		m.meter.Tick()
+		if m.logsDirty {
+			m.refreshLogs()
+		}
+		if !m.wantsTick() {
+			m.ticking = false
+			return m, nil
+		}
+		return m, nextTick(m.cfg.UI.TickRate)
+
+	case soundMsg:
+		if msg.err != nil {
+			m.appendLog("[sound] " + msg.err.Error())
+		}
+
+	case logLineMsg:
+		m.appendLog("[log] " + msg.line)
+		return m, waitLogLine(m.logLines)
+
+	case editorDoneMsg:
+		return m, m.handleEditorDone(msg)
+
+	case manifestCheckMsg:
+		m.handleManifestCheck(msg)
+
+	case probeMsg:
+		m.handleProbe(msg)
+
+	case shareMsg:
+		return m, m.handleShare(msg)
+
+	case pipelineMsg:
+		return m, m.handlePipeline(msg)
+
+	case doctorMsg:
+		m.health = &msg.report
+		for _, c := range msg.report.Checks {
+			if c.Status == doctor.StatusFail {
+				m.appendLog(fmt.Sprintf("[doctor] %s: %s (fix: %s)", c.Name, c.Detail, c.Fix))
+			}
+		}
+
+	case diskMsg:
+		m.diskUsed = msg.used
+		if msg.err != nil {
+			m.log.Warn("measure render output", "err", msg.err)
+		}
 
 	case featureMsg:
 		m.feature = msg.info
 		if msg.info.DetectionFailure != "" {
 			m.appendLog(fmt.Sprintf("[feature] %s", msg.info.DetectionFailure))
 		}
+		if err := msg.info.CheckMinVersion(m.cfg.VCRMinVersion); err != nil {
+			m.status = m.tr.T("status.vcr_outdated")
+			m.appendLog("[vcr] " + err.Error())
+			m.setHint(hint.Of(err))
+		}
 
 	case runEventMsg:
-		switch msg.event.Type {
-		case runner.EventStarted:
-			m.appendLog("$ " + msg.event.Message)
-			m.status = "running"
-		case runner.EventLog:
-			m.appendLog(msg.event.Message)
-		case runner.EventFinished:
-			if msg.event.ExitCode == 0 {
-				m.appState = anim.StateSuccess
-				if m.runningID != "" {
-					m.tapeStates[m.runningID] = anim.StateSuccess
-				}
-				m.status = "success"
-			} else {
-				m.appState = anim.StateFailed
-				if m.runningID != "" {
-					m.tapeStates[m.runningID] = anim.StateFailed
-				}
-				m.status = fmt.Sprintf("failed (%d)", msg.event.ExitCode)
-			}
-			if msg.event.Message != "" {
-				m.appendLog("[run] " + msg.event.Message)
+		for _, event := range msg.events {
+			if cmd, finished := m.handleRunEvent(event); finished {
+				return m, cmd
 			}
-			if msg.event.Record != nil && len(msg.event.Record.OutputPaths) > 0 {
-				m.lastOutputPath = msg.event.Record.OutputPaths[0]
-			}
-			if msg.event.RecordErr != nil {
-				m.appendLog("[record] " + msg.event.RecordErr.Error())
-			}
-			m.runningID = ""
-			m.runEvents = nil
-			m.runCancel = nil
 		}
-
 		if m.runEvents != nil {
 			return m, waitRunEvent(m.runEvents)
 		}
@@ -218,17 +424,40 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.runCancel != nil {
 				m.runCancel()
 			}
+			if m.shareCancel != nil {
+				m.shareCancel()
+			}
+			if m.pipelineCancel != nil {
+				m.pipelineCancel()
+			}
 			return m, tea.Quit
 		}
 		if key.Matches(msg, m.keys.Cancel) {
 			if m.runCancel != nil {
 				m.runCancel()
-				m.status = "canceling..."
+				m.log.Info("cancel requested", "tape", m.runningID)
+				m.status = m.tr.T("status.canceling")
 				m.appendLog("[run] cancel requested")
+			} else if m.pipelineCancel != nil {
+				m.pipelineCancel()
+				m.status = m.tr.T("status.canceling")
+				m.appendLog("[pipeline] cancel requested")
+			} else if m.shareCancel != nil {
+				m.shareCancel()
+				m.appendLog("[share] cancel requested")
 			}
 			return m, nil
 		}
 
+		if m.prompt != nil {
+			return m, m.answerPrompt(msg)
+		}
+
+		if m.whatsNew != nil {
+			m.whatsNew = nil
+			return m, nil
+		}
+
 		if key.Matches(msg, m.keys.Help) {
 			m.showHelp = !m.showHelp
 			return m, nil
@@ -238,114 +467,302 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if key.Matches(msg, m.keys.Snapshot) {
+			m.saveSnapshot()
+			return m, nil
+		}
+
+		if m.showPipelines {
+			return m, m.updatePipelines(msg)
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Up):
-			if m.selected > 0 {
-				m.selected--
-			}
+			m.moveSelection(-1)
 		case key.Matches(msg, m.keys.Down):
-			if m.selected < len(m.cfg.Tapes)-1 {
-				m.selected++
-			}
+			m.moveSelection(1)
+		case key.Matches(msg, m.keys.Hidden):
+			m.toggleShowDisabled()
+		case key.Matches(msg, m.keys.Sort):
+			m.cycleSort()
 		case key.Matches(msg, m.keys.Insert):
-			m.toggleInsert()
+			return m, m.toggleInsert()
 		case key.Matches(msg, m.keys.Play):
 			return m, m.startRun(runner.ActionPrimary)
 		case key.Matches(msg, m.keys.Preview):
 			return m, m.startRun(runner.ActionPreview)
+		case key.Matches(msg, m.keys.Verify):
+			return m, m.startRun(runner.ActionVerify)
+		case key.Matches(msg, m.keys.Edit):
+			return m, m.editManifest()
+		case key.Matches(msg, m.keys.Dup):
+			m.duplicateTape()
+		case key.Matches(msg, m.keys.Share):
+			return m, m.shareOutput()
+		case key.Matches(msg, m.keys.Preset):
+			m.cyclePreset()
+		case key.Matches(msg, m.keys.WhatsNew):
+			m.toggleWhatsNew()
+		case key.Matches(msg, m.keys.Pipelines):
+			m.togglePipelines()
 		case key.Matches(msg, m.keys.DryRun):
 			m.dryRun = !m.dryRun
-			m.status = fmt.Sprintf("dry run: %v", m.dryRun)
+			m.status = m.tr.T("status.dry_run", m.tr.OnOff(m.dryRun))
 		case key.Matches(msg, m.keys.Logs):
 			m.logs = nil
-			m.viewport.SetContent("")
-			m.status = "logs cleared"
+			m.refreshLogs()
+			m.status = m.tr.T("status.logs_cleared")
 		}
 
 		m.syncSelectedState()
+		return m, m.probeSelected()
 	}
 
 	return m, nil
 }
 
 func (m *model) startRun(action runner.Action) tea.Cmd {
-	if m.runEvents != nil {
-		m.appendLog("[run] already running")
-		return nil
-	}
 	if m.insertedTapeID == "" {
-		m.status = "insert a tape first"
+		m.status = m.tr.T("status.insert_first")
 		return nil
 	}
 
 	tape, ok := m.findTape(m.insertedTapeID)
 	if !ok {
-		m.status = "inserted tape is missing"
+		m.status = m.tr.T("status.inserted_missing")
 		return nil
 	}
 	if action == runner.ActionPreview && !tape.Preview.Enabled {
-		m.status = "preview is disabled for this tape"
+		m.status = m.tr.T("status.preview_disabled")
+		return nil
+	}
+	if action == runner.ActionVerify && !tape.Golden.Enabled() {
+		m.status = m.tr.T("status.golden_none")
 		return nil
 	}
-	if action == runner.ActionPreview && m.feature.Checked && !m.feature.HasRenderFrame {
-		m.status = "preview unavailable (render-frame not supported)"
-		m.appendLog("[preview] Update VCR or set primary_args to an explicit supported subcommand.")
+	if (action == runner.ActionPreview || action == runner.ActionVerify) && m.feature.Checked && !m.feature.HasRenderFrame {
+		m.status = m.tr.T("status.preview_unsupported")
+		m.setHint(m.tr.T("hint.preview_unsupported"))
 		return nil
 	}
+	if m.pipelineEvents != nil {
+		m.status = m.tr.T("status.pipeline_busy")
+		return nil
+	}
+	// With build: configured the runner checks the pin after rebuilding.
+	if !m.dryRun && !m.cfg.Build.Enabled() {
+		if err := m.feature.CheckMinVersion(m.cfg.VCRMinVersion); err != nil {
+			m.status = m.tr.T("status.vcr_outdated")
+			m.appendLog("[vcr] " + err.Error())
+			m.setHint(hint.Of(err))
+			return nil
+		}
+	}
+
+	job := queue.Job{TapeID: tape.ID, Action: action, DryRun: m.dryRun, EnqueuedAt: time.Now()}
+	if p := m.lowDiskPrompt(job, tape.OutputDir); p != nil {
+		m.queuePrompt(p)
+		return nil
+	}
+	return m.enqueue(job)
+}
+
+// enqueue starts job now, or queues it behind the active run.
+func (m *model) enqueue(job queue.Job) tea.Cmd {
+	if m.runEvents != nil {
+		m.pending = append(m.pending, job)
+		m.saveQueue()
+		m.status = m.tr.T("status.queued", job.TapeID, job.Action, len(m.pending))
+		m.appendLog(fmt.Sprintf("[queue] %s %s queued behind %s", job.TapeID, job.Action, m.runningID))
+		return nil
+	}
+	return m.startJob(job)
+}
+
+func (m *model) startJob(job queue.Job) tea.Cmd {
+	tape, ok := m.findTape(job.TapeID)
+	if !ok {
+		m.appendLog(fmt.Sprintf("[queue] skipping %s: tape no longer in config", job.TapeID))
+		return m.startNextQueued()
+	}
+	if tape.Disabled {
+		m.appendLog(fmt.Sprintf("[queue] skipping %s: tape is disabled", job.TapeID))
+		return m.startNextQueued()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	events, err := m.runner.Start(ctx, runner.Request{
 		Config: m.cfg,
 		Tape:   tape,
-		Action: action,
-		DryRun: m.dryRun,
+		Action: job.Action,
+		DryRun: job.DryRun,
 	})
 	if err != nil {
 		cancel()
-		m.status = "run failed to start"
+		m.log.Error("run failed to start", "tape", tape.ID, "action", job.Action, "err", err)
+		m.status = m.tr.T("status.start_failed")
 		m.appendLog("[run] " + err.Error())
+		m.setHint(hint.Of(err))
 		m.appState = anim.StateFailed
 		m.tapeStates[tape.ID] = anim.StateFailed
+		m.slot.Shake(tape.ID, m.tickCount)
 		return nil
 	}
 
+	var sound tea.Cmd
+	if m.insertedTapeID != tape.ID {
+		if m.insertedTapeID != "" && m.tapeStates[m.insertedTapeID] == anim.StateInserted {
+			m.tapeStates[m.insertedTapeID] = anim.StateIdle
+		}
+		m.insertedTapeID = tape.ID
+		m.slot.Insert(tape.ID, m.tickCount)
+		sound = playSound(m.cfg.UI.Sounds.Insert)
+	}
 	m.runCancel = cancel
 	m.runEvents = events
 	m.runningID = tape.ID
+	m.setHint("")
+	m.progress = nil
+	m.inFlight = &queue.InFlight{Job: job}
 	m.appState = anim.StateRunning
 	m.tapeStates[tape.ID] = anim.StateRunning
-	m.status = fmt.Sprintf("running %s", action)
-	return waitRunEvent(events)
+	m.status = m.tr.T("status.running_action", job.Action)
+	return tea.Batch(sound, waitRunEvent(events))
 }
 
-func (m *model) toggleInsert() {
-	if len(m.cfg.Tapes) == 0 {
+func (m *model) startNextQueued() tea.Cmd {
+	if m.runEvents != nil || len(m.pending) == 0 {
+		return nil
+	}
+	next := m.pending[0]
+	m.pending = m.pending[1:]
+	m.saveQueue()
+	m.appendLog(fmt.Sprintf("[queue] starting %s %s (%d left)", next.TapeID, next.Action, len(m.pending)))
+	return m.startJob(next)
+}
+
+func (m *model) queueState() *queue.State {
+	return &queue.State{InFlight: m.inFlight, Pending: append([]queue.Job(nil), m.pending...)}
+}
+
+func (m *model) saveQueue() {
+	if m.queuePath == "" {
 		return
 	}
+	if err := queue.Save(m.queuePath, m.queueState()); err != nil {
+		m.appendLog("[queue] " + err.Error())
+	}
+}
+
+func (m *model) toggleInsert() tea.Cmd {
+	if len(m.cfg.Tapes) == 0 {
+		return nil
+	}
 	if m.runEvents != nil {
-		m.status = "cannot eject while running"
-		return
+		m.status = m.tr.T("status.cannot_eject")
+		return nil
 	}
 
 	tape := m.cfg.Tapes[m.selected]
+	if tape.Disabled && m.insertedTapeID != tape.ID {
+		m.status = m.tr.T("status.tape_disabled")
+		return nil
+	}
 	if m.insertedTapeID == tape.ID {
 		m.insertedTapeID = ""
+		m.slot.Eject(tape.ID, m.tickCount)
 		m.appState = anim.StateIdle
-		m.status = "tape ejected"
+		m.status = m.tr.T("status.ejected")
 		if m.tapeStates[tape.ID] == anim.StateInserted {
 			m.tapeStates[tape.ID] = anim.StateIdle
 		}
-		return
+		return playSound(m.cfg.UI.Sounds.Eject)
 	}
 
 	if m.insertedTapeID != "" {
 		m.tapeStates[m.insertedTapeID] = anim.StateIdle
 	}
 	m.insertedTapeID = tape.ID
-	m.insertedAtTick = m.tickCount
+	m.slot.Insert(tape.ID, m.tickCount)
 	m.appState = anim.StateInserted
 	m.tapeStates[tape.ID] = anim.StateInserted
-	m.status = "tape inserted"
+	m.status = m.tr.T("status.inserted")
+	return playSound(m.cfg.UI.Sounds.Insert)
+}
+
+// handleRunEvent applies one run event. finished reports that the run is
+// over and cmd replaces waiting for further events.
+func (m *model) handleRunEvent(event runner.Event) (cmd tea.Cmd, finished bool) {
+	switch event.Type {
+	case runner.EventStarted:
+		m.appendLog("$ " + event.Message)
+		m.status = m.tr.T("state.running")
+		if m.inFlight != nil && event.Plan != nil {
+			m.inFlight.RecordPath = event.Plan.RecordPath
+			m.inFlight.Record = event.Record
+			m.saveQueue()
+		}
+	case runner.EventLog:
+		m.appendLog(event.Message)
+		m.meter.Hit(anim.LogHit)
+		if m.stalled {
+			m.stalled = false
+			m.status = m.tr.T("state.running")
+		}
+	case runner.EventProgress:
+		m.progress = event.Progress
+		m.meter.Hit(anim.ProgressHit)
+	case runner.EventStalled:
+		m.stalled = true
+		m.status = m.tr.T("status.stalled", event.Message)
+		m.appendLog("[watchdog] " + event.Message)
+	case runner.EventFinished:
+		m.stalled = false
+		if event.ExitCode == 0 {
+			m.appState = anim.StateSuccess
+			if m.runningID != "" {
+				m.tapeStates[m.runningID] = anim.StateSuccess
+			}
+			m.status = m.tr.T("state.success")
+		} else {
+			m.appState = anim.StateFailed
+			if m.runningID != "" {
+				m.tapeStates[m.runningID] = anim.StateFailed
+			}
+			m.status = m.tr.T("status.failed", event.ExitCode)
+			m.setHint(event.Hint)
+			m.slot.Shake(m.runningID, m.tickCount)
+		}
+		if event.Message != "" {
+			m.appendLog("[run] " + event.Message)
+		}
+		var probeOutput, redetect tea.Cmd
+		if event.Record != nil {
+			m.recordRun(*event.Record)
+			if b := event.Record.Build; b != nil && !b.Skipped {
+				redetect = detectFeatureCmd(m.runner, m.cfg)
+			}
+		}
+		if event.Record != nil && len(event.Record.OutputPaths) > 0 {
+			m.lastOutputPath = event.Record.OutputPaths[0]
+			if event.ExitCode == 0 && !event.Record.DryRun {
+				probeOutput = m.probeOutput(m.runningID, m.lastOutputPath)
+				if m.inFlight != nil && m.inFlight.RecordPath != "" {
+					m.lastRecords[m.runningID] = m.inFlight.RecordPath
+				}
+			}
+		}
+		if event.RecordErr != nil {
+			m.appendLog("[record] " + event.RecordErr.Error())
+		}
+		m.runningID = ""
+		m.runEvents = nil
+		m.runCancel = nil
+		m.inFlight = nil
+		m.saveQueue()
+		return tea.Batch(probeOutput, redetect, diskUsageCmd(m.cfg), playSound(m.cfg.UI.Sounds.Finish), m.startNextQueued()), true
+	}
+	return nil, false
 }
 
 func (m *model) appendLog(line string) {
@@ -357,29 +774,80 @@ func (m *model) appendLog(line string) {
 	if len(m.logs) > maxLogLines {
 		m.logs = m.logs[len(m.logs)-maxLogLines:]
 	}
+	m.logsDirty = true
+}
+
+// refreshLogs redraws the log pane. appendLog only marks it dirty and the
+// tick redraws, so thousands of lines a second cost one redraw per frame.
+func (m *model) refreshLogs() {
 	m.viewport.SetContent(strings.Join(m.logs, "\n"))
 	m.viewport.GotoBottom()
+	m.logsDirty = false
 }
 
 func (m *model) View() string {
 	if m.width == 0 || m.height == 0 {
-		return "loading tape deck..."
+		return m.tr.T("view.loading")
 	}
 
+	if m.prompt != nil {
+		return m.viewPromptOverlay()
+	}
+	if m.whatsNew != nil {
+		return m.viewWhatsNew()
+	}
 	if m.showHelp {
 		return m.viewHelpOverlay()
 	}
+	if m.showPipelines {
+		return m.viewPipelines()
+	}
 	return m.viewMain()
 }
 
+func (m *model) sessionState() session.State {
+	st := session.State{InsertedTapeID: m.insertedTapeID, DryRun: m.dryRun, ShowDisabled: m.showDisabled, ShelfSort: string(m.shelfSort), LastSeenVersion: version.Deck}
+	if m.selected >= 0 && m.selected < len(m.cfg.Tapes) {
+		st.SelectedTapeID = m.cfg.Tapes[m.selected].ID
+	}
+	return st
+}
+
+// applyShelfPrefs restores shelf display preferences; unlike the rest of the
+// session they apply without asking.
+func (m *model) applyShelfPrefs(st session.State) {
+	m.showDisabled = st.ShowDisabled
+	if s, ok := parseShelfSort(st.ShelfSort); ok {
+		m.shelfSort = s
+	}
+	m.ensureVisibleSelection()
+}
+
+func (m *model) applySession(st session.State) {
+	for i, tape := range m.cfg.Tapes {
+		if tape.ID == st.SelectedTapeID {
+			m.selected = i
+		}
+	}
+	m.dryRun = st.DryRun
+	m.applyShelfPrefs(st)
+	if _, ok := m.findTape(st.InsertedTapeID); ok {
+		m.insertedTapeID = st.InsertedTapeID
+		m.slot.Insert(st.InsertedTapeID, m.tickCount)
+		m.appState = anim.StateInserted
+		m.tapeStates[st.InsertedTapeID] = anim.StateInserted
+	}
+	m.status = m.tr.T("status.session_restored")
+}
+
 func (m *model) viewMain() string {
 	leftWidth := m.leftWidth()
 	rightWidth := max(30, m.width-leftWidth-1)
 
 	topHeight := max(14, m.height/2)
-	bottomHeight := max(6, m.height-topHeight-3)
+	bottomHeight := max(6, m.height-topHeight-m.footerHeight())
 
-	shelf := m.styles.shelf.Width(leftWidth - 2).Height(m.height - 4).Render(m.renderShelf(leftWidth - 4))
+	shelf := m.styles.shelf.Width(leftWidth - 2).Height(m.height - 1 - m.footerHeight()).Render(m.renderShelf(leftWidth - 4))
 	top := m.styles.top.Width(rightWidth - 2).Height(topHeight - 2).Render(m.renderTop(rightWidth-4, topHeight-4))
 	logs := m.styles.logs.Width(rightWidth - 2).Height(bottomHeight - 2).Render(m.viewport.View())
 
@@ -393,18 +861,28 @@ func (m *model) viewMain() string {
 func (m *model) viewHelpOverlay() string {
 	hm := m.help
 	hm.ShowAll = true
-	helpText := "Tape Deck Help\n\n" + hm.View(m.keys) + "\n\nEnter inserts/ejects the selected tape.\nSpace plays the inserted tape.\nCtrl+X cancels an active run.\nP runs preview if enabled."
-	box := m.styles.helpBox.Render(helpText)
+	keys := hm.View(m.keys)
+	helpText := m.tr.T("help.title") + "\n\n" + keys + "\n\n" + m.tr.T("help.body") + "\n\n" + m.statusLegend()
+	// Translated key descriptions run longer, so widen the box to fit them.
+	width := max(60, min(m.width-4, lipgloss.Width(keys)+6))
+	box := m.styles.helpBox.Width(width).Render(helpText)
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
 }
 
 func (m *model) renderShelf(width int) string {
 	var b strings.Builder
-	b.WriteString("Tape Shelf\n")
-	b.WriteString("---------\n")
-	for i, tape := range m.cfg.Tapes {
+	b.WriteString(m.tr.T("shelf.title"))
+	if m.shelfSort != sortConfig {
+		b.WriteString(m.tr.T("shelf.sorted", m.tr.T("sort."+string(m.shelfSort))))
+	}
+	b.WriteString("\n---------\n")
+	for _, i := range m.shelf() {
+		tape := m.cfg.Tapes[i]
 		marker := " "
 		style := m.styles.normal
+		if tape.Disabled {
+			style = m.styles.disabled
+		}
 		if i == m.selected {
 			marker = ">"
 			style = m.styles.selected
@@ -413,7 +891,10 @@ func (m *model) renderShelf(width int) string {
 		dot := m.renderDot(m.tapeStates[tape.ID])
 		inserted := ""
 		if m.insertedTapeID == tape.ID {
-			inserted = " [IN]"
+			inserted = m.tr.T("shelf.inserted")
+		}
+		if tape.Disabled {
+			inserted += m.tr.T("shelf.off")
 		}
 		line := fmt.Sprintf("%s %s %s%s", marker, dot, tape.Name, inserted)
 		if lipgloss.Width(line) > width {
@@ -423,55 +904,98 @@ func (m *model) renderShelf(width int) string {
 	}
 
 	if m.feature.Checked {
-		rf := "no"
+		rf := m.tr.T("common.no")
 		if m.feature.HasRenderFrame {
-			rf = "yes"
+			rf = m.tr.T("common.yes")
 		}
-		b.WriteString("\nrender-frame: " + rf)
+		b.WriteString("\n" + m.tr.T("shelf.render_frame", rf))
+		if m.feature.Version != "" {
+			if m.feature.CheckMinVersion(m.cfg.VCRMinVersion) != nil {
+				b.WriteString("\n" + m.styles.warning.Render(m.tr.T("shelf.vcr_outdated", m.feature.Version, m.cfg.VCRMinVersion)))
+			} else {
+				b.WriteString("\n" + m.tr.T("shelf.vcr", m.feature.Version))
+			}
+		}
+	}
+	if m.health != nil {
+		b.WriteString("\n" + m.tr.T("shelf.doctor", m.health.Summary()))
 	}
 
 	return b.String()
 }
 
+// artPack loads the tape's art once. A pack that cannot be read, say a
+// template deleted since the config loaded, falls back to the default.
+func (m *model) artPack(tape config.Tape) *anim.Pack {
+	if p, ok := m.packs[tape.Aesthetic.Art]; ok {
+		return p
+	}
+	p, err := anim.LoadPack(tape.Aesthetic.Art)
+	if err != nil {
+		m.log.Warn("art pack unreadable, using default", "tape", tape.ID, "err", err)
+	}
+	m.packs[tape.Aesthetic.Art] = p
+	return p
+}
+
 func (m *model) renderTop(width, height int) string {
 	tape := m.cfg.Tapes[m.selected]
 	tapeState := m.stateForTape(tape.ID)
 	inserted := m.insertedTapeID == tape.ID
 
-	animTick := 99
-	if inserted {
-		animTick = 99
-		if diff := m.tickCount - m.insertedAtTick; diff >= 0 && diff < 6 {
-			animTick = diff
-		}
+	opts := anim.Options{LabelStyle: string(tape.Aesthetic.LabelStyle), ShellColorway: string(tape.Aesthetic.ShellColorway), Pack: m.artPack(tape), Color: m.color, Slot: &m.slot, Intensity: anim.Intensity(m.cfg.UI.Animation)}
+	if p := m.progress; p != nil && m.runningID == tape.ID && p.Phase == progress.PhaseRender {
+		opts.Done, opts.Total = p.Done, p.Total
 	}
-
 	cassette := m.animator.Render(
 		tape.Name,
 		tape.ID,
-		animTick,
+		m.tickCount,
 		tapeState,
 		inserted,
-		anim.Options{LabelStyle: string(tape.Aesthetic.LabelStyle), ShellColorway: string(tape.Aesthetic.ShellColorway)},
+		opts,
 	)
 
 	meta := []string{
 		"",
-		"Tape Metadata",
+		m.tr.T("meta.title"),
 		"-------------",
-		"Manifest: " + tape.Manifest,
-		"Mode: " + string(tape.Mode),
-		"Output: " + tape.OutputDir,
-		"Primary Args: " + strings.Join(tape.PrimaryArgs, " "),
 	}
+	// Live progress goes first so it stays visible when the panel is short.
+	if m.progress != nil && m.runningID == tape.ID {
+		meta = append(meta, renderProgress(m.tr.T("meta.progress"), *m.progress, 20))
+	}
+	if m.runningID == tape.ID {
+		meta = append(meta, m.tr.T("meta.activity", m.meter.Render(20, m.color)))
+	}
+	if m.shareProgress != nil && m.shareTapeID == tape.ID {
+		meta = append(meta, renderProgress(m.tr.T("meta.progress"), *m.shareProgress, 20))
+	}
+	meta = append(meta,
+		m.tr.T("meta.manifest", tape.Manifest),
+		m.tr.T("meta.mode", tape.Mode),
+		m.tr.T("meta.output", tape.OutputDir),
+		m.tr.T("meta.args", strings.Join(tape.PrimaryArgs, " ")),
+	)
 	if tape.Preview.Enabled {
-		meta = append(meta, fmt.Sprintf("Preview: frame=%d args=%s", tape.Preview.Frame, strings.Join(tape.Preview.Args, " ")))
+		meta = append(meta, m.tr.T("meta.preview", tape.Preview.Frame, strings.Join(tape.Preview.Args, " ")))
 	} else {
-		meta = append(meta, "Preview: disabled")
+		meta = append(meta, m.tr.T("meta.preview_off"))
 	}
+	meta = append(meta, m.statsMeta(tape.ID)...)
 	if tape.Notes != "" {
-		meta = append(meta, "Notes: "+tape.Notes)
+		meta = append(meta, m.tr.T("meta.notes", tape.Notes))
+	}
+	if tape.Disabled {
+		meta = append(meta, m.tr.T("meta.disabled"))
 	}
+	if tape.Template != "" {
+		meta = append(meta, m.tr.T("meta.template", tape.Template))
+	}
+	if tape.RequiresAlpha {
+		meta = append(meta, m.tr.T("meta.alpha"))
+	}
+	meta = append(meta, m.outputMeta(tape.ID)...)
 
 	left := cassette
 	right := strings.Join(meta, "\n")
@@ -480,28 +1004,74 @@ func (m *model) renderTop(width, height int) string {
 	if lipgloss.Height(joined) < height {
 		joined += strings.Repeat("\n", height-lipgloss.Height(joined))
 	}
+	if lines := strings.Split(joined, "\n"); height > 0 && len(lines) > height {
+		joined = strings.Join(lines[:height], "\n")
+	}
 	return truncateLines(joined, width)
 }
 
+func renderProgress(label string, p progress.Snapshot, barWidth int) string {
+	filled := int(p.Overall*float64(barWidth) + 0.5)
+	filled = max(0, min(barWidth, filled))
+	bar := strings.Repeat("#", filled) + strings.Repeat(".", barWidth-filled)
+	line := fmt.Sprintf("%s [%s] %3.0f%% %s", label, bar, p.Overall*100, p.Phase)
+	if p.Total > 0 {
+		line += fmt.Sprintf(" %d/%d", p.Done, p.Total)
+	}
+	return line
+}
+
 func (m *model) renderFooter() string {
-	status := fmt.Sprintf("status=%s | dry-run=%v", m.status, m.dryRun)
+	status := m.tr.T("footer.status", m.status, m.tr.OnOff(m.dryRun))
+	if len(m.pending) > 0 {
+		status += m.tr.T("footer.queue", len(m.pending))
+	}
+	if m.diskUsed > 0 {
+		status += m.tr.T("footer.disk", disk.Format(m.diskUsed))
+	}
 	if m.lastOutputPath != "" {
-		status += " | last=" + m.lastOutputPath
+		status += m.tr.T("footer.last", m.lastOutputPath)
 	}
 	keys := m.help.ShortHelpView(m.keys.ShortHelp())
-	return m.styles.footer.Render(keys + "\n" + status)
+	footer := m.styles.footer.Render(keys + "\n" + status)
+	if m.stalled {
+		footer = m.styles.footer.Render(keys) + "\n" + m.styles.warning.Render(status)
+	}
+	if m.hint != "" {
+		footer += "\n" + m.styles.warning.Render(truncate(m.tr.T("footer.hint", m.hint), max(10, m.width-2)))
+	}
+	return footer
+}
+
+// footerHeight is the number of lines below the panes: the key help, the
+// status line and the border, plus one while a hint is shown.
+func (m *model) footerHeight() int {
+	if m.hint != "" {
+		return 4
+	}
+	return 3
+}
+
+// setHint shows how to fix the last failure below the status line, or
+// clears it when h is empty.
+func (m *model) setHint(h string) {
+	if (h == "") != (m.hint == "") {
+		m.hint = h
+		m.resize()
+		return
+	}
+	m.hint = h
 }
 
 func (m *model) resize() {
 	leftWidth := m.leftWidth()
 	rightWidth := max(30, m.width-leftWidth-1)
 	topHeight := max(14, m.height/2)
-	bottomHeight := max(6, m.height-topHeight-3)
+	bottomHeight := max(6, m.height-topHeight-m.footerHeight())
 
 	m.viewport.Width = max(10, rightWidth-6)
 	m.viewport.Height = max(3, bottomHeight-4)
-	m.viewport.SetContent(strings.Join(m.logs, "\n"))
-	m.viewport.GotoBottom()
+	m.refreshLogs()
 }
 
 func (m *model) leftWidth() int {
@@ -530,18 +1100,48 @@ func (m *model) syncSelectedState() {
 }
 
 func (m *model) renderDot(state anim.State) string {
+	glyph := anim.StatusGlyph(state, m.glyphs)
 	switch state {
 	case anim.StateRunning:
-		return m.styles.runDot.Render("●")
+		return m.styles.runDot.Render(glyph)
 	case anim.StateInserted:
-		return m.styles.insertDot.Render("●")
+		return m.styles.insertDot.Render(glyph)
 	case anim.StateSuccess:
-		return m.styles.successDot.Render("●")
+		return m.styles.successDot.Render(glyph)
 	case anim.StateFailed:
-		return m.styles.failedDot.Render("●")
+		return m.styles.failedDot.Render(glyph)
 	default:
-		return m.styles.idleDot.Render("●")
+		return m.styles.idleDot.Render(glyph)
+	}
+}
+
+func (m *model) statusLegend() string {
+	parts := make([]string, 0, 5)
+	for _, state := range []anim.State{anim.StateSuccess, anim.StateFailed, anim.StateRunning, anim.StateInserted, anim.StateIdle} {
+		parts = append(parts, m.renderDot(state)+" "+m.tr.T("state."+string(state)))
+	}
+	return m.tr.T("legend", strings.Join(parts, "  "))
+}
+
+// glyphSet resolves ui.status_glyphs; NO_COLOR (https://no-color.org)
+// switches the default to shapes since the dots would all look the same.
+func glyphSet(cfg *config.Config) anim.GlyphSet {
+	if cfg.UI.StatusGlyphs != "" {
+		return anim.GlyphSet(cfg.UI.StatusGlyphs)
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return anim.GlyphsUnicode
+	}
+	return anim.GlyphsDots
+}
+
+// cassetteColor resolves ui.cassette_color; like the shelf dots, the art
+// falls back to plain ASCII under NO_COLOR.
+func cassetteColor(cfg *config.Config) bool {
+	if cfg.UI.CassetteColor != "" {
+		return cfg.UI.CassetteColor == "on"
 	}
+	return os.Getenv("NO_COLOR") == ""
 }
 
 func (m *model) findTape(id string) (config.Tape, bool) {
@@ -553,18 +1153,15 @@ func (m *model) findTape(id string) (config.Tape, bool) {
 	return config.Tape{}, false
 }
 
+// truncate cuts s to width cells, keeping any color escapes intact.
 func truncate(s string, width int) string {
 	if width <= 0 {
 		return ""
 	}
-	r := []rune(s)
-	if len(r) <= width {
-		return s
-	}
 	if width <= 1 {
-		return string(r[:width])
+		return ansi.Truncate(s, width, "")
 	}
-	return string(r[:width-1]) + "…"
+	return ansi.Truncate(s, width, "…")
 }
 
 func truncateLines(v string, width int) string {
@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/anim"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/pipeline"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+type pipelineMsg struct {
+	event pipeline.Event
+}
+
+// pipelineRun is what the pipeline view shows about the current or last
+// pipeline run.
+type pipelineRun struct {
+	id      string
+	steps   []string
+	status  map[string]string
+	detail  map[string]string
+	overall float64
+	result  runner.RunStatus
+}
+
+func waitPipeline(events <-chan pipeline.Event) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return pipelineMsg{event: event}
+	}
+}
+
+func (m *model) togglePipelines() {
+	m.showPipelines = !m.showPipelines
+	if m.pipelineSel >= len(m.cfg.Pipelines) {
+		m.pipelineSel = max(0, len(m.cfg.Pipelines)-1)
+	}
+}
+
+func (m *model) updatePipelines(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Pipelines), key.Matches(msg, m.keys.Dismiss):
+		m.showPipelines = false
+	case key.Matches(msg, m.keys.Up):
+		m.pipelineSel = max(0, m.pipelineSel-1)
+	case key.Matches(msg, m.keys.Down):
+		m.pipelineSel = max(0, min(len(m.cfg.Pipelines)-1, m.pipelineSel+1))
+	case key.Matches(msg, m.keys.Play), key.Matches(msg, m.keys.Insert):
+		return m.startPipeline()
+	case key.Matches(msg, m.keys.DryRun):
+		m.dryRun = !m.dryRun
+		m.status = m.tr.T("status.dry_run", m.tr.OnOff(m.dryRun))
+	}
+	return nil
+}
+
+func (m *model) startPipeline() tea.Cmd {
+	if len(m.cfg.Pipelines) == 0 {
+		return nil
+	}
+	if m.pipelineEvents != nil {
+		m.status = m.tr.T("status.pipeline_busy")
+		return nil
+	}
+	if m.runEvents != nil {
+		m.status = m.tr.T("status.pipeline_wait")
+		return nil
+	}
+	p := m.cfg.Pipelines[m.pipelineSel]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := pipeline.Start(ctx, m.runner, pipeline.Request{Config: m.cfg, Pipeline: p, DryRun: m.dryRun})
+	if err != nil {
+		cancel()
+		m.status = m.tr.T("status.start_failed")
+		m.appendLog("[pipeline] " + err.Error())
+		return nil
+	}
+
+	run := &pipelineRun{id: p.ID, status: map[string]string{}, detail: map[string]string{}}
+	if order, err := p.Order(); err == nil {
+		for _, step := range order {
+			run.steps = append(run.steps, step.ID)
+		}
+	}
+	m.lastPipeline = run
+	m.pipelineEvents = events
+	m.pipelineCancel = cancel
+	m.status = m.tr.T("status.pipeline_running", p.ID)
+	m.log.Info("pipeline started", "pipeline", p.ID, "dry_run", m.dryRun)
+	return waitPipeline(events)
+}
+
+func (m *model) handlePipeline(msg pipelineMsg) tea.Cmd {
+	event := msg.event
+	run := m.lastPipeline
+	run.overall = event.Overall
+	switch event.Type {
+	case pipeline.EventStepStarted:
+		run.status[event.Step] = "running"
+		m.appendLog(fmt.Sprintf("[pipeline] %s started", event.Step))
+	case pipeline.EventLog:
+		m.appendLog(fmt.Sprintf("[%s] %s", event.Step, event.Message))
+	case pipeline.EventStepRetrying:
+		run.status[event.Step] = "retrying"
+		run.detail[event.Step] = event.Message
+		m.appendLog(fmt.Sprintf("[pipeline] %s %s", event.Step, event.Message))
+	case pipeline.EventStepFinished:
+		result := event.Result
+		run.status[event.Step] = string(result.Status)
+		run.detail[event.Step] = result.Error
+		m.appendLog(fmt.Sprintf("[pipeline] %s %s", event.Step, strings.TrimSpace(string(result.Status)+" "+result.Error)))
+		if result.Tape != "" && result.Status != pipeline.StatusSkipped {
+			m.tapeStates[result.Tape] = anim.StateFailed
+			if result.Status == runner.StatusSuccess {
+				m.tapeStates[result.Tape] = anim.StateSuccess
+			}
+		}
+	case pipeline.EventFinished:
+		run.result = event.Record.Status
+		m.status = m.tr.T("status.pipeline_done", run.id, m.tr.T("pipelines.step_"+string(run.result)))
+		if event.RecordErr != nil {
+			m.appendLog("[record] " + event.RecordErr.Error())
+		}
+		m.log.Info("pipeline finished", "pipeline", run.id, "run_id", event.Record.RunID, "status", run.result)
+		m.pipelineCancel()
+		m.pipelineEvents = nil
+		m.pipelineCancel = nil
+		return nil
+	}
+	return waitPipeline(m.pipelineEvents)
+}
+
+func (m *model) viewPipelines() string {
+	var b strings.Builder
+	b.WriteString(m.tr.T("pipelines.title") + "\n\n")
+	if len(m.cfg.Pipelines) == 0 {
+		b.WriteString(m.tr.T("pipelines.none") + "\n")
+	}
+	for i, p := range m.cfg.Pipelines {
+		marker := "  "
+		style := m.styles.normal
+		if i == m.pipelineSel {
+			marker = "> "
+			style = m.styles.selected
+		}
+		b.WriteString(style.Render(marker+m.tr.T("pipelines.item", p.ID, len(p.Steps))) + "\n")
+	}
+
+	if run := m.lastPipeline; run != nil {
+		state := m.tr.T("pipelines.step_running")
+		if run.result != "" {
+			state = m.tr.T("pipelines.step_" + string(run.result))
+		}
+		b.WriteString("\n" + renderProgress(m.tr.T("pipelines.run", run.id, state), progress.Snapshot{Overall: run.overall}, 20) + "\n")
+		width := 0
+		for _, id := range run.steps {
+			width = max(width, lipgloss.Width(id))
+		}
+		for _, id := range run.steps {
+			status := run.status[id]
+			if status == "" {
+				status = "pending"
+			}
+			line := fmt.Sprintf("  %s %-*s %s", m.renderDot(stepState(status)), width, id, m.tr.T("pipelines.step_"+status))
+			if detail := run.detail[id]; detail != "" {
+				line += ": " + detail
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	b.WriteString("\n" + m.tr.T("pipelines.footer"))
+
+	width := max(60, min(m.width-4, 90))
+	box := m.styles.helpBox.Width(width).Render(truncateLines(b.String(), width-6))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func stepState(status string) anim.State {
+	switch status {
+	case "running", "retrying":
+		return anim.StateRunning
+	case string(runner.StatusSuccess):
+		return anim.StateSuccess
+	case string(runner.StatusFailed), string(runner.StatusCanceled):
+		return anim.StateFailed
+	default:
+		return anim.StateIdle
+	}
+}
@@ -0,0 +1,297 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/disk"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/i18n"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+// RunPlain drives the deck with line commands read from in and writes
+// timestamped status lines to out. It uses no alt screen, colors or
+// animation, so it works on dumb terminals, in logged sessions and with
+// screen readers.
+func RunPlain(cfg *config.Config, opts Options, in io.Reader, out io.Writer) error {
+	run := runner.New(nil)
+	run.SetLogger(opts.Logger)
+	d := &plainDeck{
+		cfg:     cfg,
+		runner:  run,
+		out:     out,
+		tr:      i18n.New(i18n.Detect(cfg.UI.Locale)),
+		results: map[string]string{},
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	d.say(d.tr.T("plain.ready", len(cfg.Tapes)))
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				d.shutdown()
+				return nil
+			}
+			if quit := d.handle(strings.Fields(line)); quit {
+				d.shutdown()
+				return nil
+			}
+		case event, ok := <-d.events:
+			if !ok {
+				d.events = nil
+				continue
+			}
+			d.handleEvent(event)
+		case line, ok := <-opts.LogLines:
+			if !ok {
+				opts.LogLines = nil
+				continue
+			}
+			d.say("log: " + line)
+		}
+	}
+}
+
+type plainDeck struct {
+	cfg    *config.Config
+	runner *runner.Runner
+	out    io.Writer
+	tr     *i18n.Catalog
+
+	dryRun  bool
+	logs    bool
+	pending []queue.Job
+	results map[string]string
+
+	events    <-chan runner.Event
+	cancel    context.CancelFunc
+	running   queue.Job
+	lastShown int
+}
+
+func (d *plainDeck) say(msg string) {
+	fmt.Fprintf(d.out, "%s %s\n", time.Now().Format("15:04:05"), msg)
+}
+
+func (d *plainDeck) handle(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+	switch strings.ToLower(fields[0]) {
+	case "help", "?":
+		for _, line := range strings.Split(d.tr.T("plain.help"), "\n") {
+			d.say(line)
+		}
+	case "list", "ls":
+		for i, tape := range d.cfg.Tapes {
+			result := d.results[tape.ID]
+			if result == "" {
+				result = d.tr.T("plain.not_run")
+			}
+			state := ""
+			if tape.Disabled {
+				state = d.tr.T("plain.disabled_suffix")
+			}
+			d.say(d.tr.T("plain.list_item", i+1, tape.ID, tape.Name, tape.Mode, state, result))
+		}
+	case "play":
+		d.enqueue(arg, runner.ActionPrimary)
+	case "preview":
+		d.enqueue(arg, runner.ActionPreview)
+	case "verify":
+		d.enqueue(arg, runner.ActionVerify)
+	case "cancel":
+		if d.cancel == nil {
+			d.say(d.tr.T("plain.nothing_running"))
+			return false
+		}
+		d.cancel()
+		d.say(d.tr.T("plain.cancel_requested", d.running.TapeID))
+	case "dry":
+		d.dryRun = arg == "on"
+		d.say(d.tr.T("plain.dry_run", d.tr.OnOff(d.dryRun)))
+	case "logs":
+		d.logs = arg == "on"
+		d.say(d.tr.T("plain.logs", d.tr.OnOff(d.logs)))
+	case "status":
+		if d.events == nil {
+			d.say(d.tr.T("plain.idle", d.tr.OnOff(d.dryRun)))
+		} else {
+			d.say(d.tr.T("plain.running", d.running.TapeID, d.running.Action))
+		}
+		if len(d.pending) > 0 {
+			d.say(d.tr.T("plain.queued_count", len(d.pending)))
+		}
+	case "quit", "exit", "q":
+		return true
+	default:
+		d.say(d.tr.T("plain.unknown_command", fields[0]))
+	}
+	return false
+}
+
+func (d *plainDeck) enqueue(ref string, action runner.Action) {
+	tape, ok := d.lookup(ref)
+	if !ok {
+		d.say(d.tr.T("plain.unknown_tape", ref))
+		return
+	}
+	if tape.Disabled {
+		d.say(d.tr.T("plain.tape_disabled", tape.ID))
+		return
+	}
+	if action == runner.ActionPreview && !tape.Preview.Enabled {
+		d.say(d.tr.T("plain.no_preview", tape.ID))
+		return
+	}
+	if action == runner.ActionVerify && !tape.Golden.Enabled() {
+		d.say(d.tr.T("plain.no_golden", tape.ID))
+		return
+	}
+	job := queue.Job{TapeID: tape.ID, Action: action, DryRun: d.dryRun, EnqueuedAt: time.Now()}
+	if floor := d.cfg.Disk.MinFreeBytes(); floor > 0 && !job.DryRun {
+		if free, err := disk.Free(tape.OutputDir); err == nil && free < floor {
+			d.say(d.tr.T("plain.low_disk", disk.Format(int64(free)), tape.OutputDir))
+		}
+	}
+	if d.events != nil {
+		d.pending = append(d.pending, job)
+		d.say(d.tr.T("plain.queued", tape.ID, action, len(d.pending)))
+		return
+	}
+	d.start(job)
+}
+
+func (d *plainDeck) lookup(ref string) (config.Tape, bool) {
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(d.cfg.Tapes) {
+		return d.cfg.Tapes[n-1], true
+	}
+	for _, tape := range d.cfg.Tapes {
+		if tape.ID == ref {
+			return tape, true
+		}
+	}
+	return config.Tape{}, false
+}
+
+func (d *plainDeck) start(job queue.Job) {
+	tape, ok := d.lookup(job.TapeID)
+	if !ok {
+		d.say(d.tr.T("plain.tape_gone", job.TapeID))
+		d.startNext()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := d.runner.Start(ctx, runner.Request{Config: d.cfg, Tape: tape, Action: job.Action, DryRun: job.DryRun})
+	if err != nil {
+		cancel()
+		d.say(d.tr.T("plain.start_failed", tape.ID, err))
+		if h := hint.Of(err); h != "" {
+			d.say(d.tr.T("plain.hint", h))
+		}
+		d.startNext()
+		return
+	}
+	d.events = events
+	d.cancel = cancel
+	d.running = job
+	d.lastShown = -1
+}
+
+func (d *plainDeck) startNext() {
+	if d.events != nil || len(d.pending) == 0 {
+		return
+	}
+	next := d.pending[0]
+	d.pending = d.pending[1:]
+	d.start(next)
+}
+
+func (d *plainDeck) handleEvent(event runner.Event) {
+	switch event.Type {
+	case runner.EventStarted:
+		d.say(d.tr.T("plain.started", d.running.TapeID, d.running.Action))
+		if d.logs {
+			d.say("$ " + event.Message)
+		}
+	case runner.EventLog:
+		if d.logs {
+			d.say(event.Message)
+		}
+	case runner.EventStalled:
+		d.say(d.tr.T("plain.warning", event.Message))
+	case runner.EventProgress:
+		// One line per 10% keeps screen readers from drowning in updates.
+		step := int(event.Progress.Overall * 10)
+		if step > d.lastShown {
+			d.lastShown = step
+			d.say(d.tr.T("plain.progress", d.running.TapeID, step*10, event.Progress.Phase))
+		}
+	case runner.EventFinished:
+		result := d.tr.T("plain.success")
+		if event.ExitCode != 0 {
+			result = d.tr.T("plain.failed", event.ExitCode)
+			if event.Record != nil && event.Record.Status == runner.StatusCanceled {
+				result = d.tr.T("plain.canceled")
+			}
+		}
+		d.results[d.running.TapeID] = result
+		d.say(d.tr.T("plain.finished", d.running.TapeID, result, event.Message))
+		if event.Hint != "" {
+			d.say(d.tr.T("plain.hint", event.Hint))
+		}
+		if event.Record != nil && len(event.Record.OutputPaths) > 0 && event.ExitCode == 0 && !event.Record.DryRun {
+			d.say(d.tr.T("plain.output", event.Record.OutputPaths[0]))
+		}
+		if event.Record != nil && event.ExitCode == 0 {
+			for _, u := range event.Record.Uploads {
+				if u.URL != "" {
+					d.say(d.tr.T("plain.uploaded", u.URL))
+				}
+			}
+		}
+		if event.RecordErr != nil {
+			d.say(d.tr.T("plain.record_error", event.RecordErr))
+		}
+		d.cancel()
+		d.events = nil
+		d.cancel = nil
+		d.startNext()
+	}
+}
+
+func (d *plainDeck) shutdown() {
+	d.pending = nil
+	if d.cancel == nil {
+		return
+	}
+	d.say(d.tr.T("plain.canceling", d.running.TapeID))
+	d.cancel()
+	for event := range d.events {
+		if event.Type == runner.EventFinished {
+			d.handleEvent(event)
+			break
+		}
+	}
+}
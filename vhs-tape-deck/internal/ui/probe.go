@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+)
+
+type probeMsg struct {
+	path string
+	info probe.Info
+	err  error
+}
+
+type probeResult struct {
+	pending bool
+	info    probe.Info
+	err     error
+}
+
+func probeCmd(cache *probe.Cache, path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		info, err := cache.Probe(ctx, "", path)
+		return probeMsg{path: path, info: info, err: err}
+	}
+}
+
+// probeOutput records path as the tape's latest output and inspects it in
+// the background.
+func (m *model) probeOutput(tapeID, path string) tea.Cmd {
+	m.lastOutputs[tapeID] = path
+	if m.probes == nil {
+		return nil
+	}
+	m.probed[path] = probeResult{pending: true}
+	return probeCmd(m.probes, path)
+}
+
+// probeSelected inspects the selected tape's latest output when it was
+// loaded from history and has not been probed this session. Outputs probed
+// before are answered from the hash cache without running ffprobe.
+func (m *model) probeSelected() tea.Cmd {
+	if m.probes == nil || m.selected < 0 || m.selected >= len(m.cfg.Tapes) {
+		return nil
+	}
+	path, ok := m.lastOutputs[m.cfg.Tapes[m.selected].ID]
+	if !ok {
+		return nil
+	}
+	if _, done := m.probed[path]; done {
+		return nil
+	}
+	m.probed[path] = probeResult{pending: true}
+	return probeCmd(m.probes, path)
+}
+
+func (m *model) handleProbe(msg probeMsg) {
+	m.probed[msg.path] = probeResult{info: msg.info, err: msg.err}
+	switch {
+	case errors.Is(msg.err, probe.ErrNotInstalled):
+	case msg.err != nil:
+		m.appendLog("[probe] " + msg.err.Error())
+	default:
+		m.log.Info("output probed", "path", msg.path, "codec", msg.info.Codec, "alpha", msg.info.HasAlpha)
+	}
+}
+
+func (m *model) outputMeta(tapeID string) []string {
+	path, ok := m.lastOutputs[tapeID]
+	if !ok {
+		return nil
+	}
+	meta := []string{"", m.tr.T("meta.last_output", filepath.Base(path))}
+	res, ok := m.probed[path]
+	switch {
+	case !ok:
+	case res.pending:
+		meta = append(meta, m.tr.T("meta.probe_running"))
+	case errors.Is(res.err, probe.ErrNotInstalled):
+		meta = append(meta, m.tr.T("meta.probe_missing"))
+	case res.err != nil:
+		meta = append(meta, m.tr.T("meta.probe_failed"))
+	default:
+		meta = append(meta, m.tr.T("meta.probe", res.info.Summary()))
+	}
+	return meta
+}
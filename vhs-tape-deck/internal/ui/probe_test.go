@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/probe"
+)
+
+func TestProbeSelectedUsesCacheForHistoryOutputs(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "alpha.mov")
+	if err := os.WriteFile(output, []byte("rendered"), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+	hash, err := probe.HashFile(output)
+	if err != nil {
+		t.Fatalf("HashFile: %v", err)
+	}
+	cache, _ := probe.OpenCache("")
+	want := probe.Info{Codec: "prores", PixFmt: "yuva444p12le", HasAlpha: true}
+	if err := cache.Store(hash, want); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	// As after loadHistory: the output is known but was not probed.
+	m := &model{
+		cfg:         &config.Config{Tapes: []config.Tape{{ID: "alpha"}, {ID: "beta"}}},
+		probes:      cache,
+		probed:      map[string]probeResult{},
+		lastOutputs: map[string]string{"alpha": output},
+	}
+	cmd := m.probeSelected()
+	if cmd == nil || !m.probed[output].pending {
+		t.Fatalf("expected the history output to be probed")
+	}
+	msg, ok := cmd().(probeMsg)
+	if !ok || msg.err != nil || msg.info != want {
+		t.Fatalf("expected the cached info, got %+v", msg)
+	}
+	if m.probeSelected() != nil {
+		t.Fatalf("a pending probe should not start again")
+	}
+
+	m.selected = 1
+	if m.probeSelected() != nil {
+		t.Fatalf("a tape without an output should not be probed")
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/session"
+)
+
+// prompt is a modal yes/no question; while one is open it captures all keys
+// except quit and cancel.
+type prompt struct {
+	title string
+	body  string
+	yes   func() tea.Cmd
+	no    func()
+	// then is shown once this prompt is answered.
+	then *prompt
+}
+
+// queuePrompt shows p now, or after any prompts already waiting.
+func (m *model) queuePrompt(p *prompt) {
+	if m.prompt == nil {
+		m.prompt = p
+		return
+	}
+	last := m.prompt
+	for last.then != nil {
+		last = last.then
+	}
+	last.then = p
+}
+
+func (m *model) answerPrompt(msg tea.KeyMsg) tea.Cmd {
+	p := m.prompt
+	switch {
+	case key.Matches(msg, m.keys.Confirm):
+		m.prompt = p.then
+		if p.yes != nil {
+			return p.yes()
+		}
+	case key.Matches(msg, m.keys.Dismiss):
+		m.prompt = p.then
+		if p.no != nil {
+			p.no()
+		}
+	}
+	return nil
+}
+
+func (m *model) viewPromptOverlay() string {
+	body := m.prompt.body + "\n\n" + m.tr.T("prompt.yes_no")
+	box := m.styles.helpBox.Render(m.prompt.title + "\n\n" + body)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m *model) restorePrompt(st session.State) *prompt {
+	var b strings.Builder
+	if st.Crashed {
+		b.WriteString(m.tr.T("prompt.crashed") + "\n\n")
+	} else {
+		b.WriteString(m.tr.T("prompt.saved") + "\n\n")
+	}
+	if st.InsertedTapeID != "" {
+		b.WriteString(m.tr.T("prompt.inserted", st.InsertedTapeID) + "\n")
+	}
+	if st.SelectedTapeID != "" {
+		b.WriteString(m.tr.T("prompt.selected", st.SelectedTapeID) + "\n")
+	}
+	if !st.SavedAt.IsZero() {
+		b.WriteString(m.tr.T("prompt.saved_at", st.SavedAt.Local().Format("2006-01-02 15:04:05")) + "\n")
+	}
+	b.WriteString("\n" + m.tr.T("prompt.restore"))
+
+	return &prompt{
+		title: m.tr.T("prompt.restore_title"),
+		body:  b.String(),
+		yes: func() tea.Cmd {
+			m.applySession(st)
+			return nil
+		},
+		no: func() {
+			m.status = m.tr.T("status.session_discarded")
+		},
+	}
+}
+
+func (m *model) resumePrompt(jobs []queue.Job) *prompt {
+	var b strings.Builder
+	b.WriteString(m.tr.T("prompt.resume_header", len(jobs)) + "\n\n")
+	for i, job := range jobs {
+		if i == 8 {
+			b.WriteString(m.tr.T("prompt.resume_more", len(jobs)-i) + "\n")
+			break
+		}
+		fmt.Fprintf(&b, "  %s (%s)\n", job.TapeID, job.Action)
+	}
+	b.WriteString("\n" + m.tr.T("prompt.resume"))
+
+	return &prompt{
+		title: m.tr.T("prompt.resume_title"),
+		body:  b.String(),
+		yes: func() tea.Cmd {
+			m.pending = append(m.pending, jobs...)
+			m.saveQueue()
+			return m.startNextQueued()
+		},
+		no: func() {
+			m.status = m.tr.T("status.queue_discarded")
+		},
+	}
+}
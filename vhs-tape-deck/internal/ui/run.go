@@ -1,15 +1,150 @@
 package ui
 
 import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 
-	"vhs-tape-deck/internal/config"
-	"vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/logging"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/pipeline"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/queue"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/session"
 )
 
-func Run(cfg *config.Config) error {
-	m := NewModel(cfg, runner.New(nil))
-	p := tea.NewProgram(m, tea.WithAltScreen())
+type Options struct {
+	Logger *slog.Logger
+	// LogLines, when set, streams formatted log records into the log pane.
+	LogLines <-chan string
+	// Restore is a previous session offered to the user at startup.
+	Restore *session.State
+	// Prefs is the last saved session, whose shelf preferences are applied
+	// whether or not it is restored.
+	Prefs *session.State
+	// Resume holds renders left pending by a previous run of the deck.
+	Resume []queue.Job
+}
+
+func Run(cfg *config.Config, opts Options) error {
+	run := runner.New(nil)
+	run.SetLogger(opts.Logger)
+
+	sessionPath := ""
+	if cfg.Path != "" {
+		sessionPath = session.Path(filepath.Dir(cfg.Path))
+		if st, err := session.Load(sessionPath); err == nil && st != nil {
+			if opts.Prefs == nil {
+				opts.Prefs = st
+			}
+			if opts.Restore == nil && st.Restorable() {
+				opts.Restore = st
+			}
+		}
+	}
+
+	if opts.Resume == nil && cfg.RunsDir != "" {
+		opts.Resume = recoverQueue(queue.Path(cfg.RunsDir), opts.Logger)
+	}
+
+	m := newModel(cfg, run, opts)
+	guard := &crashGuard{inner: m}
+	p := tea.NewProgram(guard, tea.WithAltScreen())
+	guard.send = p.Send
 	_, err := p.Run()
+
+	if m.runCancel != nil {
+		m.runCancel()
+		drainRun(m.runEvents)
+		st := m.queueState()
+		if err := queue.Interrupt(st); err != nil {
+			m.log.Error("mark interrupted run", "err", err)
+		}
+		if m.queuePath != "" {
+			if err := queue.Save(m.queuePath, st); err != nil {
+				m.log.Error("save queue", "err", err)
+			}
+		}
+	}
+
+	if m.pipelineCancel != nil {
+		m.pipelineCancel()
+		drainPipeline(m.pipelineEvents)
+	}
+
+	st := m.sessionState()
+	st.SavedAt = time.Now()
+	st.Crashed = guard.crashed()
+	if sessionPath != "" {
+		if saveErr := session.Save(sessionPath, st); saveErr != nil {
+			m.log.Error("save session", "err", saveErr)
+		}
+	}
+
+	if guard.crashed() {
+		m.log.Error("ui panic", "panic", fmt.Sprint(guard.value))
+		report, reportErr := guard.writeCrashReport(st.SavedAt)
+		if reportErr != nil {
+			return fmt.Errorf("tape deck crashed (%v); crash report failed: %w", guard.value, reportErr)
+		}
+		return fmt.Errorf("tape deck crashed (%v); report written to %s", guard.value, report)
+	}
 	return err
 }
+
+// recoverQueue marks runs interrupted by a crash as aborted and returns the
+// jobs that can be resumed.
+func recoverQueue(path string, logger *slog.Logger) []queue.Job {
+	log := logging.Component(logger, "queue")
+	st, err := queue.Load(path)
+	if err != nil {
+		log.Error("load queue", "path", path, "err", err)
+		return nil
+	}
+	jobs, err := queue.Recover(st)
+	if err != nil {
+		log.Error("recover queue", "path", path, "err", err)
+		return nil
+	}
+	if err := queue.Save(path, st); err != nil {
+		log.Error("save queue", "path", path, "err", err)
+	}
+	return jobs
+}
+
+// drainRun waits briefly for a canceled run to write its record so it can be
+// overwritten as aborted.
+func drainRun(events <-chan runner.Event) {
+	if events == nil {
+		return
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// drainPipeline waits briefly for a canceled pipeline to write its record.
+func drainPipeline(events <-chan pipeline.Event) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/share"
+)
+
+type shareMsg struct {
+	fraction float64
+	done     bool
+	output   string
+	err      error
+}
+
+func waitShare(events <-chan shareMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func (m *model) cyclePreset() {
+	names := share.PresetNames()
+	for i, name := range names {
+		if name == m.sharePreset {
+			m.sharePreset = names[(i+1)%len(names)]
+			break
+		}
+	}
+	p := share.Presets[m.sharePreset]
+	m.status = m.tr.T("status.gif_preset", p.Name, p.Width, p.FPS)
+}
+
+func (m *model) shareOutput() tea.Cmd {
+	if len(m.cfg.Tapes) == 0 {
+		return nil
+	}
+	if m.shareEvents != nil {
+		m.status = m.tr.T("status.gif_busy")
+		return nil
+	}
+	tape := m.cfg.Tapes[m.selected]
+	input, ok := m.lastOutputs[tape.ID]
+	if !ok {
+		m.status = m.tr.T("status.no_output")
+		return nil
+	}
+
+	opts := share.Options{Input: input, Format: share.FormatGIF, Preset: share.Presets[m.sharePreset]}
+	opts.Output = share.DefaultOutput(input, opts.Format, opts.Preset)
+	var expected time.Duration
+	if res, ok := m.probed[input]; ok && res.err == nil {
+		expected = time.Duration(res.info.Duration * float64(time.Second))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan shareMsg, 16)
+	go func() {
+		defer close(events)
+		err := share.Convert(ctx, "", opts, expected, func(f float64) {
+			select {
+			case events <- shareMsg{fraction: f}:
+			default:
+			}
+		})
+		events <- shareMsg{done: true, output: opts.Output, err: err}
+	}()
+
+	m.shareEvents = events
+	m.shareCancel = cancel
+	m.shareTapeID = tape.ID
+	m.shareProgress = &progress.Snapshot{Phase: progress.Phase(opts.Format)}
+	m.status = m.tr.T("status.creating", filepath.Base(opts.Output))
+	m.appendLog(fmt.Sprintf("[share] %s -> %s (%s)", filepath.Base(input), filepath.Base(opts.Output), opts.Preset.Name))
+	m.log.Info("share started", "tape", tape.ID, "input", input, "output", opts.Output, "preset", opts.Preset.Name)
+	return waitShare(events)
+}
+
+func (m *model) handleShare(msg shareMsg) tea.Cmd {
+	if !msg.done {
+		m.shareProgress.Overall = msg.fraction
+		m.shareProgress.PhaseFraction = msg.fraction
+		return waitShare(m.shareEvents)
+	}
+
+	tapeID := m.shareTapeID
+	m.shareCancel()
+	m.shareEvents = nil
+	m.shareCancel = nil
+	m.shareTapeID = ""
+	m.shareProgress = nil
+	if msg.err != nil {
+		m.status = m.tr.T("status.gif_failed")
+		m.appendLog("[share] " + msg.err.Error())
+		m.log.Error("share failed", "tape", tapeID, "err", msg.err)
+		return nil
+	}
+
+	m.status = m.tr.T("status.wrote", filepath.Base(msg.output))
+	m.appendLog("[share] wrote " + msg.output)
+	if recordPath, ok := m.lastRecords[tapeID]; ok {
+		if err := runner.AddArtifact(recordPath, msg.output); err != nil {
+			m.appendLog("[record] " + err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+type shelfSort string
+
+const (
+	sortConfig shelfSort = "config"
+	sortName   shelfSort = "name"
+	sortRecent shelfSort = "recent"
+	sortStatus shelfSort = "status"
+)
+
+var shelfSorts = []shelfSort{sortConfig, sortName, sortRecent, sortStatus}
+
+func parseShelfSort(v string) (shelfSort, bool) {
+	for _, s := range shelfSorts {
+		if string(s) == v {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// statusRank orders tapes for the status sort: tapes needing attention
+// first, never-run tapes last.
+func statusRank(status runner.RunStatus, ran bool) int {
+	if !ran {
+		return 4
+	}
+	switch status {
+	case runner.StatusFailed:
+		return 0
+	case runner.StatusAborted, runner.StatusCanceled:
+		return 1
+	case runner.StatusSuccess:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// shelf returns the indexes into cfg.Tapes shown on the shelf, in display
+// order. Disabled tapes are left out unless the toggle is on, or unless
+// every tape is disabled.
+func (m *model) shelf() []int {
+	out := make([]int, 0, len(m.cfg.Tapes))
+	for i, tape := range m.cfg.Tapes {
+		if tape.Disabled && !m.showDisabled {
+			continue
+		}
+		out = append(out, i)
+	}
+	if len(out) == 0 {
+		for i := range m.cfg.Tapes {
+			out = append(out, i)
+		}
+	}
+	m.sortShelf(out)
+	return out
+}
+
+func (m *model) sortShelf(idx []int) {
+	tapes := m.cfg.Tapes
+	switch m.shelfSort {
+	case sortName:
+		sort.SliceStable(idx, func(a, b int) bool {
+			return strings.ToLower(tapes[idx[a]].Name) < strings.ToLower(tapes[idx[b]].Name)
+		})
+	case sortRecent:
+		sort.SliceStable(idx, func(a, b int) bool {
+			ra, okA := m.lastRuns[tapes[idx[a]].ID]
+			rb, okB := m.lastRuns[tapes[idx[b]].ID]
+			if okA != okB {
+				return okA
+			}
+			return ra.Timestamp.After(rb.Timestamp)
+		})
+	case sortStatus:
+		sort.SliceStable(idx, func(a, b int) bool {
+			ra, okA := m.lastRuns[tapes[idx[a]].ID]
+			rb, okB := m.lastRuns[tapes[idx[b]].ID]
+			rankA, rankB := statusRank(ra.Status, okA), statusRank(rb.Status, okB)
+			if rankA != rankB {
+				return rankA < rankB
+			}
+			return ra.Timestamp.After(rb.Timestamp)
+		})
+	}
+}
+
+func (m *model) cycleSort() {
+	for i, s := range shelfSorts {
+		if s == m.shelfSort {
+			m.shelfSort = shelfSorts[(i+1)%len(shelfSorts)]
+			break
+		}
+	}
+	m.status = m.tr.T("status.sorted", m.tr.T("sort."+string(m.shelfSort)))
+}
+
+func (m *model) moveSelection(delta int) {
+	shelf := m.shelf()
+	for pos, i := range shelf {
+		if i != m.selected {
+			continue
+		}
+		next := pos + delta
+		if next >= 0 && next < len(shelf) {
+			m.selected = shelf[next]
+		}
+		return
+	}
+	if len(shelf) > 0 {
+		m.selected = shelf[0]
+	}
+}
+
+// ensureVisibleSelection moves the cursor to the first shelf entry when the
+// selected tape is hidden.
+func (m *model) ensureVisibleSelection() {
+	shelf := m.shelf()
+	for _, i := range shelf {
+		if i == m.selected {
+			return
+		}
+	}
+	if len(shelf) > 0 {
+		m.selected = shelf[0]
+	}
+}
+
+func (m *model) toggleShowDisabled() {
+	m.showDisabled = !m.showDisabled
+	hidden := 0
+	for _, tape := range m.cfg.Tapes {
+		if tape.Disabled {
+			hidden++
+		}
+	}
+	if m.showDisabled {
+		m.status = m.tr.T("status.showing_disabled")
+	} else {
+		m.status = m.tr.T("status.hiding_disabled")
+	}
+	if hidden == 0 {
+		m.status = m.tr.T("status.no_disabled")
+	}
+	m.ensureVisibleSelection()
+}
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/capture"
+)
+
+// saveSnapshot writes the frame on screen under <runs_dir>/screenshots as
+// ANSI, plain text and SVG.
+func (m *model) saveSnapshot() {
+	paths, err := capture.Write(capture.Dir(m.cfg.RunsDir), "deck", m.View(), time.Now())
+	if err != nil {
+		m.status = m.tr.T("status.snapshot_failed")
+		m.appendLog("[screenshot] " + err.Error())
+		return
+	}
+	m.status = m.tr.T("status.wrote", paths[len(paths)-1])
+	m.appendLog("[screenshot] wrote " + strings.Join(paths, ", "))
+}
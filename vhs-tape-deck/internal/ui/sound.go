@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+)
+
+// soundTimeout stops a sound command that hangs, such as a player waiting
+// on an audio device.
+const soundTimeout = 10 * time.Second
+
+type soundMsg struct {
+	err error
+}
+
+// playSound runs a ui.sounds command in the background. Failures are only
+// logged; a missing player should never get in the way of a render.
+func playSound(command []string) tea.Cmd {
+	if len(command) == 0 {
+		return nil
+	}
+	if len(command) == 1 && command[0] == config.SoundBell {
+		return func() tea.Msg {
+			_, err := os.Stdout.WriteString("\a")
+			return soundMsg{err: err}
+		}
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), soundTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+		if err != nil {
+			return soundMsg{err: fmt.Errorf("%s: %w: %s", command[0], err, strings.TrimSpace(string(out)))}
+		}
+		return soundMsg{}
+	}
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/changelog"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/version"
+)
+
+// whatsNewSince picks the releases to announce at startup. Sessions saved
+// before the deck recorded versions only get the latest release.
+func whatsNewSince(lastSeen string) []changelog.Release {
+	releases := changelog.Since(lastSeen)
+	if lastSeen == "" && len(releases) > 1 {
+		releases = releases[:1]
+	}
+	return releases
+}
+
+func (m *model) toggleWhatsNew() {
+	if m.whatsNew != nil {
+		m.whatsNew = nil
+		return
+	}
+	m.whatsNew = whatsNewSince("")
+}
+
+func (m *model) viewWhatsNew() string {
+	box := m.renderWhatsNew(m.whatsNew)
+	// Fall back to the newest release alone when several do not fit.
+	if lipgloss.Height(box) > m.height && len(m.whatsNew) > 1 {
+		box = m.renderWhatsNew(m.whatsNew[:1])
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m *model) renderWhatsNew(releases []changelog.Release) string {
+	var b strings.Builder
+	b.WriteString(m.tr.T("whatsnew.title", version.Deck) + "\n")
+	for _, r := range releases {
+		b.WriteString("\n" + r.Version + "\n")
+		for _, f := range r.Features {
+			b.WriteString("  • " + f + "\n")
+		}
+		if len(r.Keys) > 0 {
+			b.WriteString(m.tr.T("whatsnew.keys") + " " + strings.Join(r.Keys, ", ") + "\n")
+		}
+	}
+	b.WriteString("\n" + m.tr.T("whatsnew.dismiss"))
+	width := max(60, min(m.width-4, 80))
+	return m.styles.helpBox.Width(width).Render(b.String())
+}
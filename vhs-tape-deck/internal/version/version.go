@@ -0,0 +1,97 @@
+// Package version identifies the tape deck build and compares dotted
+// version strings such as "0.3.0" or "v1.2.0-rc1".
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Deck is the tape deck version. Release builds may override it with
+// -ldflags "-X github.com/coltonbatts/VCR/vhs-tape-deck/internal/version.Deck=1.2.3".
+var Deck = "0.3.0"
+
+var versionPattern = regexp.MustCompile(`\bv?\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.]+)?`)
+
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release suffix after "-", if any.
+	Pre string
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Parse accepts an optional "v" prefix, one to three numeric parts and an
+// optional "-pre" or "+build" suffix; build metadata is dropped.
+func Parse(s string) (Version, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var v Version
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.Pre = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", raw)
+	}
+	nums := [3]int{}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// Find extracts the first version number from free-form output such as
+// "vcr 0.1.2" or "vcr version v0.2.0-dev".
+func Find(s string) (Version, bool) {
+	m := versionPattern.FindString(s)
+	if m == "" {
+		return Version{}, false
+	}
+	v, err := Parse(m)
+	return v, err == nil
+}
+
+// Compare returns -1, 0 or 1. A pre-release sorts before its release.
+func (v Version) Compare(o Version) int {
+	for _, d := range [3]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	return sign(strings.Compare(v.Pre, o.Pre))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package version
+
+import "testing"
+
+func TestParseAndCompare(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"0.3.0", "v0.3", 0},
+		{"1.2.10", "1.2.9", 1},
+		{"1.2.0-rc1", "1.2.0", -1},
+		{"1.2.0+abc", "1.2.0", 0},
+		{"2", "1.9.9", 1},
+	}
+	for _, tc := range cases {
+		a, err := Parse(tc.a)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.a, err)
+		}
+		b, err := Parse(tc.b)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.b, err)
+		}
+		if got := a.Compare(b); got != tc.want {
+			t.Fatalf("Compare(%s, %s) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	for out, want := range map[string]string{"vcr 0.1.2\n": "0.1.2", "vcr version v0.2.0-dev (abc)": "0.2.0-dev"} {
+		v, ok := Find(out)
+		if !ok || v.String() != want {
+			t.Fatalf("Find(%q) = %s %v, want %s", out, v, ok, want)
+		}
+	}
+	if _, ok := Find("vcr: command not found"); ok {
+		t.Fatalf("expected no version in error output")
+	}
+
+	for _, bad := range []string{"", "one.two", "1.2.3.4", "1.-2"} {
+		if _, err := Parse(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package vcrrun lets other Go programs drive vcr renders the way tape-deck
+// does: same config file, command plans, events and run records, without
+// shelling out to the deck.
+//
+// The names exported here are the stable surface. They alias the deck's
+// internal types, so values pass between the two unchanged, and they only
+// change with a major version of the deck.
+package vcrrun
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/config"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/hint"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/progress"
+	"github.com/coltonbatts/VCR/vhs-tape-deck/internal/runner"
+)
+
+var errNoRecord = errors.New("run ended without a record")
+
+// Failures with a known fix; match them with errors.Is and show Hint(err)
+// to the user.
+var (
+	ErrConfigMissing         = config.ErrConfigMissing
+	ErrBinaryNotFound        = runner.ErrBinaryNotFound
+	ErrManifestMissing       = runner.ErrManifestMissing
+	ErrUnsupportedSubcommand = runner.ErrUnsupportedSubcommand
+	ErrVCROutdated           = runner.ErrVCROutdated
+	ErrNoPreview             = runner.ErrNoPreview
+	ErrNoGolden              = runner.ErrNoGolden
+)
+
+// Hint returns how to fix err, or "" when there is no known fix. Failed
+// runs carry theirs in the finished Event's Hint.
+func Hint(err error) string {
+	return hint.Of(err)
+}
+
+type (
+	Config      = config.Config
+	Tape        = config.Tape
+	Request     = runner.Request
+	CommandPlan = runner.CommandPlan
+	Event       = runner.Event
+	EventType   = runner.EventType
+	Action      = runner.Action
+	RunStatus   = runner.RunStatus
+	RunRecord   = runner.RunRecord
+	Progress    = progress.Snapshot
+)
+
+const (
+	ActionPrimary = runner.ActionPrimary
+	ActionPreview = runner.ActionPreview
+	ActionVerify  = runner.ActionVerify
+
+	EventStarted  = runner.EventStarted
+	EventLog      = runner.EventLog
+	EventProgress = runner.EventProgress
+	EventStalled  = runner.EventStalled
+	EventFinished = runner.EventFinished
+
+	StatusSuccess  = runner.StatusSuccess
+	StatusFailed   = runner.StatusFailed
+	StatusCanceled = runner.StatusCanceled
+	StatusAborted  = runner.StatusAborted
+)
+
+// LoadConfig reads and validates a tape-deck config. Relative paths in it
+// resolve against workDir, as they would for a deck launched there.
+func LoadConfig(path, workDir string) (*Config, error) {
+	return config.Load(path, workDir)
+}
+
+// DefaultConfigPath is where the deck looks for its config when none is
+// given.
+func DefaultConfigPath() (string, error) {
+	return config.DefaultConfigPath()
+}
+
+// Runner starts renders. It is safe for concurrent use; concurrent runs of
+// the same tape get distinct run ids.
+type Runner struct {
+	r *runner.Runner
+}
+
+func New() *Runner {
+	return &Runner{r: runner.New(nil)}
+}
+
+// SetLogger receives the runner's structured logs; they are discarded by
+// default.
+func (r *Runner) SetLogger(logger *slog.Logger) {
+	r.r.SetLogger(logger)
+}
+
+// BuildPlan resolves the command a request would run without running it.
+// Run ids are only reserved while a run is going, so two plans built in a
+// row may carry the same id.
+func (r *Runner) BuildPlan(req Request) (*CommandPlan, error) {
+	plan, _, err := r.r.BuildPlan(req)
+	if err != nil {
+		return nil, err
+	}
+	runner.ReleaseClaim(plan)
+	return plan, nil
+}
+
+// Run starts a render. The channel delivers EventStarted first and closes
+// after EventFinished, whose Record has been written to the runs dir.
+// Canceling ctx interrupts vcr and records the run as canceled.
+func (r *Runner) Run(ctx context.Context, req Request) (<-chan Event, error) {
+	return r.r.Start(ctx, req)
+}
+
+// Wait drains events and returns the finished run's record.
+func Wait(events <-chan Event) (*RunRecord, error) {
+	var finished Event
+	for event := range events {
+		if event.Type == EventFinished {
+			finished = event
+		}
+	}
+	if finished.Record == nil {
+		return nil, errNoRecord
+	}
+	return finished.Record, finished.RecordErr
+}
+
+// Subscribe follows a run started by this Runner that has not finished,
+// beginning with its most recent events.
+func (r *Runner) Subscribe(runID string) (<-chan Event, func(), bool) {
+	return r.r.Subscribe(runID)
+}
+
+func ReadRunRecord(path string) (*RunRecord, error) {
+	return runner.ReadRunRecord(path)
+}
+
+// LoadRunRecords returns the readable records in runsDir, oldest first.
+// Unreadable records are moved to <runs_dir>/records/quarantine, as the deck
+// does.
+func LoadRunRecords(runsDir string) ([]RunRecord, error) {
+	return runner.LoadRunRecords(runsDir)
+}
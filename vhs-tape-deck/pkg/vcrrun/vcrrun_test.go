@@ -0,0 +1,125 @@
+package vcrrun
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunRendersAndWritesRecord(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/true")
+	}
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	yaml := "vcr_binary: /bin/true\ntapes:\n  - id: alpha\n    manifest: ./alpha.yaml\n    mode: video\n"
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := LoadConfig(cfgPath, tmp)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	req := Request{Config: cfg, Tape: cfg.Tapes[0], Action: ActionPrimary, DryRun: true}
+
+	r := New()
+	plan, err := r.BuildPlan(req)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if plan.Binary != "/bin/true" || plan.RunID == "" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(cfg.RunsDir, "claims")); len(entries) != 0 {
+		t.Fatalf("BuildPlan left %d run id claims behind", len(entries))
+	}
+
+	events, err := r.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	record, err := Wait(events)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if record.Status != StatusSuccess || record.TapeID != "alpha" {
+		t.Fatalf("unexpected record: status=%s tape=%s", record.Status, record.TapeID)
+	}
+	records, err := LoadRunRecords(cfg.RunsDir)
+	if err != nil {
+		t.Fatalf("LoadRunRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].RunID != record.RunID {
+		t.Fatalf("expected the run's record on disk, got %d records", len(records))
+	}
+}
+
+// TestEveryRunStatusIsExported keeps the public constants in step with the
+// statuses a RunRecord can carry.
+func TestEveryRunStatusIsExported(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+	statuses := map[string]bool{}
+	pkgs, err := parser.ParseDir(fset, filepath.Join("..", "..", "internal", "runner"), func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("parse runner: %v", err)
+	}
+	for _, f := range pkgs["runner"].Files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if typ, ok := vs.Type.(*ast.Ident); ok && typ.Name == "RunStatus" {
+					for _, name := range vs.Names {
+						statuses[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	if len(statuses) == 0 {
+		t.Fatal("found no RunStatus constants in internal/runner")
+	}
+
+	f, err := parser.ParseFile(fset, "vcrrun.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse vcrrun.go: %v", err)
+	}
+	exported := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		vs, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range vs.Names {
+			if i >= len(vs.Values) {
+				break
+			}
+			if sel, ok := vs.Values[i].(*ast.SelectorExpr); ok && sel.Sel.Name == name.Name {
+				exported[name.Name] = true
+			}
+		}
+		return true
+	})
+	for name := range statuses {
+		if !exported[name] {
+			t.Errorf("runner.%s has no vcrrun.%s", name, name)
+		}
+	}
+}